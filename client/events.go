@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// WatchEvents streams events about changes in the registry and calls handler for each of them,
+// until ctx is cancelled or the server closes the stream.
+// If eventTypes is empty, all events are streamed.
+func (c *Client) WatchEvents(ctx context.Context, eventTypes []types.EventType, handler func(*types.Event)) error {
+	u, _ := c.constructAPIEndpoint("/events")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	if len(eventTypes) > 0 {
+		names := make([]string, len(eventTypes))
+		for i, t := range eventTypes {
+			names[i] = string(t)
+		}
+		q := req.URL.Query()
+		q.Add("types", strings.Join(names, ","))
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	// parse the stream according to the server-sent events format:
+	// fields of an event are on separate lines and events are separated by a blank line.
+	// The event itself is fully contained in its data field, so all other fields are ignored.
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if d, ok := strings.CutPrefix(line, "data:"); ok {
+				data.WriteString(strings.TrimPrefix(d, " "))
+			}
+			continue
+		}
+		if data.Len() == 0 {
+			continue
+		}
+		var e types.Event
+		if err := json.Unmarshal([]byte(data.String()), &e); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		data.Reset()
+		handler(&e)
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read event stream: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ListToolCalls sends API request to list the most recent tool calls.
+// The calls can be filtered by tool and client name, empty values match all calls.
+// If limit is 0, the server's default limit applies.
+func (c *Client) ListToolCalls(tool, client string, limit int) ([]types.ToolCallRecord, error) {
+	u, _ := c.constructAPIEndpoint("/history")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	if tool != "" {
+		q.Add("tool", tool)
+	}
+	if client != "" {
+		q.Add("client", client)
+	}
+	if limit > 0 {
+		q.Add("limit", strconv.Itoa(limit))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var records []types.ToolCallRecord
+	if err := json.NewDecoder(resp.Body).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return records, nil
+}
+
+// GetToolCall sends API request to fetch a single tool call from the history.
+func (c *Client) GetToolCall(id uint) (*types.ToolCallRecord, error) {
+	u, _ := c.constructAPIEndpoint("/history/" + strconv.FormatUint(uint64(id), 10))
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var rec types.ToolCallRecord
+	if err := json.NewDecoder(resp.Body).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &rec, nil
+}
@@ -2,10 +2,13 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
@@ -85,3 +88,75 @@ func (c *Client) DeregisterServer(name string) error {
 	}
 	return nil
 }
+
+// GetServerLogs fetches the last n lines written to stderr by an MCP server.
+// If n is zero, all lines retained by the registry are returned.
+func (c *Client) GetServerLogs(name string, n int) ([]*types.ServerLogEntry, error) {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/logs")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	q := req.URL.Query()
+	q.Add("tail", strconv.Itoa(n))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var entries []*types.ServerLogEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return entries, nil
+}
+
+// FollowServerLogs streams the logs of an MCP server, starting with its last n lines.
+// handler is called for every line received.
+// This is a blocking call that returns when ctx is done or the registry ends the stream,
+// eg- because the server was deregistered.
+func (c *Client) FollowServerLogs(
+	ctx context.Context, name string, n int, handler func(*types.ServerLogEntry),
+) error {
+	u, _ := c.constructAPIEndpoint("/servers/" + name + "/logs")
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req = req.WithContext(ctx)
+	q := req.URL.Query()
+	q.Add("tail", strconv.Itoa(n))
+	q.Add("follow", "true")
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	for {
+		var e types.ServerLogEntry
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to decode log entry: %w", err)
+		}
+		handler(&e)
+	}
+}
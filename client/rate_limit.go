@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// SetRateLimit sends API request to create a rate limit or replace the existing one for the same scope and target.
+func (c *Client) SetRateLimit(r *types.RateLimit) (*types.RateLimit, error) {
+	u, _ := c.constructAPIEndpoint("/rate-limits")
+
+	body, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(http.MethodPut, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.RateLimit
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListRateLimits sends API request to list all rate limits along with their current usage.
+func (c *Client) ListRateLimits() ([]types.RateLimit, error) {
+	u, _ := c.constructAPIEndpoint("/rate-limits")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var limits []types.RateLimit
+	if err := json.NewDecoder(resp.Body).Decode(&limits); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return limits, nil
+}
+
+// DeleteRateLimit sends API request to delete the rate limit of the given scope and target.
+func (c *Client) DeleteRateLimit(scope types.RateLimitScope, target string) error {
+	u, _ := c.constructAPIEndpoint("/rate-limits")
+
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	q.Add("scope", string(scope))
+	q.Add("target", target)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
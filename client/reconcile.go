@@ -0,0 +1,83 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Reconcile sends API request to make the registry match a manifest.
+// If dryRun is true, the changes are only computed and not applied.
+// If prune is true, the objects not declared in the manifest are deleted.
+func (c *Client) Reconcile(m *types.Manifest, dryRun, prune bool) (*types.ReconcileResult, error) {
+	u, _ := c.constructAPIEndpoint("/reconcile")
+
+	body, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	q := req.URL.Query()
+	q.Add("dry_run", strconv.FormatBool(dryRun))
+	q.Add("prune", strconv.FormatBool(prune))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var result types.ReconcileResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
+
+// Export sends API request to get a manifest that declares the current state of the registry.
+// If omitSecrets is true, the bearer tokens and environment variable values of servers are replaced with
+// types.KeepCurrentSecret.
+func (c *Client) Export(omitSecrets bool) (*types.Manifest, error) {
+	u, _ := c.constructAPIEndpoint("/export")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	q.Add("omit_secrets", strconv.FormatBool(omitSecrets))
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var m types.Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &m, nil
+}
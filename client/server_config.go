@@ -0,0 +1,108 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// GetServerConfig sends API request to get the mode of the server and the settings currently in effect.
+func (c *Client) GetServerConfig() (*types.ServerConfig, error) {
+	u, _ := c.constructAPIEndpoint("/config")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var cfg types.ServerConfig
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &cfg, nil
+}
+
+// UpdateServerConfig sends API request to change the settings that are set in the input
+// and to revert the ones it lists as unset.
+// The changes are applied immediately and it returns the resulting configuration.
+func (c *Client) UpdateServerConfig(input *types.UpdateServerSettingsInput) (*types.ServerConfig, error) {
+	u, _ := c.constructAPIEndpoint("/config")
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(http.MethodPut, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var cfg types.ServerConfig
+	if err := json.NewDecoder(resp.Body).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &cfg, nil
+}
+
+// SwitchToProd sends API request to switch a server running in development mode to production mode.
+// confirmationToken is the token printed to the logs of the server when it started.
+// If mcpClient is not empty, an MCP client with access to all registered MCP servers is created as well.
+func (c *Client) SwitchToProd(confirmationToken, mcpClient string) (*types.SwitchToProdResponse, error) {
+	u, _ := c.constructAPIEndpoint("/config/switch-to-prod")
+
+	body, err := json.Marshal(&types.SwitchToProdInput{ConfirmationToken: confirmationToken, McpClient: mcpClient})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var switchResp types.SwitchToProdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&switchResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &switchResp, nil
+}
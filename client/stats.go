@@ -0,0 +1,59 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// StatsFilter narrows down the tool calls included in the statistics.
+// Empty fields match all calls.
+type StatsFilter struct {
+	Server string
+	Tool   string
+	Client string
+}
+
+// GetStats sends API request to get the tool call statistics since the given period (eg- "7d", "12h"),
+// grouped by tool, server, client or day.
+func (c *Client) GetStats(by types.StatsGrouping, since string, filter StatsFilter) (*types.StatsResponse, error) {
+	u, _ := c.constructAPIEndpoint("/stats")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	q.Add("by", string(by))
+	q.Add("since", since)
+	if filter.Server != "" {
+		q.Add("server", filter.Server)
+	}
+	if filter.Tool != "" {
+		q.Add("tool", filter.Tool)
+	}
+	if filter.Client != "" {
+		q.Add("client", filter.Client)
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var stats types.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &stats, nil
+}
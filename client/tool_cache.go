@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// SetToolCachePolicy sends API request to enable caching for a tool or change its cache settings.
+func (c *Client) SetToolCachePolicy(p *types.ToolCachePolicy) error {
+	u, _ := c.constructAPIEndpoint("/tool-cache")
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newRequest(http.MethodPut, u, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ListToolCachePolicies sends API request to list all tools that have caching enabled.
+func (c *Client) ListToolCachePolicies() ([]types.ToolCachePolicy, error) {
+	u, _ := c.constructAPIEndpoint("/tool-cache")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var policies []types.ToolCachePolicy
+	if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return policies, nil
+}
+
+// DeleteToolCachePolicy sends API request to disable caching for a tool.
+func (c *Client) DeleteToolCachePolicy(tool string) error {
+	u, _ := c.constructAPIEndpoint("/tool-cache")
+
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	q.Add("tool", tool)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ClearToolCache sends API request to remove all cached results of a tool.
+// It returns the number of results that were removed.
+func (c *Client) ClearToolCache(tool string) (int, error) {
+	u, _ := c.constructAPIEndpoint("/tool-cache/clear")
+
+	req, err := c.newRequest(http.MethodPost, u, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	q := req.URL.Query()
+	q.Add("tool", tool)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var clearResp types.ClearToolCacheResponse
+	if err := json.NewDecoder(resp.Body).Decode(&clearResp); err != nil {
+		return 0, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return clearResp.Cleared, nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// CreateWebhook sends API request to create a webhook.
+// The returned webhook contains its secret, which cannot be retrieved later.
+func (c *Client) CreateWebhook(w *types.Webhook) (*types.Webhook, error) {
+	u, _ := c.constructAPIEndpoint("/webhooks")
+
+	body, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newRequest(http.MethodPost, u, bytes.NewBuffer(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var created types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &created, nil
+}
+
+// ListWebhooks sends API request to list all webhooks.
+func (c *Client) ListWebhooks() ([]types.Webhook, error) {
+	u, _ := c.constructAPIEndpoint("/webhooks")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var webhooks []types.Webhook
+	if err := json.NewDecoder(resp.Body).Decode(&webhooks); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return webhooks, nil
+}
+
+// DeleteWebhook sends API request to delete a webhook.
+func (c *Client) DeleteWebhook(name string) error {
+	u, _ := c.constructAPIEndpoint("/webhooks/" + name)
+
+	req, err := c.newRequest(http.MethodDelete, u, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ListWebhookDeliveries sends API request to list the most recent deliveries of events to a webhook.
+// If limit is 0, the server's default limit applies.
+func (c *Client) ListWebhookDeliveries(name string, limit int) ([]types.WebhookDelivery, error) {
+	u, _ := c.constructAPIEndpoint("/webhooks/" + name + "/deliveries")
+
+	req, err := c.newRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request to %s: %w", u, err)
+	}
+	if limit > 0 {
+		q := req.URL.Query()
+		q.Add("limit", strconv.Itoa(limit))
+		req.URL.RawQuery = q.Encode()
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", u, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("request failed with status: %d, message: %s", resp.StatusCode, body)
+	}
+
+	var deliveries []types.WebhookDelivery
+	if err := json.NewDecoder(resp.Body).Decode(&deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Administer the mcpjungle server",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "21",
+	},
+}
+
+var adminBackupCmd = &cobra.Command{
+	Use:   "backup",
+	Args:  cobra.NoArgs,
+	Short: "Take a backup of the registry",
+	Long: "Take a backup of everything in the registry: MCP servers and their tools, tool groups, MCP clients, users,\n" +
+		"rate limits, tool cache policies, webhooks and the server settings.\n" +
+		"The backup is taken through the API, so it does not depend on the database used by the server\n" +
+		"and can be restored by a server backed by another database.\n\n" +
+		"The backup contains secrets like access tokens and bearer tokens, store it securely.\n" +
+		"Tool call history, statistics, quota usage and webhook deliveries are not included.",
+	RunE: runAdminBackup,
+}
+
+var adminRestoreCmd = &cobra.Command{
+	Use:   "restore <backup file>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Restore the registry from a backup",
+	Long: "Replace everything in the registry with the contents of a backup taken by `admin backup`.\n" +
+		"The backup is validated before anything is changed. It can only be restored by a server that runs\n" +
+		"in the same mode and whose database schema has the same version as the server the backup was taken from.\n\n" +
+		"Access tokens are restored from the backup, so the token you are logged in with may no longer be valid.",
+	RunE: runAdminRestore,
+}
+
+var adminBackupCmdOut string
+
+func init() {
+	adminBackupCmd.Flags().StringVar(
+		&adminBackupCmdOut,
+		"out",
+		"",
+		"file to write the backup to, the backup is written to the standard output if not given",
+	)
+
+	adminCmd.AddCommand(adminBackupCmd)
+	adminCmd.AddCommand(adminRestoreCmd)
+
+	rootCmd.AddCommand(adminCmd)
+}
+
+func runAdminBackup(cmd *cobra.Command, args []string) error {
+	data, err := apiClient.Backup()
+	if err != nil {
+		return fmt.Errorf("failed to take backup: %w", err)
+	}
+	var header types.BackupHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("server returned an invalid backup: %w", err)
+	}
+
+	if adminBackupCmdOut == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	// the backup contains secrets, so only the current user may read it
+	if err := os.WriteFile(adminBackupCmdOut, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write backup to %s: %w", adminBackupCmdOut, err)
+	}
+	cmd.Printf(
+		"Saved backup of the registry to %s (schema version %d)\n", adminBackupCmdOut, header.SchemaVersion,
+	)
+	return nil
+}
+
+func runAdminRestore(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read backup: %w", err)
+	}
+	var header types.BackupHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("failed to parse backup %s: %w", args[0], err)
+	}
+	if header.SchemaVersion == 0 {
+		return fmt.Errorf("%s is not a backup taken by `admin backup`: schema version is missing", args[0])
+	}
+
+	result, err := apiClient.Restore(data)
+	if err != nil {
+		return fmt.Errorf("failed to restore backup: %w", err)
+	}
+
+	cmd.Printf("Restored the registry from %s (schema version %d)\n\n", args[0], header.SchemaVersion)
+	cmd.Printf("MCP servers:         %d\n", result.McpServers)
+	cmd.Printf("Tools:               %d\n", result.Tools)
+	cmd.Printf("Tool groups:         %d\n", result.ToolGroups)
+	cmd.Printf("MCP clients:         %d\n", result.McpClients)
+	cmd.Printf("Users:               %d\n", result.Users)
+	cmd.Printf("Rate limits:         %d\n", result.RateLimits)
+	cmd.Printf("Tool cache policies: %d\n", result.ToolCachePolicies)
+	cmd.Printf("Webhooks:            %d\n", result.Webhooks)
+	cmd.Print("\nAccess tokens were restored from the backup, log in again if your token is no longer valid.\n")
+	return nil
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	applyCmdFile   string
+	applyCmdDryRun bool
+	applyCmdPrune  bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <manifest>",
+	Short: "Make the registry match a manifest",
+	Long: "Make the registry match a YAML or JSON manifest that declares MCP servers, disabled tools, tool groups,\n" +
+		"MCP clients and users (the last two only in production mode).\n\n" +
+		"Objects declared in the manifest are created or updated as needed. Objects that are not declared\n" +
+		"are left untouched, unless --prune is given, in which case they are deleted (admin users are never deleted).\n" +
+		"Use --dry-run to review the changes before applying them.",
+	Args: cobra.NoArgs,
+	RunE: runApply,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "14",
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVarP(&applyCmdFile, "file", "f", "", "path to the manifest file (YAML or JSON), or - to read it from the standard input")
+	_ = applyCmd.MarkFlagRequired("file")
+	applyCmd.Flags().BoolVar(
+		&applyCmdDryRun,
+		"dry-run",
+		false,
+		"only show the changes needed to make the registry match the manifest, without applying them",
+	)
+	applyCmd.Flags().BoolVar(
+		&applyCmdPrune,
+		"prune",
+		false,
+		"delete the servers, tool groups, MCP clients and users that are not declared in the manifest",
+	)
+	rootCmd.AddCommand(applyCmd)
+}
+
+// readManifest reads a manifest from a YAML or JSON file, or from the standard input if the path is "-".
+// Unknown fields are rejected so that typos don't silently leave parts of the manifest unapplied.
+func readManifest(path string) (*types.Manifest, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file %s: %w", path, err)
+	}
+
+	// JSON is valid YAML, so both formats are decoded as YAML and then converted to JSON,
+	// which lets the manifest types be defined once with their json tags.
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file %s: %w", path, err)
+	}
+	if doc == nil {
+		return nil, fmt.Errorf("manifest file %s is empty", path)
+	}
+	jsonData, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file %s: %w", path, err)
+	}
+
+	var m types.Manifest
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return nil, fmt.Errorf("invalid manifest file %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+func runApply(cmd *cobra.Command, args []string) error {
+	m, err := readManifest(applyCmdFile)
+	if err != nil {
+		return err
+	}
+	return applyManifest(cmd, m, applyCmdDryRun, applyCmdPrune)
+}
+
+// applyManifest makes the registry match a manifest and prints the changes.
+// It returns an error if any of the changes failed.
+func applyManifest(cmd *cobra.Command, m *types.Manifest, dryRun, prune bool) error {
+	result, err := apiClient.Reconcile(m, dryRun, prune)
+	if err != nil {
+		return fmt.Errorf("failed to apply manifest: %w", err)
+	}
+
+	if len(result.Changes) == 0 {
+		cmd.Println("The registry already matches the manifest, nothing to do")
+		return nil
+	}
+	printReconcileChanges(cmd, result.Changes)
+
+	cmd.Println()
+	if result.DryRun {
+		cmd.Printf("%d changes would be applied, run without --dry-run to apply them\n", len(result.Changes))
+		return nil
+	}
+	failed := result.Failed()
+	if failed > 0 {
+		return fmt.Errorf("failed to apply %d of %d changes", failed, len(result.Changes))
+	}
+	cmd.Printf("Applied %d changes\n", len(result.Changes))
+	return nil
+}
+
+// printReconcileChanges prints changes as a diff: "+" for objects that are created or enabled,
+// "-" for objects that are deleted or disabled and "~" for objects that are updated.
+func printReconcileChanges(cmd *cobra.Command, changes []types.ReconcileChange) {
+	printedToken := false
+	for _, c := range changes {
+		symbol := "~"
+		switch c.Action {
+		case types.ReconcileCreate, types.ReconcileEnable:
+			symbol = "+"
+		case types.ReconcileDelete, types.ReconcileDisable:
+			symbol = "-"
+		}
+
+		line := fmt.Sprintf("%s %s %s %s", symbol, c.Action, c.Kind, c.Name)
+		if c.Details != "" {
+			line += ": " + c.Details
+		}
+		cmd.Println(line)
+
+		if c.AccessToken != "" {
+			cmd.Printf("    access token: %s\n", c.AccessToken)
+			printedToken = true
+		}
+		if c.Error != "" {
+			cmd.Printf("    error: %s\n", c.Error)
+		}
+	}
+	if printedToken {
+		cmd.Println("\nAccess tokens are only shown once, store them securely.")
+	}
+}
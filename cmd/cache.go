@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the result caches of tools",
+	Long: "mcpjungle can cache the results of tools that always return the same result for the same arguments,\n" +
+		"eg- documentation lookups. Caching is disabled by default and must be enabled for each tool.\n" +
+		"Results are cached in memory, keyed by the tool name and its arguments. Error results are never cached.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "9",
+	},
+}
+
+var cacheEnableCmd = &cobra.Command{
+	Use:   "enable <tool>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Enable caching of a tool's results",
+	Long: "Enable caching of a tool's results or change the settings of its cache.\n" +
+		"Only enable caching for tools whose results don't change between calls with the same arguments.",
+	RunE: runCacheEnable,
+}
+
+var cacheDisableCmd = &cobra.Command{
+	Use:   "disable <tool>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Disable caching of a tool's results and remove its cached results",
+	RunE:  runCacheDisable,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear <tool>",
+	Args:  cobra.ExactArgs(1),
+	Short: "Remove all cached results of a tool",
+	Long:  "Remove all cached results of a tool. Caching remains enabled for the tool.",
+	RunE:  runCacheClear,
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the tools that have caching enabled",
+	RunE:  runCacheList,
+}
+
+var (
+	cacheEnableCmdTTL        string
+	cacheEnableCmdMaxEntries int
+)
+
+func init() {
+	cacheEnableCmd.Flags().StringVar(
+		&cacheEnableCmdTTL,
+		"ttl",
+		"5m",
+		"duration for which a cached result is served",
+	)
+	cacheEnableCmd.Flags().IntVar(
+		&cacheEnableCmdMaxEntries,
+		"max-entries",
+		1000,
+		"maximum number of results cached for the tool, the least recently used result is evicted beyond that",
+	)
+
+	cacheCmd.AddCommand(cacheEnableCmd)
+	cacheCmd.AddCommand(cacheDisableCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	cacheCmd.AddCommand(cacheListCmd)
+
+	rootCmd.AddCommand(cacheCmd)
+}
+
+func runCacheEnable(cmd *cobra.Command, args []string) error {
+	p := &types.ToolCachePolicy{
+		Tool:       args[0],
+		TTL:        cacheEnableCmdTTL,
+		MaxEntries: cacheEnableCmdMaxEntries,
+	}
+	if err := apiClient.SetToolCachePolicy(p); err != nil {
+		return fmt.Errorf("failed to enable caching: %w", err)
+	}
+	cmd.Printf("Caching enabled for tool %s (ttl: %s, max entries: %d)\n", p.Tool, p.TTL, p.MaxEntries)
+	return nil
+}
+
+func runCacheDisable(cmd *cobra.Command, args []string) error {
+	if err := apiClient.DeleteToolCachePolicy(args[0]); err != nil {
+		return fmt.Errorf("failed to disable caching: %w", err)
+	}
+	cmd.Printf("Caching disabled for tool %s\n", args[0])
+	return nil
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	n, err := apiClient.ClearToolCache(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to clear the cache: %w", err)
+	}
+	cmd.Printf("Removed %d cached results of tool %s\n", n, args[0])
+	return nil
+}
+
+func runCacheList(cmd *cobra.Command, args []string) error {
+	policies, err := apiClient.ListToolCachePolicies()
+	if err != nil {
+		return fmt.Errorf("failed to list tool caches: %w", err)
+	}
+
+	if len(policies) == 0 {
+		cmd.Println("Caching is not enabled for any tool")
+		return nil
+	}
+	for i, p := range policies {
+		cmd.Printf("%d. %s\n", i+1, p.Tool)
+		cmd.Printf("TTL: %s, max entries: %d\n", p.TTL, p.MaxEntries)
+		cmd.Printf("Cached results: %d, hits: %d, misses: %d\n", p.Entries, p.Hits, p.Misses)
+
+		if i < len(policies)-1 {
+			cmd.Println()
+		}
+	}
+	return nil
+}
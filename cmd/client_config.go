@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// clientConfigFormatCodex is the format of the config file of the OpenAI Codex CLI.
+// Unlike the other formats, it can only be generated, not imported.
+const clientConfigFormatCodex = "codex"
+
+var (
+	clientConfigCmdFor    string
+	clientConfigCmdGroup  string
+	clientConfigCmdClient string
+	clientConfigCmdToken  string
+)
+
+var clientConfigCmd = &cobra.Command{
+	Use:   "client-config",
+	Short: "Print the configuration for connecting an MCP client to mcpjungle",
+	Long: "Print the configuration block that connects Claude Desktop, Cursor, VS Code or Codex to the MCPJungle MCP Proxy.\n" +
+		"The configuration points at the /mcp endpoint, or at the endpoint of a tool group if --group is given.\n\n" +
+		"In production mode, MCP clients must send their access token. Pass it with --token to fill in the\n" +
+		"'Authorization: Bearer <token>' header. If only --client is given, a placeholder is used instead.\n\n" +
+		"Example:\n" +
+		"    mcpjungle client-config --for cursor --group coding --client cursor --token <access token>",
+	Args: cobra.NoArgs,
+	RunE: runClientConfig,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "18",
+	},
+}
+
+func init() {
+	clientConfigCmd.Flags().StringVar(
+		&clientConfigCmdFor,
+		"for",
+		"",
+		fmt.Sprintf(
+			"MCP client to generate the configuration for ('%s' | '%s' | '%s' | '%s')",
+			clientConfigFormatClaude, clientConfigFormatCursor, clientConfigFormatVSCode, clientConfigFormatCodex,
+		),
+	)
+	_ = clientConfigCmd.MarkFlagRequired("for")
+	clientConfigCmd.Flags().StringVar(
+		&clientConfigCmdGroup,
+		"group",
+		"",
+		"connect to the MCP server of this tool group instead of all tools",
+	)
+	clientConfigCmd.Flags().StringVar(
+		&clientConfigCmdClient,
+		"client",
+		"",
+		"name of the MCP client in mcpjungle whose access token is used (production mode)",
+	)
+	clientConfigCmd.Flags().StringVar(
+		&clientConfigCmdToken,
+		"token",
+		"",
+		"access token of the MCP client (production mode)",
+	)
+	rootCmd.AddCommand(clientConfigCmd)
+}
+
+// clientConfigSnippet returns the configuration block that connects the given MCP client to an mcpjungle endpoint.
+// If token is not empty, it is sent in the Authorization header.
+func clientConfigSnippet(format, name, endpoint, token string) (string, error) {
+	var headers map[string]string
+	if token != "" {
+		headers = map[string]string{"Authorization": "Bearer " + token}
+	}
+
+	var f clientConfigFile
+	switch format {
+	case clientConfigFormatClaude:
+		// Claude Desktop only supports stdio servers in its config file, so it connects through mcp-remote
+		s := clientServerConfig{Command: "npx", Args: []string{"mcp-remote", endpoint}}
+		if strings.HasPrefix(endpoint, "http://") {
+			s.Args = append(s.Args, "--allow-http")
+		}
+		if token != "" {
+			// the header is passed through an environment variable because Claude Desktop on Windows
+			// doesn't handle spaces inside arguments
+			s.Args = append(s.Args, "--header", "Authorization:${AUTH_HEADER}")
+			s.Env = map[string]string{"AUTH_HEADER": "Bearer " + token}
+		}
+		f.McpServers = map[string]clientServerConfig{name: s}
+	case clientConfigFormatCursor:
+		f.McpServers = map[string]clientServerConfig{name: {URL: endpoint, Headers: headers}}
+	case clientConfigFormatVSCode:
+		f.Servers = map[string]clientServerConfig{name: {Type: "http", URL: endpoint, Headers: headers}}
+	case clientConfigFormatCodex:
+		var b strings.Builder
+		fmt.Fprintf(&b, "[mcp_servers.%s]\n", tomlKey(name))
+		fmt.Fprintf(&b, "url = %s\n", strconv.Quote(endpoint))
+		if token != "" {
+			fmt.Fprintf(&b, "http_headers = { \"Authorization\" = %s }\n", strconv.Quote("Bearer "+token))
+		}
+		return b.String(), nil
+	default:
+		return "", fmt.Errorf(
+			"unsupported MCP client '%s': must be one of %s, %s, %s or %s",
+			format, clientConfigFormatClaude, clientConfigFormatCursor, clientConfigFormatVSCode, clientConfigFormatCodex,
+		)
+	}
+
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetIndent("", "  ")
+	// keep placeholders like <access token of client> readable
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(f); err != nil {
+		return "", fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	return b.String(), nil
+}
+
+// tomlKey returns name as a TOML key, quoting it unless it is a valid bare key.
+func tomlKey(name string) string {
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return strconv.Quote(name)
+		}
+	}
+	return name
+}
+
+func runClientConfig(cmd *cobra.Command, args []string) error {
+	// fail before contacting the registry if the MCP client is not supported
+	if _, err := clientConfigSnippet(clientConfigCmdFor, "", "", ""); err != nil {
+		return err
+	}
+
+	name := "mcpjungle"
+	endpoint, err := url.JoinPath(registryServerURL, "/mcp")
+	if err != nil {
+		return fmt.Errorf("invalid registry URL %s: %w", registryServerURL, err)
+	}
+	if clientConfigCmdGroup != "" {
+		group, err := apiClient.GetToolGroup(clientConfigCmdGroup)
+		if err != nil {
+			return fmt.Errorf("failed to get tool group %s: %w", clientConfigCmdGroup, err)
+		}
+		name += "-" + clientConfigCmdGroup
+		endpoint = group.Endpoint
+	}
+
+	token := clientConfigCmdToken
+	if clientConfigCmdClient != "" {
+		clients, err := apiClient.ListMcpClients()
+		if err != nil {
+			return fmt.Errorf("failed to list MCP clients: %w", err)
+		}
+		if !slices.ContainsFunc(clients, func(c types.McpClient) bool { return c.Name == clientConfigCmdClient }) {
+			return fmt.Errorf("MCP client %s does not exist", clientConfigCmdClient)
+		}
+		if token == "" {
+			// access tokens are only shown when a client is created, so they can't be looked up
+			token = fmt.Sprintf("<access token of %s>", clientConfigCmdClient)
+			cmd.Print("Replace the placeholder below with the access token of the MCP client, or pass it with --token\n\n")
+		}
+	}
+
+	snippet, err := clientConfigSnippet(clientConfigCmdFor, name, endpoint, token)
+	if err != nil {
+		return err
+	}
+	fmt.Print(snippet)
+	return nil
+}
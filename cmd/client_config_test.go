@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"testing"
+)
+
+func TestClientConfigSnippet(t *testing.T) {
+	testCases := []struct {
+		format   string
+		endpoint string
+		token    string
+		expect   string
+	}{
+		{
+			format:   clientConfigFormatCursor,
+			endpoint: "https://jungle.example.com/mcp",
+			expect: `{
+  "mcpServers": {
+    "mcpjungle": {
+      "url": "https://jungle.example.com/mcp"
+    }
+  }
+}
+`,
+		},
+		{
+			format:   clientConfigFormatVSCode,
+			endpoint: "https://jungle.example.com/v0/groups/coding/mcp",
+			token:    "abc",
+			expect: `{
+  "servers": {
+    "mcpjungle": {
+      "type": "http",
+      "url": "https://jungle.example.com/v0/groups/coding/mcp",
+      "headers": {
+        "Authorization": "Bearer abc"
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			format:   clientConfigFormatClaude,
+			endpoint: "http://localhost:8080/mcp",
+			token:    "abc",
+			expect: `{
+  "mcpServers": {
+    "mcpjungle": {
+      "command": "npx",
+      "args": [
+        "mcp-remote",
+        "http://localhost:8080/mcp",
+        "--allow-http",
+        "--header",
+        "Authorization:${AUTH_HEADER}"
+      ],
+      "env": {
+        "AUTH_HEADER": "Bearer abc"
+      }
+    }
+  }
+}
+`,
+		},
+		{
+			format:   clientConfigFormatCodex,
+			endpoint: "http://localhost:8080/mcp",
+			token:    "abc",
+			expect: `[mcp_servers.mcpjungle]
+url = "http://localhost:8080/mcp"
+http_headers = { "Authorization" = "Bearer abc" }
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := clientConfigSnippet(tc.format, "mcpjungle", tc.endpoint, tc.token)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.expect {
+				t.Errorf("clientConfigSnippet() = %s, want %s", got, tc.expect)
+			}
+		})
+	}
+
+	if _, err := clientConfigSnippet("zed", "mcpjungle", "http://localhost:8080/mcp", ""); err == nil {
+		t.Error("expected an error for an unsupported MCP client")
+	}
+}
@@ -53,6 +53,49 @@ var createToolGroupCmd = &cobra.Command{
 	RunE: runCreateToolGroup,
 }
 
+var createRateLimitCmd = &cobra.Command{
+	Use:   "rate-limit [client|server|tool] [name]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Limit the tool calls of an MCP client, server or tool",
+	Long: "Create a rate limit and/or quota for tool calls made through the MCPJungle MCP Proxy.\n" +
+		"A limit can apply to all calls made by an MCP client, all calls made to an MCP server or all calls made to a single tool.\n" +
+		"Calls that exceed a limit are rejected with an error telling the client when to retry.\n" +
+		"Quotas are counted per day and per calendar month (UTC) and are preserved across restarts of mcpjungle.\n" +
+		"If a limit already exists for the client, server or tool, it is replaced.\n\n" +
+		"Examples:\n" +
+		"    mcpjungle create rate-limit client my-agent --rpm 30\n" +
+		"    mcpjungle create rate-limit tool brave-search__search --daily 1000 --monthly 20000",
+	RunE: runCreateRateLimit,
+}
+
+var createWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Send events about changes in mcpjungle to an HTTP endpoint",
+	Long: "Create a webhook that receives events, eg- when an MCP server is registered or becomes unhealthy,\n" +
+		"a tool is removed or a quota is exhausted.\n" +
+		"Every event is POSTed as JSON and signed with the webhook's secret: the X-MCPJungle-Signature-256 header\n" +
+		"contains 'sha256=' followed by the hex-encoded HMAC-SHA256 of the request body.\n" +
+		"Failed deliveries are retried with exponential backoff.\n\n" +
+		"Available event types: " + eventTypesHelp() + "\n\n" +
+		"Example:\n" +
+		"    mcpjungle create webhook ops --url https://example.com/hooks/mcpjungle --events server.unhealthy,quota.exceeded",
+	RunE: runCreateWebhook,
+}
+
+var (
+	createWebhookCmdURL    string
+	createWebhookCmdEvents string
+	createWebhookCmdSecret string
+)
+
+var (
+	createRateLimitCmdRequestsPerMinute int
+	createRateLimitCmdBurst             int
+	createRateLimitCmdDailyQuota        int64
+	createRateLimitCmdMonthlyQuota      int64
+)
+
 var (
 	createMcpClientCmdAllowedServers string
 	createMcpClientCmdDescription    string
@@ -84,9 +127,56 @@ func init() {
 	)
 	_ = createToolGroupCmd.MarkFlagRequired("conf")
 
+	createRateLimitCmd.Flags().IntVar(
+		&createRateLimitCmdRequestsPerMinute,
+		"rpm",
+		0,
+		"Maximum sustained number of tool calls per minute",
+	)
+	createRateLimitCmd.Flags().IntVar(
+		&createRateLimitCmdBurst,
+		"burst",
+		0,
+		"Maximum number of tool calls that can be made at once (defaults to the value of --rpm)",
+	)
+	createRateLimitCmd.Flags().Int64Var(
+		&createRateLimitCmdDailyQuota,
+		"daily",
+		0,
+		"Maximum number of tool calls per day",
+	)
+	createRateLimitCmd.Flags().Int64Var(
+		&createRateLimitCmdMonthlyQuota,
+		"monthly",
+		0,
+		"Maximum number of tool calls per calendar month",
+	)
+
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdURL,
+		"url",
+		"",
+		"URL that events are POSTed to",
+	)
+	_ = createWebhookCmd.MarkFlagRequired("url")
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdEvents,
+		"events",
+		"",
+		"Comma-separated list of event types to send to the webhook (default: all events)",
+	)
+	createWebhookCmd.Flags().StringVar(
+		&createWebhookCmdSecret,
+		"secret",
+		"",
+		"Secret used to sign the payloads (default: a random secret is generated)",
+	)
+
 	createCmd.AddCommand(createMcpClientCmd)
 	createCmd.AddCommand(createUserCmd)
 	createCmd.AddCommand(createToolGroupCmd)
+	createCmd.AddCommand(createRateLimitCmd)
+	createCmd.AddCommand(createWebhookCmd)
 
 	rootCmd.AddCommand(createCmd)
 }
@@ -181,3 +271,56 @@ func runCreateToolGroup(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runCreateRateLimit(cmd *cobra.Command, args []string) error {
+	r := &types.RateLimit{
+		Scope:             types.RateLimitScope(args[0]),
+		Target:            args[1],
+		RequestsPerMinute: createRateLimitCmdRequestsPerMinute,
+		Burst:             createRateLimitCmdBurst,
+		DailyQuota:        createRateLimitCmdDailyQuota,
+		MonthlyQuota:      createRateLimitCmdMonthlyQuota,
+	}
+	created, err := apiClient.SetRateLimit(r)
+	if err != nil {
+		return fmt.Errorf("failed to create rate limit: %w", err)
+	}
+
+	cmd.Printf("Rate limit for %s '%s' created successfully\n", created.Scope, created.Target)
+	printRateLimit(cmd, created)
+
+	return nil
+}
+
+func runCreateWebhook(cmd *cobra.Command, args []string) error {
+	w := &types.Webhook{
+		Name:   args[0],
+		URL:    createWebhookCmdURL,
+		Secret: createWebhookCmdSecret,
+	}
+	for _, e := range strings.Split(createWebhookCmdEvents, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			w.Events = append(w.Events, types.EventType(e))
+		}
+	}
+	created, err := apiClient.CreateWebhook(w)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	cmd.Printf("Webhook '%s' created successfully\n", created.Name)
+	if createWebhookCmdSecret == "" {
+		cmd.Println("Payloads are signed with the following secret, store it safely because it cannot be retrieved later:")
+		cmd.Printf("\n    %s\n\n", created.Secret)
+	}
+	return nil
+}
+
+// eventTypesHelp returns the list of event types for use in help texts.
+func eventTypesHelp() string {
+	names := make([]string, len(types.EventTypes))
+	for i, t := range types.EventTypes {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
 )
 
@@ -44,10 +45,29 @@ var deleteToolGroupCmd = &cobra.Command{
 	RunE: runDeleteToolGroup,
 }
 
+var deleteRateLimitCmd = &cobra.Command{
+	Use:   "rate-limit [client|server|tool] [name]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Delete the rate limit of an MCP client, server or tool",
+	Long: "Delete the rate limit and quotas of an MCP client, server or tool.\n" +
+		"The usage counted so far is kept, so re-creating a quota within the same day or month does not reset it.",
+	RunE: runDeleteRateLimit,
+}
+
+var deleteWebhookCmd = &cobra.Command{
+	Use:   "webhook [name]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Delete a webhook",
+	Long:  "Delete a webhook along with its delivery log. Events that are still being retried are not delivered anymore.",
+	RunE:  runDeleteWebhook,
+}
+
 func init() {
 	deleteCmd.AddCommand(deleteMcpClientCmd)
 	deleteCmd.AddCommand(deleteUserCmd)
 	deleteCmd.AddCommand(deleteToolGroupCmd)
+	deleteCmd.AddCommand(deleteRateLimitCmd)
+	deleteCmd.AddCommand(deleteWebhookCmd)
 
 	rootCmd.AddCommand(deleteCmd)
 }
@@ -78,3 +98,21 @@ func runDeleteToolGroup(cmd *cobra.Command, args []string) error {
 	cmd.Printf("Tool group '%s' deleted successfully!\n", name)
 	return nil
 }
+
+func runDeleteRateLimit(cmd *cobra.Command, args []string) error {
+	scope, target := types.RateLimitScope(args[0]), args[1]
+	if err := apiClient.DeleteRateLimit(scope, target); err != nil {
+		return fmt.Errorf("failed to delete the rate limit: %w", err)
+	}
+	cmd.Printf("Rate limit for %s '%s' deleted successfully!\n", scope, target)
+	return nil
+}
+
+func runDeleteWebhook(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := apiClient.DeleteWebhook(name); err != nil {
+		return fmt.Errorf("failed to delete the webhook: %w", err)
+	}
+	cmd.Printf("Webhook '%s' deleted successfully!\n", name)
+	return nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	exportCmdOutput      string
+	exportCmdOmitSecrets bool
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the registry as a manifest",
+	Long: "Print a manifest that declares the MCP servers, disabled tools, tool groups, MCP clients and users\n" +
+		"in the registry, eg- to move them to another mcpjungle server:\n\n" +
+		"    mcpjungle export > jungle.yaml\n" +
+		"    mcpjungle --registry <other server> import jungle.yaml\n\n" +
+		"The manifest includes the bearer tokens and environment variables of servers unless --omit-secrets is given,\n" +
+		"which replaces them with " + types.KeepCurrentSecret + " so that importing the manifest keeps the current ones.\n" +
+		"Access tokens are never exported, so MCP clients and users get new ones when the manifest is imported.",
+	Args: cobra.NoArgs,
+	RunE: runExport,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "15",
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVarP(&exportCmdOutput, "output", "o", "yaml", "output format, either yaml or json")
+	exportCmd.Flags().BoolVar(
+		&exportCmdOmitSecrets,
+		"omit-secrets",
+		false,
+		"leave out the bearer tokens and environment variable values of MCP servers",
+	)
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	if exportCmdOutput != "yaml" && exportCmdOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': must be either yaml or json", exportCmdOutput)
+	}
+
+	m, err := apiClient.Export(exportCmdOmitSecrets)
+	if err != nil {
+		return fmt.Errorf("failed to export the registry: %w", err)
+	}
+
+	var out []byte
+	if exportCmdOutput == "json" {
+		out, err = json.MarshalIndent(m, "", "  ")
+		if err == nil {
+			out = append(out, '\n')
+		}
+	} else {
+		out, err = toYAML(m)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to serialize manifest: %w", err)
+	}
+	// always written to stdout so that the manifest can be redirected to a file
+	fmt.Print(string(out))
+
+	if exportCmdOmitSecrets {
+		cmd.PrintErrln(
+			"Secrets were replaced with " + types.KeepCurrentSecret + ", which keeps the current secrets of registered servers." +
+				" Fill them in to register new servers from the manifest",
+		)
+	}
+	return nil
+}
+
+// toYAML serializes a value as block-style YAML, using its json tags.
+// Fields are kept in the order of the JSON document, which is the order they're declared in.
+func toYAML(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is valid YAML, so decoding it into a node preserves the order of the fields
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetYAMLStyle drops the flow style & quoting inherited from JSON, so that the YAML is formatted idiomatically.
+func resetYAMLStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		resetYAMLStyle(c)
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	historyCmdTool   string
+	historyCmdClient string
+	historyCmdLimit  int
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [call-id]",
+	Short: "Show the most recent tool calls",
+	Long: "Shows the most recent tool calls made through mcpjungle, newest first.\n" +
+		"If a call ID is given, the arguments and the full result of that call are shown.\n" +
+		"The registry retains a limited number of calls, see the --history-size option of the start command.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistory,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "10",
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVar(&historyCmdTool, "tool", "", "only show calls to this tool")
+	historyCmd.Flags().StringVar(&historyCmdClient, "client", "", "only show calls made by this MCP client")
+	historyCmd.Flags().IntVar(&historyCmdLimit, "limit", 20, "maximum number of calls to show")
+	rootCmd.AddCommand(historyCmd)
+}
+
+// parseCallID parses the ID of a tool call given on the command line.
+func parseCallID(s string) (uint, error) {
+	id, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid call ID '%s': must be a positive integer", s)
+	}
+	return uint(id), nil
+}
+
+// toIndentedJSON formats a value as indented JSON for display.
+func toIndentedJSON(v any) string {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		id, err := parseCallID(args[0])
+		if err != nil {
+			return err
+		}
+		rec, err := apiClient.GetToolCall(id)
+		if err != nil {
+			return fmt.Errorf("failed to get tool call: %w", err)
+		}
+		printToolCallDetails(cmd, rec)
+		return nil
+	}
+
+	records, err := apiClient.ListToolCalls(historyCmdTool, historyCmdClient, historyCmdLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list tool calls: %w", err)
+	}
+	if len(records) == 0 {
+		cmd.Println("No tool calls recorded")
+		return nil
+	}
+	for _, r := range records {
+		cmd.Printf(
+			"#%d  %s  %s  client: %s  %s (%dms)\n",
+			r.ID, r.Time.Local().Format(time.RFC3339), r.Tool, r.Client, r.Outcome, r.DurationMs,
+		)
+		if r.Arguments != nil {
+			args, _ := json.Marshal(r.Arguments)
+			cmd.Printf("    arguments: %s\n", args)
+		}
+		if r.Error != "" {
+			cmd.Printf("    error: %s\n", r.Error)
+		}
+	}
+	return nil
+}
+
+func printToolCallDetails(cmd *cobra.Command, r *types.ToolCallRecord) {
+	cmd.Printf("Call #%d to tool %s\n", r.ID, r.Tool)
+	cmd.Printf("Time: %s\n", r.Time.Local().Format(time.RFC3339))
+	cmd.Printf("Client: %s\n", r.Client)
+	cmd.Printf("Outcome: %s (%dms)\n", r.Outcome, r.DurationMs)
+	if r.Error != "" {
+		cmd.Printf("Error: %s\n", r.Error)
+	}
+	if r.Truncated {
+		cmd.Println("Note: parts of this call were too large to be recorded")
+	}
+
+	cmd.Println()
+	cmd.Println("Arguments:")
+	cmd.Println(toIndentedJSON(r.Arguments))
+	if r.Result != nil {
+		cmd.Println()
+		cmd.Println("Result:")
+		cmd.Println(toIndentedJSON(r.Result))
+	}
+}
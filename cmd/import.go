@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var importCmdDryRun bool
+
+var importCmd = &cobra.Command{
+	Use:   "import [manifest]",
+	Short: "Import a manifest created by export",
+	Long: "Create the MCP servers, tool groups, MCP clients and users declared in a manifest created by `export`,\n" +
+		"and enable or disable their tools accordingly. Objects that already exist are updated to match the manifest.\n" +
+		"Nothing is deleted, use `apply --prune` to also delete the objects that are not in the manifest.\n\n" +
+		"The manifest is read from the standard input if no file is given.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: runImport,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "16",
+	},
+}
+
+func init() {
+	importCmd.Flags().BoolVar(
+		&importCmdDryRun,
+		"dry-run",
+		false,
+		"only show the changes that importing the manifest would make, without applying them",
+	)
+	rootCmd.AddCommand(importCmd)
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	path := "-"
+	if len(args) == 1 {
+		path = args[0]
+	}
+	m, err := readManifest(path)
+	if err != nil {
+		return err
+	}
+	return applyManifest(cmd, m, importCmdDryRun, false)
+}
@@ -0,0 +1,237 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+// formats of the MCP configuration files of other MCP clients
+const (
+	clientConfigFormatClaude = "claude"
+	clientConfigFormatCursor = "cursor"
+	clientConfigFormatVSCode = "vscode"
+)
+
+var importConfigCmdFormat string
+
+var importConfigCmd = &cobra.Command{
+	Use:   "import-config <file>",
+	Short: "Register the MCP servers from a Claude Desktop, Cursor or VS Code config file",
+	Long: "Register the MCP servers declared in the MCP configuration file of another MCP client, eg-\n" +
+		"claude_desktop_config.json, .cursor/mcp.json or .vscode/mcp.json.\n\n" +
+		"Server names that aren't valid in mcpjungle are sanitized, eg- \"my server\" is registered as \"my-server\".\n" +
+		"Remote servers are registered with their URL, an 'Authorization: Bearer <token>' header is used as their\n" +
+		"bearer token. Other headers and SSE servers are not supported.\n\n" +
+		"The format of the file is detected automatically unless --format is given.",
+	Args: cobra.ExactArgs(1),
+	RunE: runImportConfig,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "17",
+	},
+}
+
+func init() {
+	importConfigCmd.Flags().StringVar(
+		&importConfigCmdFormat,
+		"format",
+		"",
+		fmt.Sprintf(
+			"format of the config file ('%s' | '%s' | '%s')",
+			clientConfigFormatClaude, clientConfigFormatCursor, clientConfigFormatVSCode,
+		),
+	)
+	rootCmd.AddCommand(importConfigCmd)
+}
+
+// clientServerConfig is an MCP server entry in the config file of Claude Desktop, Cursor or VS Code.
+type clientServerConfig struct {
+	// Type is only set by VS Code ("stdio", "http" or "sse") and some versions of Cursor
+	Type string `json:"type,omitempty"`
+
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// clientConfigFile is the MCP config file of Claude Desktop, Cursor or VS Code.
+type clientConfigFile struct {
+	// McpServers is used by Claude Desktop and Cursor
+	McpServers map[string]clientServerConfig `json:"mcpServers,omitempty"`
+	// Servers is used by VS Code
+	Servers map[string]clientServerConfig `json:"servers,omitempty"`
+}
+
+// parseClientConfig parses the MCP config file of another MCP client and returns its servers by name.
+// If format is empty, it is detected from the content of the file.
+func parseClientConfig(data []byte, format string) (map[string]clientServerConfig, error) {
+	var f clientConfigFile
+	// VS Code allows comments and trailing commas in its config files
+	if err := json.Unmarshal(stripJSONComments(data), &f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	switch format {
+	case clientConfigFormatClaude, clientConfigFormatCursor:
+		return f.McpServers, nil
+	case clientConfigFormatVSCode:
+		return f.Servers, nil
+	case "":
+		if f.McpServers != nil {
+			return f.McpServers, nil
+		}
+		if f.Servers != nil {
+			return f.Servers, nil
+		}
+		return nil, fmt.Errorf("config file contains neither an 'mcpServers' nor a 'servers' block")
+	default:
+		return nil, fmt.Errorf(
+			"unsupported format '%s': must be one of %s, %s or %s",
+			format, clientConfigFormatClaude, clientConfigFormatCursor, clientConfigFormatVSCode,
+		)
+	}
+}
+
+// toRegisterServerInput converts a server entry from another MCP client's config file into
+// the input for registering it in mcpjungle.
+func toRegisterServerInput(name string, c clientServerConfig) (*types.RegisterServerInput, error) {
+	sanitized, err := mcp.SanitizeServerName(name)
+	if err != nil {
+		return nil, err
+	}
+	input := &types.RegisterServerInput{Name: sanitized}
+
+	switch {
+	case c.Type == "sse":
+		return nil, fmt.Errorf("SSE servers are not supported")
+	case c.Command != "":
+		input.Transport = string(types.TransportStdio)
+		input.Command = c.Command
+		input.Args = c.Args
+		input.Env = c.Env
+	case c.URL != "":
+		input.Transport = string(types.TransportStreamableHTTP)
+		input.URL = c.URL
+		for k, v := range c.Headers {
+			token, ok := strings.CutPrefix(v, "Bearer ")
+			if !strings.EqualFold(k, "Authorization") || !ok {
+				return nil, fmt.Errorf(
+					"header '%s' is not supported, only an 'Authorization: Bearer <token>' header can be used", k,
+				)
+			}
+			input.BearerToken = token
+		}
+	default:
+		return nil, fmt.Errorf("server has neither a command nor a url")
+	}
+	return input, nil
+}
+
+// stripJSONComments removes // and /* */ comments as well as trailing commas from a JSON document.
+func stripJSONComments(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	inString := false
+	for i := 0; i < len(data); i++ {
+		ch := data[i]
+		if inString {
+			out = append(out, ch)
+			if ch == '\\' && i+1 < len(data) {
+				i++
+				out = append(out, data[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case ch == '"':
+			inString = true
+			out = append(out, ch)
+		case ch == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case ch == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case ch == '}' || ch == ']':
+			// drop a trailing comma before the closing bracket, ignoring the whitespace in between
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+func runImportConfig(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s: %w", args[0], err)
+	}
+	servers, err := parseClientConfig(data, importConfigCmdFormat)
+	if err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		cmd.Println("The config file does not contain any MCP servers")
+		return nil
+	}
+
+	registered := make(map[string]string)
+	failed := 0
+	for _, name := range slices.Sorted(maps.Keys(servers)) {
+		input, err := toRegisterServerInput(name, servers[name])
+		if err == nil {
+			if other, ok := registered[input.Name]; ok {
+				err = fmt.Errorf("its sanitized name %s is the same as that of server %s", input.Name, other)
+			}
+		}
+		if err == nil {
+			_, err = apiClient.RegisterServer(input)
+		}
+		if err != nil {
+			cmd.Printf("✗ %s: %v\n", name, err)
+			failed++
+			continue
+		}
+
+		registered[input.Name] = name
+		if input.Name != name {
+			cmd.Printf("✓ %s (registered as %s)\n", name, input.Name)
+		} else {
+			cmd.Printf("✓ %s\n", name)
+		}
+	}
+
+	cmd.Printf("\nRegistered %d of %d MCP servers\n", len(servers)-failed, len(servers))
+	if failed > 0 {
+		return fmt.Errorf("failed to register %d MCP servers", failed)
+	}
+	return nil
+}
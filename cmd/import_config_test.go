@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestParseClientConfig(t *testing.T) {
+	vscode := []byte(`{
+	// servers used by copilot
+	"servers": {
+		"github": {
+			"type": "http",
+			"url": "https://api.githubcopilot.com/mcp/", /* remote */
+			"headers": {"Authorization": "Bearer abc"},
+		},
+	},
+}`)
+	servers, err := parseClientConfig(vscode, "")
+	if err != nil {
+		t.Fatalf("failed to parse VS Code config: %v", err)
+	}
+	if len(servers) != 1 || servers["github"].URL != "https://api.githubcopilot.com/mcp/" {
+		t.Fatalf("unexpected servers: %+v", servers)
+	}
+
+	claude := []byte(`{"mcpServers": {"files": {"command": "npx", "args": ["-y", "server-filesystem", "//tmp"]}}}`)
+	servers, err = parseClientConfig(claude, clientConfigFormatClaude)
+	if err != nil {
+		t.Fatalf("failed to parse Claude config: %v", err)
+	}
+	if got := servers["files"].Args; !reflect.DeepEqual(got, []string{"-y", "server-filesystem", "//tmp"}) {
+		t.Errorf("comment markers inside strings must be kept, got args %q", got)
+	}
+
+	if _, err := parseClientConfig(claude, clientConfigFormatVSCode); err != nil {
+		t.Errorf("expected no error for a config without servers in the given format, got %v", err)
+	}
+	if _, err := parseClientConfig([]byte(`{}`), ""); err == nil {
+		t.Error("expected an error for a config without any servers block")
+	}
+	if _, err := parseClientConfig(claude, "zed"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
+
+func TestToRegisterServerInput(t *testing.T) {
+	testCases := []struct {
+		name      string
+		config    clientServerConfig
+		expect    *types.RegisterServerInput
+		expectErr bool
+	}{
+		{
+			name:   "my files",
+			config: clientServerConfig{Command: "npx", Args: []string{"server"}, Env: map[string]string{"K": "v"}},
+			expect: &types.RegisterServerInput{
+				Name:      "my-files",
+				Transport: string(types.TransportStdio),
+				Command:   "npx",
+				Args:      []string{"server"},
+				Env:       map[string]string{"K": "v"},
+			},
+		},
+		{
+			name: "github",
+			config: clientServerConfig{
+				Type:    "http",
+				URL:     "https://example.com/mcp",
+				Headers: map[string]string{"authorization": "Bearer abc"},
+			},
+			expect: &types.RegisterServerInput{
+				Name:        "github",
+				Transport:   string(types.TransportStreamableHTTP),
+				URL:         "https://example.com/mcp",
+				BearerToken: "abc",
+			},
+		},
+		{
+			name:      "custom-header",
+			config:    clientServerConfig{URL: "https://example.com/mcp", Headers: map[string]string{"X-Api-Key": "abc"}},
+			expectErr: true,
+		},
+		{
+			name:      "legacy",
+			config:    clientServerConfig{Type: "sse", URL: "https://example.com/sse"},
+			expectErr: true,
+		},
+		{
+			name:      "empty",
+			config:    clientServerConfig{},
+			expectErr: true,
+		},
+		{
+			name:      "***",
+			config:    clientServerConfig{Command: "npx"},
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := toRegisterServerInput(tc.name, tc.config)
+			if tc.expectErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("toRegisterServerInput() = %+v, want %+v", got, tc.expect)
+			}
+		})
+	}
+}
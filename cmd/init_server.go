@@ -8,11 +8,21 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var (
+	initServerCmdFromDev   bool
+	initServerCmdConfirm   string
+	initServerCmdMcpClient string
+)
+
 var initServerCmd = &cobra.Command{
 	Use:   "init-server",
 	Short: "Initialize the MCPJungle Server (for Production Mode only)",
 	Long: "If the MCPJungle Server was started in Production Mode, use this command to initialize the server.\n" +
-		"Initialization is required before you can use the server.\n",
+		"Initialization is required before you can use the server.\n\n" +
+		"A server that is already running in Development Mode can be switched to Production Mode with --from-dev.\n" +
+		"This requires the confirmation token that the server prints to its logs when it starts, given with --confirm.\n" +
+		"Its MCP servers, tools and tool groups are kept. Because MCP clients need an access token in Production Mode,\n" +
+		"--mcp-client creates an MCP client that can access all registered MCP servers.\n",
 	RunE: runInitServer,
 	Annotations: map[string]string{
 		"group": string(subCommandGroupAdvanced),
@@ -21,10 +31,35 @@ var initServerCmd = &cobra.Command{
 }
 
 func init() {
+	initServerCmd.Flags().BoolVar(
+		&initServerCmdFromDev,
+		"from-dev",
+		false,
+		"switch a server that is running in Development Mode to Production Mode",
+	)
+	initServerCmd.Flags().StringVar(
+		&initServerCmdConfirm,
+		"confirm",
+		"",
+		"confirmation token printed to the logs of the server when it started (only with --from-dev)",
+	)
+	initServerCmd.Flags().StringVar(
+		&initServerCmdMcpClient,
+		"mcp-client",
+		"",
+		"name of an MCP client to create with access to all registered MCP servers (only with --from-dev)",
+	)
 	rootCmd.AddCommand(initServerCmd)
 }
 
 func runInitServer(cmd *cobra.Command, args []string) error {
+	if initServerCmdFromDev {
+		return runSwitchToProd()
+	}
+	if initServerCmdMcpClient != "" || initServerCmdConfirm != "" {
+		return errors.New("--mcp-client and --confirm can only be used with --from-dev")
+	}
+
 	fmt.Println("Initializing the MCPJungle Server in Production Mode...")
 	resp, err := apiClient.InitServer()
 	if err != nil {
@@ -35,9 +70,41 @@ func runInitServer(cmd *cobra.Command, args []string) error {
 		return errors.New("server initialization failed: no admin access token received")
 	}
 
-	// Create new client configuration
+	if err := saveAdminAccessToken(resp.AdminAccessToken); err != nil {
+		return err
+	}
+	fmt.Println("All done!")
+	return nil
+}
+
+func runSwitchToProd() error {
+	if initServerCmdConfirm == "" {
+		return errors.New(
+			"--confirm is required, use the confirmation token printed to the logs of the server when it started",
+		)
+	}
+	fmt.Println("Switching the MCPJungle Server from Development Mode to Production Mode...")
+	resp, err := apiClient.SwitchToProd(initServerCmdConfirm, initServerCmdMcpClient)
+	if err != nil {
+		return fmt.Errorf("failed to switch the server to production mode: %w", err)
+	}
+
+	if err := saveAdminAccessToken(resp.AdminAccessToken); err != nil {
+		return err
+	}
+	if resp.McpClientAccessToken != "" {
+		fmt.Printf("Access token of MCP client %s: %s\n", initServerCmdMcpClient, resp.McpClientAccessToken)
+		fmt.Println("Your MCP clients should send this token in the `Authorization: Bearer {token}` HTTP header.")
+	}
+	fmt.Println("All done! From now on, the server runs in Production Mode, even after a restart.")
+	return nil
+}
+
+// saveAdminAccessToken saves the admin access token in the client configuration,
+// so that subsequent commands are authenticated.
+func saveAdminAccessToken(token string) error {
 	cfg := &config.ClientConfig{
-		AccessToken: resp.AdminAccessToken,
+		AccessToken: token,
 	}
 	if err := config.Save(cfg); err != nil {
 		return fmt.Errorf("failed to create client configuration: %w", err)
@@ -48,7 +115,5 @@ func runInitServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to get client configuration path: %w", err)
 	}
 	fmt.Println("Your Admin access token has been saved to", cfgPath)
-
-	fmt.Println("All done!")
 	return nil
 }
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
@@ -53,7 +54,35 @@ var listGroupsCmd = &cobra.Command{
 	RunE:  runListGroups,
 }
 
+var listRateLimitsCmd = &cobra.Command{
+	Use:   "rate-limits",
+	Short: "List rate limits and quotas along with their current usage",
+	RunE:  runListRateLimits,
+}
+
+var listWebhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "List webhooks",
+	RunE:  runListWebhooks,
+}
+
+var listWebhookDeliveriesCmd = &cobra.Command{
+	Use:   "webhook-deliveries [webhook]",
+	Args:  cobra.ExactArgs(1),
+	Short: "List the most recent deliveries of events to a webhook",
+	RunE:  runListWebhookDeliveries,
+}
+
+var listWebhookDeliveriesCmdLimit int
+
 func init() {
+	listWebhookDeliveriesCmd.Flags().IntVar(
+		&listWebhookDeliveriesCmdLimit,
+		"limit",
+		20,
+		"Maximum number of deliveries to show",
+	)
+
 	listToolsCmd.Flags().StringVar(
 		&listToolsCmdServerName,
 		"server",
@@ -66,6 +95,9 @@ func init() {
 	listCmd.AddCommand(listMcpClientsCmd)
 	listCmd.AddCommand(listUsersCmd)
 	listCmd.AddCommand(listGroupsCmd)
+	listCmd.AddCommand(listRateLimitsCmd)
+	listCmd.AddCommand(listWebhooksCmd)
+	listCmd.AddCommand(listWebhookDeliveriesCmd)
 
 	rootCmd.AddCommand(listCmd)
 }
@@ -129,6 +161,32 @@ func runListServers(cmd *cobra.Command, args []string) error {
 			}
 		}
 
+		if s.CallTimeout != "" {
+			fmt.Println("Call timeout: " + s.CallTimeout)
+		}
+		if s.MaxRetries != nil {
+			fmt.Printf("Max retries: %d\n", *s.MaxRetries)
+		}
+		if s.RetryBackoff != "" {
+			fmt.Println("Retry backoff: " + s.RetryBackoff)
+		}
+		if s.RetryOnTimeout != nil {
+			fmt.Printf("Retry on timeout: %t\n", *s.RetryOnTimeout)
+		}
+		if s.MaxConcurrency != nil {
+			fmt.Printf("Max concurrent calls: %d\n", *s.MaxConcurrency)
+		}
+		if s.MaxQueue != nil {
+			fmt.Printf("Max queued calls: %d\n", *s.MaxQueue)
+		}
+		if s.QueueTimeout != "" {
+			fmt.Println("Queue timeout: " + s.QueueTimeout)
+		}
+
+		if s.Status != nil {
+			printServerStatus(s.Status)
+		}
+
 		if i < len(servers)-1 {
 			fmt.Println()
 		}
@@ -137,6 +195,30 @@ func runListServers(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printServerStatus prints the runtime status of an MCP server as reported by the registry.
+func printServerStatus(st *types.McpServerStatus) {
+	if st.Health == types.ServerHealthUnknown {
+		fmt.Println("Health: unknown")
+	} else {
+		fmt.Printf("Health: %s (latency: %dms)\n", st.Health, st.LatencyMs)
+	}
+	if st.Health == types.ServerHealthUnhealthy {
+		fmt.Printf("Consecutive failed checks: %d, last error: %s\n", st.ConsecutiveFailures, st.LastError)
+		if st.LastSuccess != nil {
+			fmt.Println("Last healthy at: " + st.LastSuccess.Local().Format(time.RFC3339))
+		}
+	}
+	if st.CircuitBreaker == types.CircuitOpen || st.CircuitBreaker == types.CircuitHalfOpen {
+		fmt.Printf("Circuit breaker: %s (recent tool calls to this server have failed)\n", st.CircuitBreaker)
+	}
+	if st.ToolsDisabled {
+		fmt.Println("The tools of this server have been removed from the MCP proxy until it recovers")
+	}
+	if st.ActiveCalls > 0 || st.QueuedCalls > 0 {
+		fmt.Printf("Tool calls in progress: %d, waiting: %d\n", st.ActiveCalls, st.QueuedCalls)
+	}
+}
+
 func runListMcpClients(cmd *cobra.Command, args []string) error {
 	clients, err := apiClient.ListMcpClients()
 	if err != nil {
@@ -216,3 +298,91 @@ func runListGroups(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+func runListRateLimits(cmd *cobra.Command, args []string) error {
+	limits, err := apiClient.ListRateLimits()
+	if err != nil {
+		return fmt.Errorf("failed to list rate limits: %w", err)
+	}
+
+	if len(limits) == 0 {
+		cmd.Println("There are no rate limits in the registry")
+		return nil
+	}
+	for i := range limits {
+		r := &limits[i]
+		cmd.Printf("%d. %s %s\n", i+1, r.Scope, r.Target)
+		printRateLimit(cmd, r)
+
+		if i < len(limits)-1 {
+			cmd.Println()
+		}
+	}
+
+	return nil
+}
+
+// printRateLimit prints the limits configured in a rate limit, along with their usage where available.
+func printRateLimit(cmd *cobra.Command, r *types.RateLimit) {
+	if r.RequestsPerMinute > 0 {
+		cmd.Printf("Rate limit: %d calls per minute (burst %d)\n", r.RequestsPerMinute, r.Burst)
+	}
+	if r.DailyQuota > 0 {
+		cmd.Printf("Daily quota: %d of %d calls used\n", r.DailyUsage, r.DailyQuota)
+	}
+	if r.MonthlyQuota > 0 {
+		cmd.Printf("Monthly quota: %d of %d calls used\n", r.MonthlyUsage, r.MonthlyQuota)
+	}
+}
+
+func runListWebhooks(cmd *cobra.Command, args []string) error {
+	webhooks, err := apiClient.ListWebhooks()
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	if len(webhooks) == 0 {
+		cmd.Println("There are no webhooks in the registry")
+		return nil
+	}
+	for i, w := range webhooks {
+		cmd.Printf("%d. %s\n", i+1, w.Name)
+		cmd.Printf("URL: %s\n", w.URL)
+		if len(w.Events) == 0 {
+			cmd.Println("Events: all")
+		} else {
+			events := make([]string, len(w.Events))
+			for j, e := range w.Events {
+				events[j] = string(e)
+			}
+			cmd.Printf("Events: %s\n", strings.Join(events, ", "))
+		}
+
+		if i < len(webhooks)-1 {
+			cmd.Println()
+		}
+	}
+	return nil
+}
+
+func runListWebhookDeliveries(cmd *cobra.Command, args []string) error {
+	deliveries, err := apiClient.ListWebhookDeliveries(args[0], listWebhookDeliveriesCmdLimit)
+	if err != nil {
+		return fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	if len(deliveries) == 0 {
+		cmd.Println("No events have been sent to this webhook yet")
+		return nil
+	}
+	for _, d := range deliveries {
+		cmd.Printf(
+			"#%d  %s  %s  %s (attempts: %d)\n",
+			d.ID, d.Time.Local().Format(time.RFC3339), d.EventType, d.Status, d.Attempts,
+		)
+		if d.Error != "" {
+			cmd.Printf("    error: %s\n", d.Error)
+		}
+	}
+	return nil
+}
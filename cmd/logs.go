@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logsCmdFollow bool
+	logsCmdTail   int
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs <server>",
+	Short: "Show the logs of an MCP server",
+	Long: "Shows the most recent stderr output of a registered STDIO MCP server.\n" +
+		"The registry retains a limited number of lines for each server in memory.\n" +
+		"Streamable HTTP servers don't produce any logs in mcpjungle.",
+	Args: cobra.ExactArgs(1),
+	RunE: runGetServerLogs,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "8",
+	},
+}
+
+func init() {
+	logsCmd.Flags().BoolVarP(
+		&logsCmdFollow,
+		"follow",
+		"f",
+		false,
+		"keep streaming new log lines as they are written by the server",
+	)
+	logsCmd.Flags().IntVar(
+		&logsCmdTail,
+		"tail",
+		0,
+		"number of most recent lines to show (default: all retained lines)",
+	)
+	rootCmd.AddCommand(logsCmd)
+}
+
+func printServerLogEntry(e *types.ServerLogEntry) {
+	fmt.Printf("%s  %s\n", e.Time.Local().Format(time.RFC3339), e.Line)
+}
+
+func runGetServerLogs(cmd *cobra.Command, args []string) error {
+	if logsCmdTail < 0 {
+		return fmt.Errorf("--tail must not be negative")
+	}
+	name := args[0]
+
+	if logsCmdFollow {
+		if err := apiClient.FollowServerLogs(cmd.Context(), name, logsCmdTail, printServerLogEntry); err != nil {
+			return fmt.Errorf("failed to follow logs of server %s: %w", name, err)
+		}
+		return nil
+	}
+
+	entries, err := apiClient.GetServerLogs(name, logsCmdTail)
+	if err != nil {
+		return fmt.Errorf("failed to get logs of server %s: %w", name, err)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No logs found for server %s\n", name)
+		return nil
+	}
+	for _, e := range entries {
+		printServerLogEntry(e)
+	}
+	return nil
+}
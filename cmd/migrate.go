@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var (
+	migrateCmdDataDir   string
+	migrateDownCmdSteps int
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Manage the schema of the mcpjungle database",
+	Long: "Apply or revert the migrations of the database schema of the mcpjungle server.\n" +
+		"These commands connect to the database directly, using the same DATABASE_URL as the `start` command.\n\n" +
+		"The server doesn't migrate an existing database when it starts unless it is started with --auto-migrate,\n" +
+		"so run `mcpjungle migrate up` after upgrading mcpjungle.",
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "20",
+	},
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up",
+	Short: "Apply all pending migrations",
+	Long: "Apply all pending migrations.\n" +
+		"On MySQL, a migration that fails may be left partially applied, since MySQL can't roll back schema changes.\n" +
+		"Fix the cause of the failure and run `mcpjungle migrate up` again to complete it.",
+	Args: cobra.NoArgs,
+	RunE: runMigrateUp,
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down",
+	Short: "Revert the most recently applied migrations",
+	Long: "Revert the most recently applied migrations.\n" +
+		"Reverting a migration may delete data, eg- reverting the initial schema drops all tables.",
+	Args: cobra.NoArgs,
+	RunE: runMigrateDown,
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show which migrations have been applied",
+	Args:  cobra.NoArgs,
+	RunE:  runMigrateStatus,
+}
+
+func init() {
+	migrateCmd.PersistentFlags().StringVar(
+		&migrateCmdDataDir,
+		"data-dir",
+		"",
+		fmt.Sprintf(
+			"directory of the embedded SQLite database if %s is not set, same as for the `start` command\n"+
+				"(overrides env var %s)",
+			DBUrlEnvVar, DataDirEnvVar,
+		),
+	)
+	migrateDownCmd.Flags().IntVar(&migrateDownCmdSteps, "steps", 1, "number of migrations to revert")
+
+	migrateCmd.AddCommand(migrateUpCmd)
+	migrateCmd.AddCommand(migrateDownCmd)
+	migrateCmd.AddCommand(migrateStatusCmd)
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// connectDB connects to the database of the mcpjungle server.
+func connectDB() (*gorm.DB, error) {
+	_ = godotenv.Load()
+	return db.NewDBConnection(db.Config{
+		DSN:     os.Getenv(DBUrlEnvVar),
+		DataDir: dataDir(migrateCmdDataDir),
+	})
+}
+
+func runMigrateUp(cmd *cobra.Command, args []string) error {
+	dbConn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	applied, err := migrations.Up(dbConn)
+	for _, m := range applied {
+		fmt.Printf("Applied migration %d: %s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+	if len(applied) == 0 {
+		fmt.Println("The database schema is up to date")
+	}
+	return nil
+}
+
+func runMigrateDown(cmd *cobra.Command, args []string) error {
+	dbConn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	reverted, err := migrations.Down(dbConn, migrateDownCmdSteps)
+	for _, m := range reverted {
+		fmt.Printf("Reverted migration %d: %s\n", m.Version, m.Name)
+	}
+	return err
+}
+
+func runMigrateStatus(cmd *cobra.Command, args []string) error {
+	dbConn, err := connectDB()
+	if err != nil {
+		return err
+	}
+	status, err := migrations.Status(dbConn)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range status {
+		appliedAt := "pending"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <call-id>",
+	Short: "Replay a tool call from the history",
+	Long: "Invokes the tool of a past call again with the same arguments and shows how the result differs\n" +
+		"from the recorded one. Use the history command to find the ID of a call.",
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "11",
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	id, err := parseCallID(args[0])
+	if err != nil {
+		return err
+	}
+	rec, err := apiClient.GetToolCall(id)
+	if err != nil {
+		return fmt.Errorf("failed to get tool call: %w", err)
+	}
+	if rec.Truncated && rec.Arguments == nil {
+		return fmt.Errorf("the arguments of call #%d were too large to be recorded, it cannot be replayed", id)
+	}
+
+	// a failed call is represented by its error so that it can be compared with the new outcome as well
+	var recorded any = rec.Result
+	if rec.Result == nil {
+		recorded = map[string]string{"error": rec.Error}
+	}
+	var replayed any
+	result, err := apiClient.InvokeTool(rec.Tool, rec.Arguments)
+	if err != nil {
+		replayed = map[string]string{"error": err.Error()}
+	} else {
+		replayed = result
+	}
+
+	cmd.Printf("Replayed call #%d to tool %s\n\n", id, rec.Tool)
+	if rec.Truncated && rec.Result == nil {
+		cmd.Println("The recorded result was too large to be kept, so it cannot be compared. New result:")
+		cmd.Println(toIndentedJSON(replayed))
+		return nil
+	}
+
+	diff, changed := diffLines(
+		strings.Split(toIndentedJSON(recorded), "\n"),
+		strings.Split(toIndentedJSON(replayed), "\n"),
+	)
+	if !changed {
+		cmd.Println("The result is identical to the recorded one:")
+	} else {
+		cmd.Println("The result differs from the recorded one (- recorded, + new):")
+	}
+	for _, l := range diff {
+		cmd.Println(l)
+	}
+	return nil
+}
+
+// diffLines returns a line-by-line diff of a and b, based on their longest common subsequence.
+// Lines only in a are prefixed with "- ", lines only in b with "+ " and common lines with "  ".
+// It also reports whether a and b differ at all.
+func diffLines(a, b []string) ([]string, bool) {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := make([]string, 0, len(a)+len(b))
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			diff = append(diff, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			diff = append(diff, "- "+a[i])
+			i++
+			changed = true
+		default:
+			diff = append(diff, "+ "+b[j])
+			j++
+			changed = true
+		}
+	}
+	return diff, changed
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	testCases := []struct {
+		name        string
+		a, b        []string
+		expect      []string
+		expectDiffs bool
+	}{
+		{
+			name:   "identical",
+			a:      []string{"{", `  "text": "ok"`, "}"},
+			b:      []string{"{", `  "text": "ok"`, "}"},
+			expect: []string{"  {", `    "text": "ok"`, "  }"},
+		},
+		{
+			name:        "changed line",
+			a:           []string{"{", `  "text": "old"`, "}"},
+			b:           []string{"{", `  "text": "new"`, "}"},
+			expect:      []string{"  {", `-   "text": "old"`, `+   "text": "new"`, "  }"},
+			expectDiffs: true,
+		},
+		{
+			name:        "added and removed lines",
+			a:           []string{"a", "b", "c"},
+			b:           []string{"b", "c", "d"},
+			expect:      []string{"- a", "  b", "  c", "+ d"},
+			expectDiffs: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, changed := diffLines(tc.a, tc.b)
+			if !reflect.DeepEqual(got, tc.expect) {
+				t.Errorf("diffLines() = %q, want %q", got, tc.expect)
+			}
+			if changed != tc.expectDiffs {
+				t.Errorf("diffLines() changed = %v, want %v", changed, tc.expectDiffs)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serverConfigCmdCallTimeout    time.Duration
+	serverConfigCmdMaxRetries     int
+	serverConfigCmdRetryBackoff   time.Duration
+	serverConfigCmdMaxConcurrency int
+	serverConfigCmdMaxQueue       int
+	serverConfigCmdQueueTimeout   time.Duration
+	serverConfigCmdLogLevel       string
+	serverConfigCmdUnset          []string
+)
+
+var serverConfigCmd = &cobra.Command{
+	Use:   "server-config",
+	Short: "View or change the settings of the running mcpjungle server",
+	Long: "Show the mode of the mcpjungle server and the settings currently in effect.\n" +
+		"If any flags are given, the corresponding settings are changed first. Changes are applied immediately,\n" +
+		"without restarting the server, and are kept across restarts, taking precedence over the flags of `start`.\n" +
+		"Settings given to --unset revert to the flags of `start`.\n\n" +
+		"Example:\n" +
+		"    mcpjungle server-config --call-timeout 2m --log-level debug\n" +
+		"    mcpjungle server-config --unset call-timeout,log-level",
+	Args: cobra.NoArgs,
+	RunE: runServerConfig,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "19",
+	},
+}
+
+func init() {
+	serverConfigCmd.Flags().DurationVar(
+		&serverConfigCmdCallTimeout,
+		"call-timeout",
+		0,
+		"default maximum time allowed for a single attempt of a tool call to an MCP server",
+	)
+	serverConfigCmd.Flags().IntVar(
+		&serverConfigCmdMaxRetries,
+		"max-retries",
+		0,
+		"default number of times a tool call is retried if it fails due to a transport error",
+	)
+	serverConfigCmd.Flags().DurationVar(
+		&serverConfigCmdRetryBackoff,
+		"retry-backoff",
+		0,
+		"default delay before the first retry of a tool call",
+	)
+	serverConfigCmd.Flags().IntVar(
+		&serverConfigCmdMaxConcurrency,
+		"max-concurrency",
+		0,
+		"default maximum number of tool calls in progress on an MCP server at the same time (0 means unlimited)",
+	)
+	serverConfigCmd.Flags().IntVar(
+		&serverConfigCmdMaxQueue,
+		"max-queue",
+		0,
+		"default maximum number of tool calls waiting for a busy MCP server",
+	)
+	serverConfigCmd.Flags().DurationVar(
+		&serverConfigCmdQueueTimeout,
+		"queue-timeout",
+		0,
+		"default maximum time a tool call waits for a busy MCP server",
+	)
+	serverConfigCmd.Flags().StringVar(
+		&serverConfigCmdLogLevel,
+		"log-level",
+		"",
+		"minimum level of the server logs ('debug' | 'info' | 'warn' | 'error')",
+	)
+	serverConfigCmd.Flags().StringSliceVar(
+		&serverConfigCmdUnset,
+		"unset",
+		nil,
+		"settings to revert to the flags of `start`, named like the flags of this command, eg- call-timeout",
+	)
+	rootCmd.AddCommand(serverConfigCmd)
+}
+
+func runServerConfig(cmd *cobra.Command, args []string) error {
+	var input types.UpdateServerSettingsInput
+	settings := &input.ServerSettings
+	changed := false
+	flags := cmd.Flags()
+	if flags.Changed("call-timeout") {
+		settings.CallTimeout = serverConfigCmdCallTimeout.String()
+		changed = true
+	}
+	if flags.Changed("max-retries") {
+		settings.MaxRetries = &serverConfigCmdMaxRetries
+		changed = true
+	}
+	if flags.Changed("retry-backoff") {
+		settings.RetryBackoff = serverConfigCmdRetryBackoff.String()
+		changed = true
+	}
+	if flags.Changed("max-concurrency") {
+		settings.MaxConcurrency = &serverConfigCmdMaxConcurrency
+		changed = true
+	}
+	if flags.Changed("max-queue") {
+		settings.MaxQueue = &serverConfigCmdMaxQueue
+		changed = true
+	}
+	if flags.Changed("queue-timeout") {
+		settings.QueueTimeout = serverConfigCmdQueueTimeout.String()
+		changed = true
+	}
+	if flags.Changed("log-level") {
+		settings.LogLevel = serverConfigCmdLogLevel
+		changed = true
+	}
+	for _, name := range serverConfigCmdUnset {
+		// the API names settings like their JSON fields
+		input.Unset = append(input.Unset, strings.ReplaceAll(name, "-", "_"))
+		changed = true
+	}
+
+	var cfg *types.ServerConfig
+	var err error
+	if changed {
+		cfg, err = apiClient.UpdateServerConfig(&input)
+		if err != nil {
+			return fmt.Errorf("failed to update server config: %w", err)
+		}
+		cmd.Print("Server settings updated successfully\n\n")
+	} else {
+		cfg, err = apiClient.GetServerConfig()
+		if err != nil {
+			return fmt.Errorf("failed to get server config: %w", err)
+		}
+	}
+
+	s := cfg.Settings
+	fmt.Printf("Mode: %s\n\n", cfg.Mode)
+	fmt.Println("Settings:")
+	fmt.Printf("  call-timeout:    %s\n", s.CallTimeout)
+	fmt.Printf("  max-retries:     %s\n", formatOptionalInt(s.MaxRetries))
+	fmt.Printf("  retry-backoff:   %s\n", s.RetryBackoff)
+	fmt.Printf("  max-concurrency: %s\n", formatOptionalInt(s.MaxConcurrency))
+	fmt.Printf("  max-queue:       %s\n", formatOptionalInt(s.MaxQueue))
+	fmt.Printf("  queue-timeout:   %s\n", s.QueueTimeout)
+	fmt.Printf("  log-level:       %s\n", s.LogLevel)
+	return nil
+}
+
+func formatOptionalInt(v *int) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
@@ -1,22 +1,39 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/api"
+	"github.com/mcpjungle/mcpjungle/internal/changes"
 	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
 	"github.com/mcpjungle/mcpjungle/internal/migrations"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/history"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/reconcile"
+	"github.com/mcpjungle/mcpjungle/internal/service/stats"
 	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/internal/session"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"github.com/spf13/cobra"
+	"gorm.io/gorm"
 )
 
 const (
@@ -24,13 +41,65 @@ const (
 	BindPortDefault = "8080"
 
 	DBUrlEnvVar = "DATABASE_URL"
+	// DataDirEnvVar is the directory in which the embedded SQLite database is created if DATABASE_URL is not set
+	DataDirEnvVar = "DATA_DIR"
+
+	// AutoMigrateEnvVar enables applying pending database migrations when the server starts
+	AutoMigrateEnvVar = "AUTO_MIGRATE"
 
 	ServerModeEnvVar = "SERVER_MODE"
+
+	LogFormatEnvVar = "LOG_FORMAT"
+	LogLevelEnvVar  = "LOG_LEVEL"
+
+	// ConfigFileEnvVar is the path to a manifest that is applied every time the server starts
+	ConfigFileEnvVar = "CONFIG_FILE"
+
+	// TraceExporterEnvVar is the standard OpenTelemetry environment variable to select the trace exporter
+	TraceExporterEnvVar = "OTEL_TRACES_EXPORTER"
+
+	// SessionStoreEnvVar selects where the MCP sessions of the proxy endpoints are stored
+	SessionStoreEnvVar = "SESSION_STORE"
 )
 
 var (
-	startServerCmdBindPort    string
-	startServerCmdProdEnabled bool
+	startServerCmdBindPort      string
+	startServerCmdProdEnabled   bool
+	startServerCmdTraceExporter string
+	startServerCmdLogFormat     string
+	startServerCmdLogLevel      string
+	startServerCmdConfigFile    string
+	startServerCmdAutoMigrate   bool
+	startServerCmdDataDir       string
+
+	startServerCmdDBMaxOpenConns    int
+	startServerCmdDBMaxIdleConns    int
+	startServerCmdDBConnMaxLifetime time.Duration
+
+	startServerCmdHealthCheckInterval         time.Duration
+	startServerCmdHealthCheckTimeout          time.Duration
+	startServerCmdHealthCheckFailureThreshold int
+
+	startServerCmdSyncInterval time.Duration
+
+	startServerCmdSessionStore       string
+	startServerCmdSessionIdleTimeout time.Duration
+
+	startServerCmdCallTimeout  time.Duration
+	startServerCmdMaxRetries   int
+	startServerCmdRetryBackoff time.Duration
+
+	startServerCmdMaxConcurrency int
+	startServerCmdMaxQueue       int
+	startServerCmdQueueTimeout   time.Duration
+
+	startServerCmdBreakerFailureRatio float64
+	startServerCmdBreakerMinRequests  int
+	startServerCmdBreakerWindow       time.Duration
+	startServerCmdBreakerOpenDuration time.Duration
+
+	startServerCmdHistorySize          int
+	startServerCmdHistoryMaxRecordSize int
 )
 
 var startServerCmd = &cobra.Command{
@@ -63,6 +132,202 @@ func init() {
 			ServerModeEnvVar, model.ModeDev, model.ModeProd,
 		),
 	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdTraceExporter,
+		"trace-exporter",
+		"",
+		fmt.Sprintf(
+			"OpenTelemetry trace exporter to use ('%s' | '%s' | '%s', overrides env var %s).\n"+
+				"The OTLP exporter is configured using the standard OTEL_EXPORTER_OTLP_* environment variables.",
+			tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterConsole, TraceExporterEnvVar,
+		),
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdLogFormat,
+		"log-format",
+		"",
+		fmt.Sprintf(
+			"format of the server logs ('%s' | '%s', overrides env var %s, default '%s')",
+			logging.FormatText, logging.FormatJSON, LogFormatEnvVar, logging.FormatText,
+		),
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdLogLevel,
+		"log-level",
+		"",
+		fmt.Sprintf(
+			"minimum level of the server logs ('debug' | 'info' | 'warn' | 'error', overrides env var %s, default 'info')",
+			LogLevelEnvVar,
+		),
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdConfigFile,
+		"config",
+		"",
+		fmt.Sprintf(
+			"path to a YAML or JSON manifest of MCP servers, disabled tools and tool groups to register every time\n"+
+				"the server starts, see `mcpjungle apply --help` (overrides env var %s)",
+			ConfigFileEnvVar,
+		),
+	)
+	startServerCmd.Flags().BoolVar(
+		&startServerCmdAutoMigrate,
+		"auto-migrate",
+		false,
+		fmt.Sprintf(
+			"apply pending database migrations on startup instead of requiring `mcpjungle migrate up`.\n"+
+				"A new, empty database is always set up. Alternatively, set the %s environment variable to true",
+			AutoMigrateEnvVar,
+		),
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdDataDir,
+		"data-dir",
+		"",
+		fmt.Sprintf(
+			"directory in which the embedded SQLite database is created if %s is not set\n"+
+				"(overrides env var %s, default is the current working directory)",
+			DBUrlEnvVar, DataDirEnvVar,
+		),
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdDBMaxOpenConns,
+		"db-max-open-conns",
+		0,
+		"maximum number of open connections to a PostgreSQL or MySQL database (0 means unlimited)",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdDBMaxIdleConns,
+		"db-max-idle-conns",
+		0,
+		"maximum number of idle connections to a PostgreSQL or MySQL database kept open for reuse\n"+
+			"(0 keeps the default of 2)",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdDBConnMaxLifetime,
+		"db-conn-max-lifetime",
+		0,
+		"maximum time a connection to a PostgreSQL or MySQL database is reused before it is closed,\n"+
+			"eg- to rotate connections behind a load balancer (0 reuses connections forever)",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdHealthCheckInterval,
+		"health-check-interval",
+		0,
+		"interval between health checks of the registered MCP servers, eg- 1m (0 disables health checks)",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdHealthCheckTimeout,
+		"health-check-timeout",
+		10*time.Second,
+		"maximum time allowed for a single health check of an MCP server",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdHealthCheckFailureThreshold,
+		"health-check-failure-threshold",
+		0,
+		"number of consecutive failed health checks after which an MCP server's tools are removed from the\n"+
+			"MCP proxy until it recovers (0 never removes them)",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdSyncInterval,
+		"sync-interval",
+		5*time.Second,
+		"interval at which changes made to tools and tool groups by other mcpjungle servers sharing the\n"+
+			"database are picked up (0 disables it, eg- if this is the only server using the database)",
+	)
+	startServerCmd.Flags().StringVar(
+		&startServerCmdSessionStore,
+		"session-store",
+		"",
+		fmt.Sprintf(
+			"where the MCP sessions of /mcp and the tool group endpoints are stored ('%s' | '%s' | '%s',\n"+
+				"overrides env var %s, default '%s'). Use '%s' or '%s' to run several servers behind a load balancer.",
+			session.StoreMemory, session.StoreDatabase, session.StoreStateless, SessionStoreEnvVar,
+			session.StoreMemory, session.StoreDatabase, session.StoreStateless,
+		),
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdSessionIdleTimeout,
+		"session-idle-timeout",
+		24*time.Hour,
+		"time after which an MCP session that hasn't been used expires (0 never expires sessions)",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdCallTimeout,
+		"call-timeout",
+		mcp.DefaultCallPolicy.Timeout,
+		"default maximum time allowed for a single attempt of a tool call to an MCP server",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdMaxRetries,
+		"max-retries",
+		mcp.DefaultCallPolicy.MaxRetries,
+		"default number of times a tool call is retried if it fails due to a transport error.\n"+
+			"Calls are never retried if the tool itself returns an error.",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdRetryBackoff,
+		"retry-backoff",
+		mcp.DefaultCallPolicy.RetryBackoff,
+		"default delay before the first retry of a tool call, doubled for every subsequent retry",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdMaxConcurrency,
+		"max-concurrency",
+		mcp.DefaultCallPolicy.MaxConcurrency,
+		"default maximum number of tool calls in progress on an MCP server at the same time,\n"+
+			"further calls wait in a queue (0 means unlimited)",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdMaxQueue,
+		"max-queue",
+		mcp.DefaultCallPolicy.MaxQueue,
+		"default maximum number of tool calls waiting for a busy MCP server, further calls are rejected",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdQueueTimeout,
+		"queue-timeout",
+		mcp.DefaultCallPolicy.QueueTimeout,
+		"default maximum time a tool call waits for a busy MCP server before it is rejected",
+	)
+	startServerCmd.Flags().Float64Var(
+		&startServerCmdBreakerFailureRatio,
+		"breaker-failure-ratio",
+		mcp.DefaultBreakerConfig.FailureRatio,
+		"ratio of failed tool calls (0 to 1) after which an MCP server's circuit breaker opens and\n"+
+			"calls to it are rejected immediately (0 disables circuit breakers)",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdBreakerMinRequests,
+		"breaker-min-requests",
+		mcp.DefaultBreakerConfig.MinRequests,
+		"minimum number of tool calls to an MCP server within the window before its circuit breaker can open",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdBreakerWindow,
+		"breaker-window",
+		mcp.DefaultBreakerConfig.Window,
+		"duration over which tool calls are counted by the circuit breaker",
+	)
+	startServerCmd.Flags().DurationVar(
+		&startServerCmdBreakerOpenDuration,
+		"breaker-open-duration",
+		mcp.DefaultBreakerConfig.OpenDuration,
+		"time for which calls are rejected once a circuit breaker opens, before the server is probed again",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdHistorySize,
+		"history-size",
+		history.DefaultMaxRecords,
+		"number of most recent tool calls recorded for debugging (0 disables the history)",
+	)
+	startServerCmd.Flags().IntVar(
+		&startServerCmdHistoryMaxRecordSize,
+		"history-max-record-size",
+		history.DefaultMaxRecordSize,
+		"maximum size in bytes of the arguments & result recorded for a tool call, larger results are not recorded",
+	)
 
 	rootCmd.AddCommand(startServerCmd)
 }
@@ -70,17 +335,52 @@ func init() {
 func runStartServer(cmd *cobra.Command, args []string) error {
 	_ = godotenv.Load()
 
-	// connect to the DB and run migrations
-	dsn := os.Getenv(DBUrlEnvVar)
-	dbConn, err := db.NewDBConnection(dsn)
+	// set up logging before anything else so that all logs are written in the desired format
+	logFormat := startServerCmdLogFormat
+	if logFormat == "" {
+		logFormat = os.Getenv(LogFormatEnvVar)
+	}
+	logLevel := startServerCmdLogLevel
+	if logLevel == "" {
+		logLevel = os.Getenv(LogLevelEnvVar)
+	}
+	if err := logging.Init(logFormat, logLevel); err != nil {
+		return fmt.Errorf("failed to initialize logging: %v", err)
+	}
+
+	// set up tracing before creating any services so that all operations can be traced
+	traceExporter := startServerCmdTraceExporter
+	if traceExporter == "" {
+		traceExporter = os.Getenv(TraceExporterEnvVar)
+	}
+	shutdownTracing, err := tracing.Init(cmd.Context(), traceExporter)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		_ = shutdownTracing(context.Background())
+	}()
+
+	// connect to the DB and make sure that its schema is up to date
+	dbConn, err := db.NewDBConnection(db.Config{
+		DSN:             os.Getenv(DBUrlEnvVar),
+		DataDir:         dataDir(startServerCmdDataDir),
+		MaxOpenConns:    startServerCmdDBMaxOpenConns,
+		MaxIdleConns:    startServerCmdDBMaxIdleConns,
+		ConnMaxLifetime: startServerCmdDBConnMaxLifetime,
+	})
 	if err != nil {
 		return err
 	}
-	// Migrations should ideally be decoupled from both the server and the startup phase
-	// (should be run as a separate command).
-	// However, for the user's convenience, we run them as part of startup command for now.
-	if err := migrations.Migrate(dbConn); err != nil {
-		return fmt.Errorf("failed to run migrations: %v", err)
+	autoMigrate := startServerCmdAutoMigrate
+	if !autoMigrate && os.Getenv(AutoMigrateEnvVar) != "" {
+		autoMigrate, err = strconv.ParseBool(os.Getenv(AutoMigrateEnvVar))
+		if err != nil {
+			return fmt.Errorf("invalid value for %s environment variable: %v", AutoMigrateEnvVar, err)
+		}
+	}
+	if err := ensureSchemaUpToDate(dbConn, autoMigrate); err != nil {
+		return err
 	}
 
 	// determine the port to bind the server to
@@ -92,6 +392,13 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		port = BindPortDefault
 	}
 
+	// changes made by other mcpjungle servers sharing the database are watched from before the registry is loaded,
+	// so that none made while loading it are missed
+	watcher, err := changes.NewWatcher(dbConn)
+	if err != nil {
+		return fmt.Errorf("failed to watch the registry for changes: %v", err)
+	}
+
 	// create the MCP proxy server
 	mcpProxyServer := server.NewMCPServer(
 		"MCPJungle Proxy MCP Server",
@@ -104,6 +411,46 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create MCP service: %v", err)
 	}
 
+	// servers registered with their own call settings override these defaults
+	err = mcpService.SetDefaultCallPolicy(mcp.CallPolicy{
+		Timeout:      startServerCmdCallTimeout,
+		MaxRetries:   startServerCmdMaxRetries,
+		RetryBackoff: startServerCmdRetryBackoff,
+
+		MaxConcurrency: startServerCmdMaxConcurrency,
+		MaxQueue:       startServerCmdMaxQueue,
+		QueueTimeout:   startServerCmdQueueTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid tool call settings: %v", err)
+	}
+	err = mcpService.SetBreakerConfig(mcp.BreakerConfig{
+		FailureRatio: startServerCmdBreakerFailureRatio,
+		MinRequests:  startServerCmdBreakerMinRequests,
+		Window:       startServerCmdBreakerWindow,
+		OpenDuration: startServerCmdBreakerOpenDuration,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid circuit breaker settings: %v", err)
+	}
+
+	// rate limits & quotas are enforced on all tool calls made through the MCP proxy
+	rateLimitService, err := ratelimit.NewRateLimitService(dbConn)
+	if err != nil {
+		return fmt.Errorf("failed to create rate limit service: %v", err)
+	}
+	mcpService.SetCallLimiter(rateLimitService)
+
+	// record the most recent tool calls so that they can be inspected & replayed
+	historyService := history.NewHistoryService(dbConn, startServerCmdHistorySize, startServerCmdHistoryMaxRecordSize)
+	mcpService.AddToolCallCallback(historyService.RecordToolCall)
+	historyService.Start(cmd.Context())
+
+	// aggregate usage statistics of all tools
+	statsService := stats.NewStatsService(dbConn)
+	mcpService.AddToolCallCallback(statsService.RecordToolCall)
+	statsService.Start(cmd.Context())
+
 	mcpClientService := mcpclient.NewMCPClientService(dbConn)
 
 	configService := config.NewServerConfigService(dbConn)
@@ -114,6 +461,38 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create Tool Group service: %v", err)
 	}
 
+	// deliver events about changes to servers, tools, tool groups and quotas to webhooks
+	// and to clients of the event stream API
+	eventBus := events.NewBus()
+	mcpService.SetEventPublisher(eventBus)
+	toolGroupService.SetEventPublisher(eventBus)
+	webhookService := webhook.NewWebhookService(dbConn)
+	eventBus.Subscribe(webhookService.HandleEvent)
+	webhookService.Start(cmd.Context())
+
+	reconcileService := reconcile.NewReconcileService(mcpService, toolGroupService, mcpClientService, userService)
+	backupService := backup.NewBackupService(dbConn, mcpService, toolGroupService, rateLimitService)
+
+	// start health checks only after all services that react to changes in tools have been created,
+	// because unhealthy servers may have their tools removed from the proxy.
+	mcpService.StartHealthChecker(cmd.Context(), mcp.HealthCheckConfig{
+		Interval:         startServerCmdHealthCheckInterval,
+		Timeout:          startServerCmdHealthCheckTimeout,
+		FailureThreshold: startServerCmdHealthCheckFailureThreshold,
+	})
+
+	// tools are reloaded before tool groups, because groups are built from the tools in the MCP proxy
+	watcher.OnChange(model.RegistryScopeTools, mcpService.Reload)
+	watcher.OnChange(model.RegistryScopeToolGroups, toolGroupService.Reload)
+	watcher.OnChange(model.RegistryScopeRateLimits, rateLimitService.Reload)
+	watcher.Start(cmd.Context(), startServerCmdSyncInterval)
+
+	sessions, err := newSessionManager(dbConn)
+	if err != nil {
+		return err
+	}
+	sessions.Start(cmd.Context())
+
 	// create the API server
 	opts := &api.ServerOptions{
 		Port:             port,
@@ -123,14 +502,26 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		ConfigService:    configService,
 		UserService:      userService,
 		ToolGroupService: toolGroupService,
+		RateLimitService: rateLimitService,
+		HistoryService:   historyService,
+		StatsService:     statsService,
+		WebhookService:   webhookService,
+		EventBus:         eventBus,
+		ReconcileService: reconcileService,
+		BackupService:    backupService,
+		Sessions:         sessions,
 	}
 	s, err := api.NewServer(opts)
 	if err != nil {
 		return fmt.Errorf("failed to create server: %v", err)
 	}
+	// settings changed through another server sharing the database are applied immediately
+	watcher.OnChange(model.RegistryScopeSettings, s.ApplyStoredSettings)
 
 	// determine the server mode
 	desiredMode := model.ModeDev
+	// explicitMode is true if the mode was given through the flag or the environment variable
+	explicitMode := startServerCmdProdEnabled
 	envMode := os.Getenv(ServerModeEnvVar)
 	if envMode != "" {
 		// the value of the environment variable is allowed to be case-insensitive
@@ -144,6 +535,7 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		}
 
 		desiredMode = model.ServerMode(envMode)
+		explicitMode = true
 	}
 	if startServerCmdProdEnabled {
 		// If the --prod flag is set, it gets precedence over the environment variable
@@ -157,17 +549,34 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 	}
 	if ok {
 		// If the server is already initialized, then the mode supplied to this command (desired mode)
-		// must match the configured mode.
+		// must match the configured mode. If no mode was supplied, the configured mode is used.
 		mode, err := s.GetMode()
 		if err != nil {
 			return fmt.Errorf("failed to get server mode: %v", err)
 		}
-		if desiredMode != mode {
+		if explicitMode && desiredMode != mode {
+			if mode == model.ModeDev {
+				return fmt.Errorf(
+					"server is already initialized in %s mode, cannot start in %s mode."+
+						" Start it in %s mode and run `mcpjungle init-server --from-dev` to switch it to %s mode",
+					mode, desiredMode, mode, desiredMode,
+				)
+			}
 			return fmt.Errorf(
 				"server is already initialized in %s mode, cannot start in %s mode",
 				mode, desiredMode,
 			)
 		}
+
+		// settings changed through the API while the server was running take precedence over the flags
+		if err := s.ApplyStoredSettings(); err != nil {
+			return fmt.Errorf("failed to apply stored server settings: %v", err)
+		}
+		if mode == model.ModeDev {
+			if err := logSwitchToProdToken(configService); err != nil {
+				return err
+			}
+		}
 	} else {
 		// If server isn't already initialized and the desired mode is dev, silently initialize the server.
 		// Individual (dev mode) users need not worry about server initialization.
@@ -175,6 +584,9 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 			if err := s.InitDev(); err != nil {
 				return fmt.Errorf("failed to initialize server in development mode: %v", err)
 			}
+			if err := logSwitchToProdToken(configService); err != nil {
+				return err
+			}
 		} else {
 			// If desired mode is prod, then server initialization is a manual next step to be taken by the user.
 			// This is so that they can obtain the admin access token on their client machine.
@@ -185,12 +597,156 @@ func runStartServer(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	// register the servers, tool groups, etc. declared in the config file.
+	// This happens on every start, so that changes to the file are picked up after a restart.
+	// The file is checked before starting, but only applied once the server accepts connections,
+	// because connecting to the MCP servers declared in it may take a while.
+	configFile := startServerCmdConfigFile
+	if configFile == "" {
+		configFile = os.Getenv(ConfigFileEnvVar)
+	}
+	var onListening func()
+	if configFile != "" {
+		m, err := loadConfigFile(cmd.Context(), reconcileService, configFile)
+		if err != nil {
+			return err
+		}
+		onListening = func() {
+			if err := applyConfigFile(cmd.Context(), reconcileService, configFile, m); err != nil {
+				slog.Error("failed to apply config file", "path", configFile, logging.KeyError, err)
+			}
+		}
+	}
+
 	// Display startup banner when the server is started
 	fmt.Print(asciiArt)
 	fmt.Printf("MCPJungle HTTP server listening on :%s\n\n", port)
-	if err := s.Start(); err != nil {
+	if err := s.Start(onListening); err != nil {
 		return fmt.Errorf("failed to run the server: %v", err)
 	}
 
 	return nil
 }
+
+// logSwitchToProdToken logs the confirmation token needed to switch a server in development mode to production mode,
+// so that only those who can read the logs of the server can make themselves its admin.
+func logSwitchToProdToken(configService *config.ServerConfigService) error {
+	token, err := configService.SwitchToProdToken()
+	if err != nil {
+		return fmt.Errorf("failed to create confirmation token for switching to production mode: %v", err)
+	}
+	slog.Info(
+		"this server can be switched to production mode with `mcpjungle init-server --from-dev --confirm <token>`",
+		"token", token,
+	)
+	return nil
+}
+
+// dataDir returns the directory of the embedded SQLite database given by the flag or the environment variable.
+func dataDir(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv(DataDirEnvVar)
+}
+
+// newSessionManager creates the manager of MCP sessions using the store given by the flag or the environment variable.
+func newSessionManager(dbConn *gorm.DB) (*session.Manager, error) {
+	storeType := startServerCmdSessionStore
+	if storeType == "" {
+		storeType = os.Getenv(SessionStoreEnvVar)
+	}
+
+	var store session.Store
+	switch session.StoreType(strings.ToLower(storeType)) {
+	case "", session.StoreMemory:
+		store = session.NewMemoryStore()
+	case session.StoreDatabase:
+		store = session.NewDBStore(dbConn)
+	case session.StoreStateless:
+		// no sessions are issued, so any server can handle any request
+	default:
+		return nil, fmt.Errorf(
+			"invalid session store '%s': must be one of '%s', '%s' or '%s'",
+			storeType, session.StoreMemory, session.StoreDatabase, session.StoreStateless,
+		)
+	}
+	return session.NewManager(store, startServerCmdSessionIdleTimeout), nil
+}
+
+// ensureSchemaUpToDate applies the pending migrations to the database if auto-migration is enabled or
+// the database is empty.
+// Otherwise, it returns an error if any migrations are pending, because the server can't work with an outdated schema.
+func ensureSchemaUpToDate(dbConn *gorm.DB, autoMigrate bool) error {
+	empty, err := migrations.IsEmpty(dbConn)
+	if err != nil {
+		return fmt.Errorf("failed to check database migrations: %v", err)
+	}
+	pending, err := migrations.Pending(dbConn)
+	if err != nil {
+		return fmt.Errorf("failed to check database migrations: %v", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if !autoMigrate && !empty {
+		return fmt.Errorf(
+			"the database schema is out of date (%d pending migrations), run `mcpjungle migrate up`"+
+				" or start the server with --auto-migrate",
+			len(pending),
+		)
+	}
+
+	applied, err := migrations.Up(dbConn)
+	for _, m := range applied {
+		slog.Info("applied database migration", "version", m.Version, "name", m.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run migrations: %v", err)
+	}
+	return nil
+}
+
+// loadConfigFile reads the manifest in the config file and checks that it can be applied.
+// An invalid file prevents the server from starting.
+func loadConfigFile(
+	ctx context.Context, reconcileService *reconcile.ReconcileService, path string,
+) (*types.Manifest, error) {
+	m, err := readManifest(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config file: %w", err)
+	}
+	if len(m.Clients) > 0 || len(m.Users) > 0 {
+		// the access tokens of new MCP clients and users could only be shown in the server logs
+		return nil, fmt.Errorf(
+			"config file %s must not declare MCP clients or users, create them with `mcpjungle apply` instead", path,
+		)
+	}
+	if _, err := reconcileService.Reconcile(ctx, m, reconcile.Options{DryRun: true}); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// applyConfigFile makes the registry match the manifest loaded from the config file.
+// Changes that fail, eg- because an MCP server is unreachable, are logged and the remaining ones are still applied.
+// Objects that aren't declared in the file are left untouched.
+func applyConfigFile(
+	ctx context.Context, reconcileService *reconcile.ReconcileService, path string, m *types.Manifest,
+) error {
+	result, err := reconcileService.Reconcile(ctx, m, reconcile.Options{})
+	if err != nil {
+		return fmt.Errorf("failed to apply config file %s: %w", path, err)
+	}
+
+	for _, c := range result.Changes {
+		attrs := []any{"action", c.Action, "kind", c.Kind, "name", c.Name}
+		if c.Error != "" {
+			slog.Error("failed to apply change from config file", append(attrs, logging.KeyError, c.Error)...)
+			continue
+		}
+		slog.Info("applied change from config file", attrs...)
+	}
+	slog.Info("applied config file", "path", path, "changes", len(result.Changes), "failed", result.Failed())
+	return nil
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	mcpservice "github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/reconcile"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+)
+
+// newConfigFileTest returns the services needed to apply a config file and the URL of an MCP server with an echo tool.
+func newConfigFileTest(t *testing.T) (*reconcile.ReconcileService, *mcpservice.MCPService, string) {
+	t.Helper()
+	db := dbtest.New(t)
+	mcpService, err := mcpservice.NewMCPService(db, server.NewMCPServer("test", "0.1.0"))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	if err != nil {
+		t.Fatalf("failed to create tool group service: %v", err)
+	}
+	reconcileService := reconcile.NewReconcileService(
+		mcpService, toolGroupService, mcpclient.NewMCPClientService(db), user.NewUserService(db),
+	)
+
+	upstream := server.NewMCPServer("upstream", "0.1.0")
+	upstream.AddTool(
+		mcp.NewTool("echo", mcp.WithDescription("echoes its input")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultText("echo"), nil
+		},
+	)
+	srv := httptest.NewServer(server.NewStreamableHTTPServer(upstream))
+	t.Cleanup(srv.Close)
+	return reconcileService, mcpService, srv.URL + "/mcp"
+}
+
+// writeConfigFile writes a config file with the given content in a temporary directory and returns its path.
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "servers.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestApplyConfigFile(t *testing.T) {
+	reconcileService, mcpService, url := newConfigFileTest(t)
+	path := writeConfigFile(t, `
+servers:
+  - name: up
+    transport: streamable_http
+    url: `+url+`
+tool_groups:
+  - name: echoes
+    included_tools: [up__echo]
+`)
+
+	m, err := loadConfigFile(context.Background(), reconcileService, path)
+	if err != nil {
+		t.Fatalf("loadConfigFile() error = %v", err)
+	}
+	// loading the file only checks it
+	if servers, _ := mcpService.ListMcpServers(); len(servers) != 0 {
+		t.Fatalf("%d servers were registered by loading the config file, want 0", len(servers))
+	}
+
+	if err := applyConfigFile(context.Background(), reconcileService, path, m); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+	if _, err := mcpService.GetTool("up__echo"); err != nil {
+		t.Errorf("tool of the declared server was not registered: %v", err)
+	}
+	result, err := reconcileService.Reconcile(context.Background(), m, reconcile.Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("failed to plan config file: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Errorf("applying the config file again would make changes %+v, want none", result.Changes)
+	}
+}
+
+func TestLoadInvalidConfigFile(t *testing.T) {
+	reconcileService, _, url := newConfigFileTest(t)
+	tests := map[string]string{
+		"not yaml": "servers: [",
+		"empty":    "",
+		"duplicate server": `
+servers:
+  - {name: up, transport: streamable_http, url: ` + url + `}
+  - {name: up, transport: streamable_http, url: ` + url + `}
+`,
+		"mcp clients": `
+clients:
+  - name: cursor
+`,
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfigFile(t, content)
+			if _, err := loadConfigFile(context.Background(), reconcileService, path); err == nil {
+				t.Fatal("loadConfigFile() error = nil, want error")
+			}
+		})
+	}
+
+	if _, err := loadConfigFile(context.Background(), reconcileService, filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loadConfigFile() error = nil for a missing file, want error")
+	}
+}
+
+func TestApplyConfigFilePartialFailure(t *testing.T) {
+	reconcileService, mcpService, url := newConfigFileTest(t)
+	path := writeConfigFile(t, `
+servers:
+  - name: down
+    transport: streamable_http
+    url: http://127.0.0.1:1/mcp
+  - name: up
+    transport: streamable_http
+    url: `+url+`
+`)
+	m, err := loadConfigFile(context.Background(), reconcileService, path)
+	if err != nil {
+		t.Fatalf("loadConfigFile() error = %v", err)
+	}
+
+	// an unreachable server doesn't prevent the others from being registered
+	if err := applyConfigFile(context.Background(), reconcileService, path, m); err != nil {
+		t.Fatalf("applyConfigFile() error = %v", err)
+	}
+	servers, err := mcpService.ListMcpServers()
+	if err != nil {
+		t.Fatalf("failed to list servers: %v", err)
+	}
+	var names []string
+	for _, s := range servers {
+		names = append(names, s.Name)
+	}
+	if strings.Join(names, ",") != "up" {
+		t.Errorf("registered servers = %v, want [up]", names)
+	}
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mcpjungle/mcpjungle/client"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statsCmdBy     string
+	statsCmdSince  string
+	statsCmdServer string
+	statsCmdTool   string
+	statsCmdClient string
+	statsCmdOutput string
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show usage statistics of tools",
+	Long: "Shows the number of calls, the error rate and the p50 & p95 latencies of tool calls,\n" +
+		"grouped by tool, server, client or day.\n" +
+		"When grouping by tool, registered tools that weren't called at all are listed as well,\n" +
+		"which helps to find unused tools that can be removed from tool groups.",
+	RunE: runStats,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "12",
+	},
+}
+
+func init() {
+	statsCmd.Flags().StringVar(&statsCmdBy, "by", string(types.StatsByTool), "group statistics by tool, server, client or day")
+	statsCmd.Flags().StringVar(
+		&statsCmdSince,
+		"since",
+		"7d",
+		"period covered by the statistics, as a number of days (eg- 30d) or a duration (eg- 12h).\n"+
+			"Statistics are kept per day, so the whole first day is always included",
+	)
+	statsCmd.Flags().StringVar(&statsCmdServer, "server", "", "only include calls to tools of this MCP server")
+	statsCmd.Flags().StringVar(&statsCmdTool, "tool", "", "only include calls to this tool")
+	statsCmd.Flags().StringVar(&statsCmdClient, "client", "", "only include calls made by this MCP client")
+	statsCmd.Flags().StringVarP(&statsCmdOutput, "output", "o", "table", "output format, either table or json")
+	rootCmd.AddCommand(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	if statsCmdOutput != "table" && statsCmdOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': must be either table or json", statsCmdOutput)
+	}
+
+	resp, err := apiClient.GetStats(
+		types.StatsGrouping(statsCmdBy),
+		statsCmdSince,
+		client.StatsFilter{Server: statsCmdServer, Tool: statsCmdTool, Client: statsCmdClient},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get statistics: %w", err)
+	}
+
+	if statsCmdOutput == "json" {
+		// always written to stdout so that the output can be piped to other tools
+		fmt.Println(toIndentedJSON(resp))
+		return nil
+	}
+
+	if len(resp.Stats) == 0 {
+		cmd.Printf("No tool calls since %s\n", resp.Since.Format("2006-01-02"))
+		return nil
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\tCALLS\tERRORS\tERROR RATE\tP50\tP95\n", strings.ToUpper(string(resp.By)))
+	for _, s := range resp.Stats {
+		fmt.Fprintf(
+			w, "%s\t%d\t%d\t%.1f%%\t%s\t%s\n",
+			s.Key, s.Calls, s.Errors, s.ErrorRate*100, formatLatency(s.P50Ms), formatLatency(s.P95Ms),
+		)
+	}
+	return w.Flush()
+}
+
+// formatLatency formats a latency in milliseconds for display.
+func formatLatency(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.1fs", ms/1000)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchCmdEvents string
+	watchCmdOutput string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch changes in the registry as they happen",
+	Long: "Streams events about changes in the registry, eg- MCP servers being registered or tools being disabled,\n" +
+		"until interrupted.\n\n" +
+		"Available event types: " + eventTypesHelp(),
+	Args: cobra.NoArgs,
+	RunE: runWatch,
+	Annotations: map[string]string{
+		"group": string(subCommandGroupAdvanced),
+		"order": "13",
+	},
+}
+
+func init() {
+	watchCmd.Flags().StringVar(
+		&watchCmdEvents,
+		"events",
+		"",
+		"comma-separated list of event types to watch (default: all events)",
+	)
+	watchCmd.Flags().StringVarP(&watchCmdOutput, "output", "o", "text", "output format, either text or json")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	if watchCmdOutput != "text" && watchCmdOutput != "json" {
+		return fmt.Errorf("invalid output format '%s': must be either text or json", watchCmdOutput)
+	}
+
+	var eventTypes []types.EventType
+	for _, e := range strings.Split(watchCmdEvents, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			eventTypes = append(eventTypes, types.EventType(e))
+		}
+	}
+
+	err := apiClient.WatchEvents(cmd.Context(), eventTypes, func(e *types.Event) {
+		if watchCmdOutput == "json" {
+			// one event per line, so that the output can be piped to other tools
+			b, _ := json.Marshal(e)
+			fmt.Println(string(b))
+			return
+		}
+		fmt.Printf("%s  %-20s %s\n", e.Time.Local().Format(time.RFC3339), e.Type, formatEventData(e.Data))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch events: %w", err)
+	}
+	if cmd.Context().Err() != nil {
+		return nil
+	}
+	// the server only closes the stream if the client couldn't keep up with the events
+	return fmt.Errorf("the event stream was closed by the server, some events may have been missed")
+}
+
+// formatEventData formats the data of an event as key=value pairs, ordered by key.
+func formatEventData(data map[string]any) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		v := data[k]
+		if list, ok := v.([]any); ok {
+			items := make([]string, len(list))
+			for j, item := range list {
+				items[j] = fmt.Sprint(item)
+			}
+			v = strings.Join(items, ",")
+		}
+		pairs[i] = fmt.Sprintf("%s=%v", k, v)
+	}
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
+)
+
+// backupHandler returns a backup of the registry.
+func backupHandler(backupService *backup.BackupService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b, err := backupService.Create()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, b)
+	}
+}
+
+// restoreHandler replaces the contents of the registry with the backup in the request body.
+// The settings stored in the backup are applied immediately.
+func restoreHandler(backupService *backup.BackupService, settings *settingsApplier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b backup.Backup
+		if err := c.ShouldBindJSON(&b); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result, err := backupService.Restore(&b)
+		if err != nil {
+			if errors.Is(err, backup.ErrInvalidBackup) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := settings.reload(); err != nil {
+			c.JSON(
+				http.StatusInternalServerError,
+				gin.H{"error": "backup was restored but its settings could not be applied: " + err.Error()},
+			)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+const (
+	// eventStreamBufferSize is the number of events that can wait to be written to a single SSE client.
+	// A client that falls further behind is disconnected, so that it knows it may have missed events.
+	eventStreamBufferSize = 64
+	// eventStreamKeepAlive is the interval at which comments are sent on an idle event stream,
+	// so that proxies don't close the connection
+	eventStreamKeepAlive = 15 * time.Second
+)
+
+// streamEventsHandler streams events about changes in the registry to the client as server-sent events,
+// until the client disconnects.
+// The optional "types" query parameter is a comma-separated list of event types to stream.
+func streamEventsHandler(bus *events.Bus) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var wanted map[types.EventType]bool
+		if t := c.Query("types"); t != "" {
+			wanted = make(map[types.EventType]bool)
+			for _, name := range strings.Split(t, ",") {
+				et := types.EventType(strings.TrimSpace(name))
+				if !types.IsValidEventType(et) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown event type '%s'", et)})
+					return
+				}
+				wanted[et] = true
+			}
+		}
+
+		stream := make(chan *types.Event, eventStreamBufferSize)
+		lagging := make(chan struct{})
+		var lagOnce sync.Once
+		unsubscribe := bus.Subscribe(func(e *types.Event) {
+			if wanted != nil && !wanted[e.Type] {
+				return
+			}
+			select {
+			case stream <- e:
+			default:
+				lagOnce.Do(func() { close(lagging) })
+			}
+		})
+		defer unsubscribe()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Status(http.StatusOK)
+		// send a comment right away so that the client knows the stream has been established
+		_, _ = fmt.Fprint(c.Writer, ": connected\n\n")
+		c.Writer.Flush()
+
+		keepAlive := time.NewTicker(eventStreamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case <-lagging:
+				return
+			case <-keepAlive.C:
+				if _, err := fmt.Fprint(c.Writer, ": keep-alive\n\n"); err != nil {
+					return
+				}
+			case e := <-stream:
+				data, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				if _, err := fmt.Fprintf(c.Writer, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data); err != nil {
+					return
+				}
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// newEventStreamServer serves streamEventsHandler for the given bus on /events.
+func newEventStreamServer(t *testing.T, bus *events.Bus) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events", streamEventsHandler(bus))
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// readFrame reads a single server-sent event, ie, the lines up to the next blank line.
+func readFrame(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event stream: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	bus := events.NewBus()
+	srv := newEventStreamServer(t, bus)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/events?types=tool.added", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect to event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	if got := readFrame(t, r); len(got) != 1 || got[0] != ": connected" {
+		t.Fatalf("first frame = %q, want the connected comment", got)
+	}
+
+	// events of other types are filtered out
+	bus.Publish(types.EventServerRegistered, map[string]any{"server": "github"})
+	bus.Publish(types.EventToolAdded, map[string]any{"tools": []string{"github__create_pr"}})
+
+	frame := readFrame(t, r)
+	if len(frame) != 3 || !strings.HasPrefix(frame[0], "id: ") || frame[1] != "event: tool.added" ||
+		!strings.HasPrefix(frame[2], "data: ") {
+		t.Fatalf("frame = %q, want id, event and data fields", frame)
+	}
+	var e types.Event
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(frame[2], "data: ")), &e); err != nil {
+		t.Fatalf("failed to decode event data: %v", err)
+	}
+	if e.ID != strings.TrimPrefix(frame[0], "id: ") || e.Type != types.EventToolAdded {
+		t.Errorf("event = %+v, want the ID and type of the frame", e)
+	}
+}
+
+func TestStreamEventsRejectsUnknownTypes(t *testing.T) {
+	srv := newEventStreamServer(t, events.NewBus())
+
+	resp, err := http.Get(srv.URL + "/events?types=tool.added,tool.renamed")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+// blockingWriter is a ResponseWriter whose writes block until it is released, like a client that doesn't read.
+type blockingWriter struct {
+	*httptest.ResponseRecorder
+	// writing is closed when the first write starts
+	writing  chan struct{}
+	once     sync.Once
+	released chan struct{}
+}
+
+func (w *blockingWriter) Write(b []byte) (int, error) {
+	w.once.Do(func() { close(w.writing) })
+	<-w.released
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestStreamEventsDisconnectsSlowClients(t *testing.T) {
+	bus := events.NewBus()
+	w := &blockingWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		writing:          make(chan struct{}),
+		released:         make(chan struct{}),
+	}
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+
+	done := make(chan struct{})
+	go func() {
+		streamEventsHandler(bus)(c)
+		close(done)
+	}()
+
+	// the handler subscribes before writing anything, so it is subscribed once the first write blocks
+	<-w.writing
+	for range eventStreamBufferSize + 1 {
+		bus.Publish(types.EventToolAdded, nil)
+	}
+
+	// once the client reads again, the stream ends instead of silently skipping the dropped event
+	close(w.released)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the stream of a client that fell behind was not closed")
+	}
+	if n := strings.Count(w.Body.String(), "event: tool.added"); n > eventStreamBufferSize {
+		t.Errorf("%d events were written, want at most the %d buffered ones", n, eventStreamBufferSize)
+	}
+}
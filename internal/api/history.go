@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/history"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// defaultHistoryLimit is the number of tool calls returned from the history if the request doesn't specify a limit
+const defaultHistoryLimit = 20
+
+// listToolCallsHandler returns the most recent tool calls, optionally filtered by tool and client.
+func listToolCallsHandler(historyService *history.HistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := history.ToolCallFilter{
+			Tool:   c.Query("tool"),
+			Client: c.Query("client"),
+			Limit:  defaultHistoryLimit,
+		}
+		if l := c.Query("limit"); l != "" {
+			limit, err := strconv.Atoi(l)
+			if err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'limit' must be a positive integer"})
+				return
+			}
+			filter.Limit = limit
+		}
+
+		records, err := historyService.ListToolCalls(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp := make([]*types.ToolCallRecord, len(records))
+		for i := range records {
+			resp[i] = toolCallRecordResponse(&records[i])
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// getToolCallHandler returns a single tool call from the history.
+func getToolCallHandler(historyService *history.HistoryService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tool call ID"})
+			return
+		}
+		rec, err := historyService.GetToolCall(uint(id))
+		if err != nil {
+			if errors.Is(err, history.ErrToolCallNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, toolCallRecordResponse(rec))
+	}
+}
+
+// toolCallRecordResponse converts a tool call record into its API representation.
+// The arguments and result were serialized by mcpjungle itself, so they are always valid.
+func toolCallRecordResponse(rec *model.ToolCallRecord) *types.ToolCallRecord {
+	resp := &types.ToolCallRecord{
+		ID:         rec.ID,
+		Time:       rec.CreatedAt,
+		Tool:       rec.Tool,
+		Client:     rec.Client,
+		Error:      rec.Error,
+		Outcome:    rec.Outcome,
+		DurationMs: rec.DurationMs,
+		Truncated:  rec.Truncated,
+	}
+	if len(rec.Arguments) > 0 {
+		_ = json.Unmarshal(rec.Arguments, &resp.Arguments)
+	}
+	if len(rec.Result) > 0 {
+		_ = json.Unmarshal(rec.Result, &resp.Result)
+	}
+	return resp
+}
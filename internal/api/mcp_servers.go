@@ -1,8 +1,11 @@
 package api
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
@@ -18,44 +21,12 @@ func registerServerHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 			return
 		}
 
-		transport, err := types.ValidateTransport(input.Transport)
+		server, err := model.NewMcpServer(&input)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		var server *model.McpServer
-		if transport == types.TransportStreamableHTTP {
-			server, err = model.NewStreamableHTTPServer(
-				input.Name,
-				input.Description,
-				input.URL,
-				input.BearerToken,
-			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating streamable http server: %v", err)},
-				)
-				return
-			}
-		} else {
-			server, err = model.NewStdioServer(
-				input.Name,
-				input.Description,
-				input.Command,
-				input.Args,
-				input.Env,
-			)
-			if err != nil {
-				c.JSON(
-					http.StatusBadRequest,
-					gin.H{"error": fmt.Sprintf("Error creating stdio server: %v", err)},
-				)
-				return
-			}
-		}
-
 		if err := mcpService.RegisterMcpServer(c, server); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
@@ -88,7 +59,24 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 				Name:        record.Name,
 				Transport:   string(record.Transport),
 				Description: record.Description,
+				Status:      mcpService.GetServerStatus(record.Name),
 			}
+			settings, err := record.GetSettings()
+			if err != nil {
+				c.JSON(
+					http.StatusInternalServerError,
+					gin.H{"error": fmt.Sprintf("Error getting settings for server %s: %v", record.Name, err)},
+				)
+				return
+			}
+			servers[i].CallTimeout = settings.CallTimeout
+			servers[i].MaxRetries = settings.MaxRetries
+			servers[i].RetryBackoff = settings.RetryBackoff
+			servers[i].RetryOnTimeout = settings.RetryOnTimeout
+			servers[i].MaxConcurrency = settings.MaxConcurrency
+			servers[i].MaxQueue = settings.MaxQueue
+			servers[i].QueueTimeout = settings.QueueTimeout
+
 			if record.Transport == types.TransportStreamableHTTP {
 				conf, err := record.GetStreamableHTTPConfig()
 				if err != nil {
@@ -120,3 +108,73 @@ func listServersHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, servers)
 	}
 }
+
+// getServerLogsHandler returns the most recent stderr output of an MCP server.
+// If the "follow" query parameter is true, the logs are streamed to the client as newline-delimited JSON
+// until the client disconnects or the server is deregistered.
+func getServerLogsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := c.Param("name")
+
+		tail := 0
+		if t := c.Query("tail"); t != "" {
+			n, err := strconv.Atoi(t)
+			if err != nil || n < 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'tail' must be a non-negative integer"})
+				return
+			}
+			tail = n
+		}
+		follow := false
+		if f := c.Query("follow"); f != "" {
+			b, err := strconv.ParseBool(f)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'follow' must be a boolean"})
+				return
+			}
+			follow = b
+		}
+
+		if !follow {
+			entries, err := mcpService.GetServerLogs(name, tail)
+			if err != nil {
+				if errors.Is(err, mcp.ErrServerNotFound) {
+					c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("MCP server %s not found", name)})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, entries)
+			return
+		}
+
+		backlog, entries, err := mcpService.FollowServerLogs(c.Request.Context(), name, tail)
+		if err != nil {
+			if errors.Is(err, mcp.ErrServerNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("MCP server %s not found", name)})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		enc := json.NewEncoder(c.Writer)
+		for _, e := range backlog {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+
+		// the channel is closed once the client disconnects
+		for e := range entries {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
@@ -2,7 +2,11 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mcpjungle/mcpjungle/internal/model"
@@ -59,6 +63,17 @@ func invokeToolHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 
 		resp, err := mcpService.InvokeTool(c, name, args)
 		if err != nil {
+			var circuitOpenErr *mcp.CircuitOpenError
+			if errors.As(err, &circuitOpenErr) {
+				c.Header("Retry-After", retryAfterSeconds(circuitOpenErr.RetryAfter))
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to invoke tool: " + err.Error()})
+				return
+			}
+			var serverBusyErr *mcp.ServerBusyError
+			if errors.As(err, &serverBusyErr) {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "failed to invoke tool: " + err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to invoke tool: " + err.Error()})
 			return
 		}
@@ -120,3 +135,8 @@ func disableToolsHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
 		c.JSON(http.StatusOK, disabledTools)
 	}
 }
+
+// retryAfterSeconds formats a duration as the value of a Retry-After header, ie, in whole seconds rounded up.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -3,17 +3,73 @@ package api
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
+// logRequests is middleware that writes a structured log line for every HTTP request served.
+// It replaces gin's default request logger so that request logs follow the same format as all other logs.
+func logRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration", time.Since(start),
+			"client_ip", c.ClientIP(),
+		}
+		// the MCP client is only known for requests made to the MCP proxy in production mode
+		if client, ok := c.Request.Context().Value("client").(*model.McpClient); ok {
+			attrs = append(attrs, logging.KeyClient, client.Name)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, logging.KeyError, c.Errors.String())
+		}
+
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+// recordRequestMetrics is middleware that records the latency of every HTTP request, labeled by its route.
+// The route template (eg- /api/v0/servers/:name) is used instead of the actual path to keep the number of
+// label values bounded.
+func recordRequestMetrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			// the request did not match any registered route
+			route = "unmatched"
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, strconv.Itoa(c.Writer.Status()), time.Since(start))
+	}
+}
+
 // requireInitialized is middleware to reject requests to certain routes if the server is not initialized
 func requireInitialized(configService *config.ServerConfigService) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -162,7 +218,7 @@ func checkAuthForMcpProxyAccess(mcpClientService *mcpclient.McpClientService) gi
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing MCP client access token"})
 			return
 		}
-		client, err := mcpClientService.GetClientByToken(token)
+		client, err := lookupMcpClient(c.Request.Context(), mcpClientService, token)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid MCP client token"})
 			return
@@ -175,3 +231,21 @@ func checkAuthForMcpProxyAccess(mcpClientService *mcpclient.McpClientService) gi
 		c.Next()
 	}
 }
+
+// lookupMcpClient returns the MCP client that owns the given access token.
+// The lookup is traced because it costs a DB round-trip on every request made to the MCP proxy.
+func lookupMcpClient(
+	ctx context.Context, mcpClientService *mcpclient.McpClientService, token string,
+) (*model.McpClient, error) {
+	_, span := tracing.Tracer().Start(ctx, "mcp_proxy.auth")
+	defer span.End()
+
+	client, err := mcpClientService.GetClientByToken(token)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid MCP client token")
+		return nil, err
+	}
+	span.SetAttributes(attribute.String("mcp.client.name", client.Name))
+	return client, nil
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+func TestRecordRequestMetrics(t *testing.T) {
+	reg := metrics.NewTestRegistry()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(recordRequestMetrics())
+	r.GET("/api/v0/servers/:name", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/v0/tools/invoke", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/v0/servers/github", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v0/servers/slack", nil),
+		httptest.NewRequest(http.MethodPost, "/api/v0/tools/invoke", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v0/nothing-here", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	// requests are counted by route rather than by path, so that the number of series stays bounded
+	got := make(map[string]uint64)
+	for _, f := range families {
+		if f.GetName() != "mcpjungle_http_request_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			got[labels["method"]+" "+labels["route"]+" "+labels["status"]] = m.GetHistogram().GetSampleCount()
+		}
+	}
+	want := map[string]uint64{
+		"GET /api/v0/servers/:name 200": 2,
+		"POST /api/v0/tools/invoke 502": 1,
+		"GET unmatched 404":             1,
+	}
+	if len(got) != len(want) {
+		t.Errorf("recorded requests = %v, want %v", got, want)
+	}
+	for k, n := range want {
+		if got[k] != n {
+			t.Errorf("%d requests recorded for %q, want %d", got[k], k, n)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// listRateLimitsHandler returns all rate limits along with their usage in the current day and month.
+func listRateLimitsHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limits, err := rateLimitService.ListRateLimits()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		resp := make([]*types.RateLimit, len(limits))
+		for i := range limits {
+			r := &limits[i]
+			daily, monthly, err := rateLimitService.GetUsage(r)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			resp[i] = &types.RateLimit{
+				Scope:             r.Scope,
+				Target:            r.Target,
+				RequestsPerMinute: r.RequestsPerMinute,
+				Burst:             r.Burst,
+				DailyQuota:        r.DailyQuota,
+				MonthlyQuota:      r.MonthlyQuota,
+				DailyUsage:        daily,
+				MonthlyUsage:      monthly,
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// setRateLimitHandler creates a rate limit or replaces the existing one for the same scope and target.
+func setRateLimitHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.RateLimit
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		r := &model.RateLimit{
+			Scope:             input.Scope,
+			Target:            input.Target,
+			RequestsPerMinute: input.RequestsPerMinute,
+			Burst:             input.Burst,
+			DailyQuota:        input.DailyQuota,
+			MonthlyQuota:      input.MonthlyQuota,
+		}
+		if err := r.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rate limit: " + err.Error()})
+			return
+		}
+		if err := rateLimitService.SetRateLimit(r); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		input.Burst = r.Burst
+		c.JSON(http.StatusOK, input)
+	}
+}
+
+// deleteRateLimitHandler deletes the rate limit with the scope and target given as query parameters.
+// The target is passed as a query parameter because tool names cannot be supplied as path params.
+func deleteRateLimitHandler(rateLimitService *ratelimit.RateLimitService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := c.Query("scope")
+		target := c.Query("target")
+		if scope == "" || target == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'scope' or 'target' query parameter"})
+			return
+		}
+		if err := rateLimitService.DeleteRateLimit(types.RateLimitScope(scope), target); err != nil {
+			if errors.Is(err, ratelimit.ErrRateLimitNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/reconcile"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// reconcileHandler makes the registry match the manifest in the request body.
+// The "dry_run" query parameter only computes the changes without applying them and
+// the "prune" query parameter deletes the objects that are not declared in the manifest.
+// Changes that fail to apply are reported in the response, so the request succeeds as long as the manifest is valid.
+func reconcileHandler(reconcileService *reconcile.ReconcileService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var opts reconcile.Options
+		var err error
+		if opts.DryRun, err = queryBool(c, "dry_run"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if opts.Prune, err = queryBool(c, "prune"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		// MCP clients and users only exist in production mode
+		mode, _ := c.Get("mode")
+		opts.ManageAccess = mode == model.ModeProd
+
+		var m types.Manifest
+		if err := c.ShouldBindJSON(&m); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := reconcileService.Reconcile(c, &m, opts)
+		if err != nil {
+			if errors.Is(err, reconcile.ErrInvalidManifest) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// exportHandler returns a manifest that declares the current state of the registry.
+// The "omit_secrets" query parameter replaces the bearer tokens and environment variable values of servers
+// with types.KeepCurrentSecret.
+func exportHandler(reconcileService *reconcile.ReconcileService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var opts reconcile.ExportOptions
+		var err error
+		if opts.OmitSecrets, err = queryBool(c, "omit_secrets"); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		mode, _ := c.Get("mode")
+		opts.ManageAccess = mode == model.ModeProd
+
+		m, err := reconcileService.Export(opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, m)
+	}
+}
+
+// queryBool returns the value of an optional boolean query parameter, false if it is absent.
+func queryBool(c *gin.Context, name string) (bool, error) {
+	v := c.Query(name)
+	if v == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("'%s' must be a boolean", name)
+	}
+	return b, nil
+}
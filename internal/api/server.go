@@ -3,15 +3,27 @@ package api
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/backup"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/history"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/reconcile"
+	"github.com/mcpjungle/mcpjungle/internal/service/stats"
 	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/internal/session"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 const (
@@ -29,6 +41,17 @@ type ServerOptions struct {
 	ConfigService    *config.ServerConfigService
 	UserService      *user.UserService
 	ToolGroupService *toolgroup.ToolGroupService
+	RateLimitService *ratelimit.RateLimitService
+	HistoryService   *history.HistoryService
+	StatsService     *stats.StatsService
+	WebhookService   *webhook.WebhookService
+	EventBus         *events.Bus
+	ReconcileService *reconcile.ReconcileService
+	BackupService    *backup.BackupService
+
+	// Sessions manages the MCP sessions opened on /mcp and the tool group endpoints.
+	// Sessions are kept in memory if it is nil.
+	Sessions *session.Manager
 }
 
 // Server represents the MCPJungle registry server that handles MCP proxy and API requests
@@ -42,11 +65,14 @@ type Server struct {
 
 	configService *config.ServerConfigService
 	userService   *user.UserService
+	settings      *settingsApplier
 }
 
 // NewServer initializes a new Gin server for MCPJungle registry and MCP proxy
+// The default call settings and the log level in effect when it is created are the ones the server was started with.
 func NewServer(opts *ServerOptions) (*Server, error) {
-	r, err := newRouter(opts)
+	settings := newSettingsApplier(opts.MCPService, opts.ConfigService)
+	r, err := newRouter(opts, settings)
 	if err != nil {
 		return nil, err
 	}
@@ -58,6 +84,7 @@ func NewServer(opts *ServerOptions) (*Server, error) {
 		mcpClientService: opts.MCPClientService,
 		configService:    opts.ConfigService,
 		userService:      opts.UserService,
+		settings:         settings,
 	}
 	return s, nil
 }
@@ -87,6 +114,12 @@ func (s *Server) GetMode() (model.ServerMode, error) {
 	return c.Mode, nil
 }
 
+// ApplyStoredSettings applies the settings that were changed through the API to the server,
+// so that they take precedence over the settings it was started with.
+func (s *Server) ApplyStoredSettings() error {
+	return s.settings.reload()
+}
+
 // InitDev initializes the server configuration in the Development mode.
 // This method does not create an admin user because that is irrelevant in dev mode.
 func (s *Server) InitDev() error {
@@ -97,18 +130,30 @@ func (s *Server) InitDev() error {
 	return nil
 }
 
-// Start runs the Gin server (blocking call)
-func (s *Server) Start() error {
-	if err := s.router.Run(":" + s.port); err != nil {
+// Start runs the Gin server (blocking call).
+// If onListening is not nil, it is called in the background once the server accepts connections.
+func (s *Server) Start(onListening func()) error {
+	ln, err := net.Listen("tcp", ":"+s.port)
+	if err != nil {
+		return fmt.Errorf("failed to run the server: %w", err)
+	}
+	if onListening != nil {
+		go onListening()
+	}
+	if err := s.router.RunListener(ln); err != nil {
 		return fmt.Errorf("failed to run the server: %w", err)
 	}
 	return nil
 }
 
 // newRouter sets up the Gin router with the MCP proxy server and API endpoints.
-func newRouter(opts *ServerOptions) (*gin.Engine, error) {
+func newRouter(opts *ServerOptions, settings *settingsApplier) (*gin.Engine, error) {
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.Use(logRequests())
+	r.Use(otelgin.Middleware(tracing.ServiceName))
+	r.Use(recordRequestMetrics())
 
 	r.GET(
 		"/health",
@@ -117,12 +162,23 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		},
 	)
 
+	// expose metrics in the Prometheus exposition format
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	sessions := opts.Sessions
+	if sessions == nil {
+		sessions = session.NewManager(session.NewMemoryStore(), 0)
+	}
+
 	r.POST("/init", registerInitServerHandler(opts.ConfigService, opts.UserService))
 
 	requireProdMode := requireServerMode(model.ModeProd)
 
 	// Set up the MCP proxy server on /mcp
-	streamableHTTPServer := server.NewStreamableHTTPServer(opts.MCPProxyServer)
+	streamableHTTPServer := server.NewStreamableHTTPServer(
+		opts.MCPProxyServer,
+		server.WithSessionIdManager(sessions.ForEndpoint("/mcp")),
+	)
 	r.Any(
 		"/mcp",
 		requireInitialized(opts.ConfigService),
@@ -134,7 +190,7 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		V0PathPrefix+"/groups/:name/mcp",
 		requireInitialized(opts.ConfigService),
 		checkAuthForMcpProxyAccess(opts.MCPClientService),
-		toolGroupMCPServerCallHandler(opts.ToolGroupService),
+		toolGroupMCPServerCallHandler(opts.ToolGroupService, sessions),
 	)
 
 	// Setup /v0 API endpoints
@@ -161,10 +217,17 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 	{
 		adminAPI.POST("/servers", registerServerHandler(opts.MCPService))
 		adminAPI.DELETE("/servers/:name", deregisterServerHandler(opts.MCPService))
+		adminAPI.GET("/servers/:name/logs", getServerLogsHandler(opts.MCPService))
 
 		adminAPI.POST("/tools/enable", enableToolsHandler(opts.MCPService))
 		adminAPI.POST("/tools/disable", disableToolsHandler(opts.MCPService))
 
+		// endpoints for managing the result caches of tools
+		adminAPI.GET("/tool-cache", listToolCachePoliciesHandler(opts.MCPService))
+		adminAPI.PUT("/tool-cache", setToolCachePolicyHandler(opts.MCPService))
+		adminAPI.DELETE("/tool-cache", deleteToolCachePolicyHandler(opts.MCPService))
+		adminAPI.POST("/tool-cache/clear", clearToolCacheHandler(opts.MCPService))
+
 		// endpoints for managing MCP clients (production mode only)
 		adminAPI.GET(
 			"/clients",
@@ -201,6 +264,44 @@ func newRouter(opts *ServerOptions) (*gin.Engine, error) {
 		adminAPI.GET("/tool-groups/:name", getToolGroupHandler(opts.ToolGroupService))
 		adminAPI.GET("/tool-groups", listToolGroupsHandler(opts.ToolGroupService))
 		adminAPI.DELETE("/tool-groups/:name", deleteToolGroupHandler(opts.ToolGroupService))
+
+		// endpoints for managing rate limits & quotas of tool calls made through the MCP proxy
+		adminAPI.GET("/rate-limits", listRateLimitsHandler(opts.RateLimitService))
+		adminAPI.PUT("/rate-limits", setRateLimitHandler(opts.RateLimitService))
+		adminAPI.DELETE("/rate-limits", deleteRateLimitHandler(opts.RateLimitService))
+
+		// endpoints for inspecting the most recent tool calls
+		adminAPI.GET("/history", listToolCallsHandler(opts.HistoryService))
+		adminAPI.GET("/history/:id", getToolCallHandler(opts.HistoryService))
+
+		// endpoint for the usage statistics of tools
+		adminAPI.GET("/stats", getStatsHandler(opts.StatsService, opts.MCPService))
+
+		// endpoint for streaming events about changes in mcpjungle, which include quota usage of MCP clients
+		adminAPI.GET("/events", streamEventsHandler(opts.EventBus))
+
+		// endpoints for managing webhooks that receive events about changes in mcpjungle
+		adminAPI.POST("/webhooks", createWebhookHandler(opts.WebhookService))
+		adminAPI.GET("/webhooks", listWebhooksHandler(opts.WebhookService))
+		adminAPI.DELETE("/webhooks/:name", deleteWebhookHandler(opts.WebhookService))
+		adminAPI.GET("/webhooks/:name/deliveries", listWebhookDeliveriesHandler(opts.WebhookService))
+
+		// endpoints for making the registry match a declarative manifest and exporting it as one
+		adminAPI.POST("/reconcile", reconcileHandler(opts.ReconcileService))
+		adminAPI.GET("/export", exportHandler(opts.ReconcileService))
+
+		// endpoints for taking a backup of the registry and restoring it
+		adminAPI.GET("/backup", backupHandler(opts.BackupService))
+		adminAPI.POST("/restore", restoreHandler(opts.BackupService, settings))
+
+		// endpoints for viewing & changing the settings of the running server
+		adminAPI.GET("/config", getServerConfigHandler(opts.MCPService))
+		adminAPI.PUT("/config", updateServerConfigHandler(opts.ConfigService, settings))
+		adminAPI.POST(
+			"/config/switch-to-prod",
+			requireServerMode(model.ModeDev),
+			switchToProdHandler(opts.ConfigService),
+		)
 	}
 
 	return r, nil
@@ -1,10 +1,17 @@
 package api
 
 import (
+	"errors"
+	"io"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
 	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
 func registerInitServerHandler(configService *config.ServerConfigService, userService *user.UserService) gin.HandlerFunc {
@@ -47,3 +54,145 @@ func registerInitServerHandler(configService *config.ServerConfigService, userSe
 		c.JSON(200, payload)
 	}
 }
+
+// getServerConfigHandler returns the mode of the server and the settings currently in effect.
+func getServerConfigHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, currentServerConfig(c, mcpService))
+	}
+}
+
+// updateServerConfigHandler changes the settings that are set in the request body and applies them immediately.
+// The settings are stored, so they are also applied when the server restarts.
+// Settings listed in unset revert to the values the server was started with.
+func updateServerConfigHandler(configService *config.ServerConfigService, settings *settingsApplier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.UpdateServerSettingsInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		update := &model.GlobalSettings{
+			ServerSettings: model.ServerSettings{
+				CallTimeout:    input.CallTimeout,
+				MaxRetries:     input.MaxRetries,
+				RetryBackoff:   input.RetryBackoff,
+				MaxConcurrency: input.MaxConcurrency,
+				MaxQueue:       input.MaxQueue,
+				QueueTimeout:   input.QueueTimeout,
+			},
+			LogLevel: input.LogLevel,
+		}
+		stored, err := configService.UpdateSettings(update, input.Unset)
+		if err != nil {
+			if errors.Is(err, config.ErrInvalidSettings) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := settings.apply(stored); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "settings were saved but could not be applied: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, currentServerConfig(c, settings.mcpService))
+	}
+}
+
+// switchToProdHandler switches a server running in development mode to production mode.
+// The request must contain the confirmation token printed to the logs of the server.
+// It returns the access token of the new admin user and, if requested, of an MCP client
+// that can access all registered MCP servers.
+func switchToProdHandler(configService *config.ServerConfigService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.SwitchToProdInput
+		// the request body is optional
+		if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		admin, client, err := configService.SwitchToProd(input.ConfirmationToken, input.McpClient)
+		if err != nil {
+			if errors.Is(err, config.ErrInvalidConfirmationToken) {
+				c.JSON(http.StatusForbidden, gin.H{
+					"error": err.Error() + ", the confirmation token is printed to the logs of the server when it starts",
+				})
+				return
+			}
+			if errors.Is(err, config.ErrModeSwitchNotAllowed) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to switch to production mode: " + err.Error()})
+			return
+		}
+		resp := types.SwitchToProdResponse{AdminAccessToken: admin.AccessToken}
+		if client != nil {
+			resp.McpClientAccessToken = client.AccessToken
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// currentServerConfig returns the server mode along with the settings currently in effect.
+func currentServerConfig(c *gin.Context, mcpService *mcp.MCPService) types.ServerConfig {
+	mode, _ := c.Get("mode")
+	m, _ := mode.(model.ServerMode)
+
+	p := mcpService.GetDefaultCallPolicy()
+	return types.ServerConfig{
+		Mode: string(m),
+		Settings: types.ServerSettings{
+			CallTimeout:    p.Timeout.String(),
+			MaxRetries:     &p.MaxRetries,
+			RetryBackoff:   p.RetryBackoff.String(),
+			MaxConcurrency: &p.MaxConcurrency,
+			MaxQueue:       &p.MaxQueue,
+			QueueTimeout:   p.QueueTimeout.String(),
+			LogLevel:       logging.Level(),
+		},
+	}
+}
+
+// settingsApplier changes the settings of the running server.
+type settingsApplier struct {
+	mcpService    *mcp.MCPService
+	configService *config.ServerConfigService
+
+	// policy and logLevel are the settings the server was started with
+	policy   mcp.CallPolicy
+	logLevel string
+}
+
+// newSettingsApplier captures the current settings of the server as the ones it was started with.
+func newSettingsApplier(mcpService *mcp.MCPService, configService *config.ServerConfigService) *settingsApplier {
+	return &settingsApplier{
+		mcpService:    mcpService,
+		configService: configService,
+		policy:        mcpService.GetDefaultCallPolicy(),
+		logLevel:      logging.Level(),
+	}
+}
+
+// apply changes the settings of the running server to the given ones.
+// Settings that are not set revert to the values the server was started with.
+func (a *settingsApplier) apply(settings *model.GlobalSettings) error {
+	if err := a.mcpService.SetDefaultCallPolicy(a.policy.WithSettings(&settings.ServerSettings)); err != nil {
+		return err
+	}
+	level := a.logLevel
+	if settings.LogLevel != "" {
+		level = settings.LogLevel
+	}
+	return logging.SetLevel(level)
+}
+
+// reload applies the settings stored in the database.
+func (a *settingsApplier) reload() error {
+	settings, err := a.configService.GetSettings()
+	if err != nil {
+		return err
+	}
+	return a.apply(settings)
+}
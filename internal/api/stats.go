@@ -0,0 +1,89 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/stats"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// defaultStatsSince is the period covered by the statistics if the request doesn't specify one
+const defaultStatsSince = "7d"
+
+// parseStatsSince parses the period covered by the statistics.
+// Besides the usual Go durations (eg- "12h"), it accepts a number of days (eg- "7d").
+func parseStatsSince(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number of days '%s'", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration '%s'", s)
+	}
+	return d, nil
+}
+
+// getStatsHandler returns the tool call statistics since the given period, grouped by tool, server, client or day.
+// When grouping by tool, registered tools that weren't called at all are included with zero calls,
+// so that unused tools can be identified.
+func getStatsHandler(statsService *stats.StatsService, mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		by := types.StatsGrouping(c.DefaultQuery("by", string(types.StatsByTool)))
+		period, err := parseStatsSince(c.DefaultQuery("since", defaultStatsSince))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid 'since' query parameter: " + err.Error()})
+			return
+		}
+		q := stats.Query{
+			By:     by,
+			Since:  time.Now().Add(-period).UTC().Truncate(24 * time.Hour),
+			Server: c.Query("server"),
+			Tool:   c.Query("tool"),
+			Client: c.Query("client"),
+		}
+
+		result, err := statsService.GetStats(q)
+		if err != nil {
+			if errors.Is(err, stats.ErrInvalidGrouping) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if by == types.StatsByTool {
+			tools, err := mcpService.ListTools()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			called := make(map[string]bool, len(result))
+			for _, st := range result {
+				called[st.Key] = true
+			}
+			for _, t := range tools {
+				if called[t.Name] || (q.Tool != "" && t.Name != q.Tool) {
+					continue
+				}
+				if q.Server != "" && mcp.ServerOfTool(t.Name) != q.Server {
+					continue
+				}
+				result = append(result, types.ToolCallStats{Key: t.Name})
+			}
+		}
+
+		c.JSON(http.StatusOK, &types.StatsResponse{By: by, Since: q.Since, Stats: result})
+	}
+}
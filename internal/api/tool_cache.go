@@ -0,0 +1,102 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// listToolCachePoliciesHandler returns the cache policies of all tools along with the state of their caches.
+func listToolCachePoliciesHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		policies, err := mcpService.ListToolCachePolicies()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		resp := make([]*types.ToolCachePolicy, len(policies))
+		for i, p := range policies {
+			entries, hits, misses := mcpService.GetToolCacheStats(p.Tool)
+			resp[i] = &types.ToolCachePolicy{
+				Tool:       p.Tool,
+				TTL:        p.TTL,
+				MaxEntries: p.MaxEntries,
+				Entries:    entries,
+				Hits:       hits,
+				Misses:     misses,
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// setToolCachePolicyHandler enables caching for a tool or changes its cache settings.
+func setToolCachePolicyHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.ToolCachePolicy
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		p := &model.ToolCachePolicy{
+			Tool:       input.Tool,
+			TTL:        input.TTL,
+			MaxEntries: input.MaxEntries,
+		}
+		if err := p.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid cache policy: " + err.Error()})
+			return
+		}
+		if err := mcpService.SetToolCachePolicy(p); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, input)
+	}
+}
+
+// deleteToolCachePolicyHandler disables caching for the tool given as query parameter.
+func deleteToolCachePolicyHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tool := c.Query("tool")
+		if tool == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'tool' query parameter"})
+			return
+		}
+		if err := mcpService.DeleteToolCachePolicy(tool); err != nil {
+			if errors.Is(err, mcp.ErrToolCachePolicyNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// clearToolCacheHandler removes all cached results of the tool given as query parameter.
+func clearToolCacheHandler(mcpService *mcp.MCPService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tool := c.Query("tool")
+		if tool == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "missing 'tool' query parameter"})
+			return
+		}
+		n, err := mcpService.ClearToolCache(tool)
+		if err != nil {
+			if errors.Is(err, mcp.ErrToolCachePolicyNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, &types.ClearToolCacheResponse{Cleared: n})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/session"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 )
 
@@ -115,7 +116,9 @@ func deleteToolGroupHandler(toolGroupService *toolgroup.ToolGroupService) gin.Ha
 }
 
 // toolGroupMCPServerCallHandler handles incoming MCP requests from for a specific tool group.
-func toolGroupMCPServerCallHandler(toolGroupService *toolgroup.ToolGroupService) gin.HandlerFunc {
+func toolGroupMCPServerCallHandler(
+	toolGroupService *toolgroup.ToolGroupService, sessions *session.Manager,
+) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// get the Proxy MCP server for the specified tool group
 		groupName := c.Param("name")
@@ -130,7 +133,10 @@ func toolGroupMCPServerCallHandler(toolGroupService *toolgroup.ToolGroupService)
 		// This api sits in the host path because we expect high traffic on MCP tool calling.
 		// It is inefficient to create a new StreamableHTTPServer for each request.
 		// Maybe pre-create a StreamableHTTPServer for each tool group and store it in the ToolGroupMCPServer struct?
-		streamableServer := server.NewStreamableHTTPServer(groupMcpServer)
+		streamableServer := server.NewStreamableHTTPServer(
+			groupMcpServer,
+			server.WithSessionIdManager(sessions.ForEndpoint(fmt.Sprintf("%s/groups/%s/mcp", V0PathPrefix, groupName))),
+		)
 		streamableServer.ServeHTTP(c.Writer, c.Request)
 	}
 }
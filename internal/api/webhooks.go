@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// createWebhookHandler creates a new webhook.
+// The response contains the webhook's secret, which is not returned by any other API.
+func createWebhookHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var input types.Webhook
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body"})
+			return
+		}
+		w := &model.Webhook{
+			Name:   input.Name,
+			URL:    input.URL,
+			Secret: input.Secret,
+		}
+		if len(input.Events) > 0 {
+			eventTypes, _ := json.Marshal(input.Events)
+			w.EventTypes = eventTypes
+		}
+		if err := w.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook: " + err.Error()})
+			return
+		}
+		if err := webhookService.CreateWebhook(w); err != nil {
+			if errors.Is(err, webhook.ErrWebhookExists) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		input.Secret = w.Secret
+		c.JSON(http.StatusCreated, input)
+	}
+}
+
+// listWebhooksHandler returns all webhooks, without their secrets.
+func listWebhooksHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		webhooks, err := webhookService.ListWebhooks()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp := make([]*types.Webhook, len(webhooks))
+		for i := range webhooks {
+			eventTypes, err := webhooks[i].GetEventTypes()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			resp[i] = &types.Webhook{
+				Name:   webhooks[i].Name,
+				URL:    webhooks[i].URL,
+				Events: eventTypes,
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// deleteWebhookHandler deletes a webhook along with its delivery log.
+func deleteWebhookHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := webhookService.DeleteWebhook(c.Param("name")); err != nil {
+			if errors.Is(err, webhook.ErrWebhookNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// listWebhookDeliveriesHandler returns the most recent deliveries of events to a webhook.
+func listWebhookDeliveriesHandler(webhookService *webhook.WebhookService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		limit := defaultHistoryLimit
+		if l := c.Query("limit"); l != "" {
+			var err error
+			if limit, err = strconv.Atoi(l); err != nil || limit <= 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "'limit' must be a positive integer"})
+				return
+			}
+		}
+
+		deliveries, err := webhookService.ListDeliveries(c.Param("name"), limit)
+		if err != nil {
+			if errors.Is(err, webhook.ErrWebhookNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		resp := make([]*types.WebhookDelivery, len(deliveries))
+		for i, d := range deliveries {
+			resp[i] = &types.WebhookDelivery{
+				ID:         d.ID,
+				Time:       d.CreatedAt,
+				EventID:    d.EventID,
+				EventType:  d.EventType,
+				Status:     d.Status,
+				Attempts:   d.Attempts,
+				StatusCode: d.StatusCode,
+				Error:      d.Error,
+			}
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
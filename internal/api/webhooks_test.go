@@ -0,0 +1,37 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/service/webhook"
+)
+
+func TestCreateWebhookHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/webhooks", createWebhookHandler(webhook.NewWebhookService(dbtest.New(t))))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid", `{"name": "ops", "url": "https://example.com/hook"}`, http.StatusCreated},
+		{"duplicate name", `{"name": "ops", "url": "https://example.org/hook"}`, http.StatusConflict},
+		{"invalid", `{"name": "ops hook", "url": "https://example.com/hook"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d (body %s)", tt.name, w.Code, tt.wantStatus, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package changes keeps mcpjungle servers that share a database in sync.
+//
+// Every server keeps some of the registry in memory, eg- the tools served by the MCP proxy.
+// Whenever a server changes a scope of the registry, it increments the scope's counter in the database.
+// All servers poll the counters and reload a scope from the database when its counter has changed,
+// so a change made through one server reaches the others within one poll interval.
+package changes
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// Record increments the counters of the given scopes of the registry, notifying all servers of a change to them.
+func Record(db *gorm.DB, scopes ...model.RegistryScope) error {
+	err := db.Model(&model.RegistryVersion{}).Where("scope IN ?", scopes).Updates(map[string]any{
+		"version":    gorm.Expr("version + 1"),
+		"updated_at": time.Now(),
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to record change to the registry: %w", err)
+	}
+	return nil
+}
+
+// Versions returns the current counter of every scope of the registry.
+func Versions(db *gorm.DB) (map[model.RegistryScope]int64, error) {
+	var rows []model.RegistryVersion
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read registry versions: %w", err)
+	}
+	versions := make(map[model.RegistryScope]int64, len(rows))
+	for _, r := range rows {
+		versions[r.Scope] = r.Version
+	}
+	return versions, nil
+}
+
+// ReloadFunc reloads a scope of the registry from the database.
+type ReloadFunc func() error
+
+// Watcher polls the counters of the registry and reloads the scopes that have changed.
+type Watcher struct {
+	db *gorm.DB
+
+	// reloaders are called in the order they were registered, so that a scope can depend on another one
+	reloaders []reloader
+	// seen contains the counters of all scopes as of the last successful reload
+	seen map[model.RegistryScope]int64
+	mu   sync.Mutex
+}
+
+type reloader struct {
+	scope  model.RegistryScope
+	reload ReloadFunc
+}
+
+// NewWatcher creates a Watcher that considers the registry up to date as of now.
+// It must be created before the in-memory state is loaded from the database,
+// so that no change made in between is missed.
+func NewWatcher(db *gorm.DB) (*Watcher, error) {
+	seen, err := Versions(db)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{db: db, seen: seen}, nil
+}
+
+// OnChange registers a function that reloads the given scope when it has changed.
+func (w *Watcher) OnChange(scope model.RegistryScope, reload ReloadFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.reloaders = append(w.reloaders, reloader{scope: scope, reload: reload})
+}
+
+// Poll reloads all scopes that have changed since the last poll.
+// A scope whose reload failed is reloaded again on the next poll.
+func (w *Watcher) Poll() error {
+	versions, err := Versions(w.db)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, r := range w.reloaders {
+		if versions[r.scope] == w.seen[r.scope] {
+			continue
+		}
+		slog.Debug("reloading changed registry scope", "scope", r.scope, "version", versions[r.scope])
+		if err := r.reload(); err != nil {
+			return fmt.Errorf("failed to reload %s: %w", r.scope, err)
+		}
+		w.seen[r.scope] = versions[r.scope]
+	}
+	return nil
+}
+
+// Start polls the registry for changes in the background at the given interval.
+// Polling is disabled if the interval is zero. It stops when ctx is done.
+func (w *Watcher) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		slog.Info("watching the registry for changes made by other servers is disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := w.Poll(); err != nil {
+				slog.Error("failed to reload changes to the registry", logging.KeyError, err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,68 @@
+package changes
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	db := dbtest.New(t)
+	w, err := NewWatcher(db)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+
+	var reloaded []model.RegistryScope
+	var toolsErr error
+	w.OnChange(model.RegistryScopeTools, func() error {
+		reloaded = append(reloaded, model.RegistryScopeTools)
+		return toolsErr
+	})
+	w.OnChange(model.RegistryScopeToolGroups, func() error {
+		reloaded = append(reloaded, model.RegistryScopeToolGroups)
+		return nil
+	})
+
+	// nothing is reloaded if nothing has changed
+	if err := w.Poll(); err != nil || len(reloaded) != 0 {
+		t.Fatalf("expected nothing to be reloaded, got %v, %v", reloaded, err)
+	}
+
+	// only the changed scope is reloaded, and only once
+	if err := Record(db, model.RegistryScopeToolGroups); err != nil {
+		t.Fatalf("failed to record change: %v", err)
+	}
+	for range 2 {
+		if err := w.Poll(); err != nil {
+			t.Fatalf("failed to poll: %v", err)
+		}
+	}
+	if !slices.Equal(reloaded, []model.RegistryScope{model.RegistryScopeToolGroups}) {
+		t.Fatalf("expected only tool groups to be reloaded, got %v", reloaded)
+	}
+
+	// scopes are reloaded in the order their reload functions were registered
+	// and a failed reload is retried on the next poll
+	reloaded = nil
+	toolsErr = errors.New("boom")
+	if err := Record(db, model.RegistryScopes...); err != nil {
+		t.Fatalf("failed to record change: %v", err)
+	}
+	if err := w.Poll(); err == nil {
+		t.Fatal("expected the failed reload to be reported")
+	}
+	toolsErr = nil
+	if err := w.Poll(); err != nil {
+		t.Fatalf("failed to poll: %v", err)
+	}
+	want := []model.RegistryScope{
+		model.RegistryScopeTools, model.RegistryScopeTools, model.RegistryScopeToolGroups,
+	}
+	if !slices.Equal(reloaded, want) {
+		t.Errorf("expected reloads %v, got %v", want, reloaded)
+	}
+}
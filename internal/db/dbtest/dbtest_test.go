@@ -0,0 +1,25 @@
+package dbtest
+
+import (
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestDatabasesAreSeparate(t *testing.T) {
+	first, second := New(t), New(t)
+	if err := first.Create(&model.ServerConfig{Mode: model.ModeDev, Initialized: true}).Error; err != nil {
+		t.Fatalf("failed to create server config: %v", err)
+	}
+	var n int64
+	if err := second.Model(&model.ServerConfig{}).Count(&n).Error; err != nil {
+		t.Fatalf("failed to count server configs: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("second database has %d server configs, want it to be separate from the first one", n)
+	}
+
+	if empty := NewEmpty(t); empty.Migrator().HasTable(&model.ServerConfig{}) {
+		t.Error("NewEmpty() returned a database with tables")
+	}
+}
@@ -0,0 +1,68 @@
+// Package events distributes the events published by mcpjungle's services to their subscribers,
+// eg- to deliver webhooks.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Publisher publishes events about changes in mcpjungle.
+type Publisher interface {
+	Publish(t types.EventType, data map[string]any)
+}
+
+// Bus is an in-process Publisher that passes every event to all of its subscribers.
+type Bus struct {
+	subscribers map[uint64]func(e *types.Event)
+	nextID      uint64
+	mu          sync.RWMutex
+}
+
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[uint64]func(e *types.Event))}
+}
+
+// Publish passes a new event to all subscribers.
+// Subscribers are called synchronously, so they must not block.
+func (b *Bus) Publish(t types.EventType, data map[string]any) {
+	e := &types.Event{
+		ID:   newEventID(),
+		Type: t,
+		Time: time.Now().UTC(),
+		Data: data,
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, fn := range b.subscribers {
+		fn(e)
+	}
+}
+
+// Subscribe registers a function to be called with every event published from now on.
+// It returns a function that removes the subscription.
+func (b *Bus) Subscribe(fn func(e *types.Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = fn
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// newEventID returns a random ID for an event.
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,39 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestBusSubscribeAndUnsubscribe(t *testing.T) {
+	b := NewBus()
+	var first, second []*types.Event
+	unsubscribeFirst := b.Subscribe(func(e *types.Event) { first = append(first, e) })
+	b.Subscribe(func(e *types.Event) { second = append(second, e) })
+
+	b.Publish(types.EventServerRegistered, map[string]any{"server": "github"})
+	unsubscribeFirst()
+	b.Publish(types.EventServerDeregistered, map[string]any{"server": "github"})
+
+	if len(first) != 1 {
+		t.Fatalf("unsubscribed subscriber received %d events, want 1", len(first))
+	}
+	if len(second) != 2 {
+		t.Fatalf("subscriber received %d events, want 2", len(second))
+	}
+	e := first[0]
+	if e.Type != types.EventServerRegistered || e.Data["server"] != "github" || e.Time.IsZero() {
+		t.Errorf("unexpected event %+v", e)
+	}
+	// every subscriber receives the same event, but every event has an ID of its own
+	if second[0] != e {
+		t.Errorf("subscribers received different events %+v and %+v", e, second[0])
+	}
+	if e.ID == "" || e.ID == second[1].ID {
+		t.Errorf("events have IDs %q and %q, want distinct IDs", e.ID, second[1].ID)
+	}
+
+	// unsubscribing twice is harmless
+	unsubscribeFirst()
+}
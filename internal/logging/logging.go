@@ -0,0 +1,91 @@
+// Package logging configures structured, leveled logging for the MCPJungle server.
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// Supported log output formats.
+const (
+	// FormatText writes logs as human-readable key=value pairs.
+	FormatText = "text"
+	// FormatJSON writes every log line as a JSON object, which is ideal for log aggregation systems.
+	FormatJSON = "json"
+)
+
+// Keys of the attributes attached to log lines.
+// These are shared across the codebase so that log lines about the same entity can be easily correlated.
+const (
+	KeyServer = "server"
+	KeyTool   = "tool"
+	KeyClient = "client"
+	KeyError  = "error"
+)
+
+// level holds the current minimum log level.
+// It is a slog.LevelVar so that it can be changed while the server is running.
+var level = new(slog.LevelVar)
+
+// Init sets up the default slog logger with the given output format and minimum level.
+// Logs written using the standard library's log package are also routed through this logger.
+func Init(format, lvl string) error {
+	if err := SetLevel(lvl); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		h = slog.NewTextHandler(os.Stderr, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf(
+			"unsupported log format '%s' (acceptable values: '%s', '%s')", format, FormatText, FormatJSON,
+		)
+	}
+
+	slog.SetDefault(slog.New(h))
+	return nil
+}
+
+// SetLevel changes the minimum level of logs that are written.
+// Acceptable values are "debug", "info", "warn" and "error" (case-insensitive).
+// An empty value sets the level to info.
+func SetLevel(lvl string) error {
+	l, err := parseLevel(lvl)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// ValidateLevel returns an error if the given log level is not supported by SetLevel.
+func ValidateLevel(lvl string) error {
+	_, err := parseLevel(lvl)
+	return err
+}
+
+func parseLevel(lvl string) (slog.Level, error) {
+	if lvl == "" {
+		return slog.LevelInfo, nil
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(lvl)); err != nil {
+		return l, fmt.Errorf(
+			"unsupported log level '%s' (acceptable values: 'debug', 'info', 'warn', 'error')", lvl,
+		)
+	}
+	return l, nil
+}
+
+// Level returns the current minimum log level in lower case, eg- "info".
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
@@ -0,0 +1,244 @@
+// Package metrics provides Prometheus instrumentation for the MCPJungle server.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "mcpjungle"
+
+// Outcomes of a tool call, used as the value of the "outcome" label.
+const (
+	// OutcomeSuccess means that the upstream tool was called and it did not report an error.
+	OutcomeSuccess = "success"
+	// OutcomeToolError means that the upstream tool was called but its result has IsError set.
+	OutcomeToolError = "tool_error"
+	// OutcomeError means that mcpjungle failed to get a result from the upstream MCP server.
+	OutcomeError = "error"
+	// OutcomeRejected means that mcpjungle rejected the call without contacting the upstream MCP server.
+	OutcomeRejected = "rejected"
+)
+
+// circuitBreakerStates are all the states a circuit breaker can be in.
+var circuitBreakerStates = []string{"closed", "open", "half_open"}
+
+// registry is the Prometheus registry that holds all mcpjungle metrics.
+// A dedicated registry is used instead of the global default one so that
+// only metrics explicitly defined here (plus Go runtime & process metrics) are exposed.
+var registry = prometheus.NewRegistry()
+
+var (
+	toolCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_total",
+			Help:      "Total number of tool calls forwarded to upstream MCP servers.",
+		},
+		[]string{"server", "tool", "client", "outcome"},
+	)
+
+	toolCallDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "tool_call_duration_seconds",
+			Help:      "Time taken to serve a tool call, including upstream session setup.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+		},
+		[]string{"server", "tool", "client", "outcome"},
+	)
+
+	upstreamSessionInitDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_session_init_duration_seconds",
+			Help:      "Time taken by the MCP initialize handshake with an upstream MCP server.",
+			Buckets:   []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		},
+		[]string{"server", "transport", "outcome"},
+	)
+
+	activeSessions = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "mcp_active_sessions",
+			Help:      "Number of MCP sessions opened on this server that have not been terminated or expired yet, per proxy endpoint.",
+		},
+		[]string{"endpoint"},
+	)
+
+	stdioProcessStartsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "stdio_process_starts_total",
+			Help:      "Total number of times a stdio MCP server process was (re)started.",
+		},
+		[]string{"server"},
+	)
+
+	circuitBreakerState = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "circuit_breaker_state",
+			Help:      "Current state of the circuit breaker of an upstream MCP server (1 for the current state, 0 otherwise).",
+		},
+		[]string{"server", "state"},
+	)
+
+	serverActiveCalls = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "server_active_calls",
+			Help:      "Number of tool calls in progress on an upstream MCP server whose concurrency is limited.",
+		},
+		[]string{"server"},
+	)
+
+	serverQueuedCalls = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "server_queued_calls",
+			Help:      "Number of tool calls waiting for a free slot on an upstream MCP server whose concurrency is limited.",
+		},
+		[]string{"server"},
+	)
+
+	toolCacheLookupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_cache_lookups_total",
+			Help:      "Total number of lookups in the result cache of tools that have caching enabled, by result (hit or miss).",
+		},
+		[]string{"server", "tool", "result"},
+	)
+
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests served by mcpjungle, per route.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+)
+
+// metricVec is a metric with labels, which can be reset.
+type metricVec interface {
+	prometheus.Collector
+	Reset()
+}
+
+// mcpjungleMetrics returns all metrics defined by mcpjungle.
+func mcpjungleMetrics() []metricVec {
+	return []metricVec{
+		toolCallsTotal,
+		toolCallDuration,
+		upstreamSessionInitDuration,
+		activeSessions,
+		stdioProcessStartsTotal,
+		circuitBreakerState,
+		serverActiveCalls,
+		serverQueuedCalls,
+		toolCacheLookupsTotal,
+		httpRequestDuration,
+	}
+}
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	for _, m := range mcpjungleMetrics() {
+		registry.MustRegister(m)
+	}
+}
+
+// NewTestRegistry resets all mcpjungle metrics and returns a registry that only contains them.
+// It lets tests check the metrics recorded by the code under test, eg- with the prometheus testutil package.
+// Tests using it must not run in parallel, because the metrics are shared by the whole process.
+func NewTestRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	for _, m := range mcpjungleMetrics() {
+		m.Reset()
+		r.MustRegister(m)
+	}
+	return r
+}
+
+// Handler returns the HTTP handler that serves all metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ObserveToolCall records the outcome and latency of a single tool call.
+func ObserveToolCall(server, tool, client, outcome string, d time.Duration) {
+	toolCallsTotal.WithLabelValues(server, tool, client, outcome).Inc()
+	toolCallDuration.WithLabelValues(server, tool, client, outcome).Observe(d.Seconds())
+}
+
+// ObserveUpstreamSessionInit records the latency of the initialize handshake with an upstream MCP server.
+func ObserveUpstreamSessionInit(server, transport string, err error, d time.Duration) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeError
+	}
+	upstreamSessionInitDuration.WithLabelValues(server, transport, outcome).Observe(d.Seconds())
+}
+
+// SetActiveSessions records the number of active MCP sessions opened on this server on the given proxy endpoint.
+func SetActiveSessions(endpoint string, n int) {
+	activeSessions.WithLabelValues(endpoint).Set(float64(n))
+}
+
+// IncStdioProcessStarts records that a new process was started for the given stdio MCP server.
+func IncStdioProcessStarts(server string) {
+	stdioProcessStartsTotal.WithLabelValues(server).Inc()
+}
+
+// ObserveHTTPRequest records the latency of an HTTP request served by mcpjungle.
+func ObserveHTTPRequest(method, route, status string, d time.Duration) {
+	httpRequestDuration.WithLabelValues(method, route, status).Observe(d.Seconds())
+}
+
+// SetCircuitBreakerState records the current state of the circuit breaker of the given MCP server.
+func SetCircuitBreakerState(server, state string) {
+	for _, st := range circuitBreakerStates {
+		v := 0.0
+		if st == state {
+			v = 1
+		}
+		circuitBreakerState.WithLabelValues(server, st).Set(v)
+	}
+}
+
+// DeleteCircuitBreakerState stops reporting the circuit breaker state of the given MCP server.
+func DeleteCircuitBreakerState(server string) {
+	circuitBreakerState.DeletePartialMatch(prometheus.Labels{"server": server})
+}
+
+// SetServerCalls records the number of tool calls in progress and waiting for the given MCP server.
+func SetServerCalls(server string, active, queued int) {
+	serverActiveCalls.WithLabelValues(server).Set(float64(active))
+	serverQueuedCalls.WithLabelValues(server).Set(float64(queued))
+}
+
+// DeleteServerCalls stops reporting the tool calls in progress and waiting for the given MCP server.
+func DeleteServerCalls(server string) {
+	serverActiveCalls.DeleteLabelValues(server)
+	serverQueuedCalls.DeleteLabelValues(server)
+}
+
+// ObserveToolCacheLookup records whether a result was found in the cache of the given tool.
+func ObserveToolCacheLookup(server, tool string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	toolCacheLookupsTotal.WithLabelValues(server, tool, result).Inc()
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveToolCall(t *testing.T) {
+	reg := NewTestRegistry()
+	ObserveToolCall("github", "create_pr", "cursor", OutcomeSuccess, 200*time.Millisecond)
+	ObserveToolCall("github", "create_pr", "cursor", OutcomeSuccess, 3*time.Second)
+	ObserveToolCall("github", "create_pr", "cursor", OutcomeError, time.Second)
+
+	if got := testutil.ToFloat64(toolCallsTotal.WithLabelValues("github", "create_pr", "cursor", OutcomeSuccess)); got != 2 {
+		t.Errorf("successful calls = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(toolCallsTotal.WithLabelValues("github", "create_pr", "cursor", OutcomeError)); got != 1 {
+		t.Errorf("failed calls = %v, want 1", got)
+	}
+
+	// one histogram per combination of labels
+	if n := testutil.CollectAndCount(toolCallDuration); n != 2 {
+		t.Errorf("%d tool call duration histograms were recorded, want 2", n)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	found := false
+	for _, f := range families {
+		if f.GetName() != "mcpjungle_tool_call_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() != "outcome" || l.GetValue() != OutcomeSuccess {
+					continue
+				}
+				found = true
+				h := m.GetHistogram()
+				if h.GetSampleCount() != 2 || h.GetSampleSum() != 3.2 {
+					t.Errorf("histogram has %d samples summing to %v, want 2 summing to 3.2",
+						h.GetSampleCount(), h.GetSampleSum())
+				}
+			}
+		}
+	}
+	if !found {
+		t.Error("no duration histogram was recorded for successful calls")
+	}
+
+	// a new test registry starts from scratch
+	NewTestRegistry()
+	if n := testutil.CollectAndCount(toolCallsTotal); n != 0 {
+		t.Errorf("%d tool call counters remain after reset, want 0", n)
+	}
+}
@@ -0,0 +1,216 @@
+package migrations
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// initialSchema creates the tables of all models.
+// Before versioned migrations were introduced, the schema was only managed by GORM's AutoMigrate.
+// Because AutoMigrate never removes anything, this migration also brings such databases up to date.
+var initialSchema = Migration{
+	Version: 1,
+	Name:    "initial schema",
+	Up: func(tx *gorm.DB) error {
+		for _, m := range initialSchemaModels {
+			if err := tx.AutoMigrate(m.model); err != nil {
+				return fmt.Errorf("auto‑migration failed for %s model: %v", m.name, err)
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		for i := len(initialSchemaModels) - 1; i >= 0; i-- {
+			if err := tx.Migrator().DropTable(initialSchemaModels[i].model); err != nil {
+				return fmt.Errorf("failed to drop table of %s model: %v", initialSchemaModels[i].name, err)
+			}
+		}
+		return nil
+	},
+}
+
+var initialSchemaModels = []struct {
+	name  string
+	model any
+}{
+	{"McpServer", &v1McpServer{}},
+	{"Tool", &v1Tool{}},
+	{"ServerConfig", &v1ServerConfig{}},
+	{"User", &v1User{}},
+	{"McpClient", &v1McpClient{}},
+	{"ToolGroup", &v1ToolGroup{}},
+	{"RateLimit", &v1RateLimit{}},
+	{"QuotaUsage", &v1QuotaUsage{}},
+	{"ToolCachePolicy", &v1ToolCachePolicy{}},
+	{"ToolCallRecord", &v1ToolCallRecord{}},
+	{"ToolCallStat", &v1ToolCallStat{}},
+	{"Webhook", &v1Webhook{}},
+	{"WebhookDelivery", &v1WebhookDelivery{}},
+}
+
+// The models below are copies of the application's models as of this migration.
+
+type v1McpServer struct {
+	gorm.Model
+
+	Name        indexedString `gorm:"uniqueIndex;not null"`
+	Transport   string        `gorm:"type:varchar(30);not null"`
+	Description string
+	Config      jsonbColumn `gorm:"not null"`
+	Settings    jsonbColumn
+}
+
+func (v1McpServer) TableName() string { return "mcp_servers" }
+
+type v1Tool struct {
+	gorm.Model
+
+	Name        string `gorm:"not null"`
+	Enabled     bool   `gorm:"default:true"`
+	Description string
+	InputSchema jsonbColumn
+	ServerID    uint        `gorm:"not null"`
+	Server      v1McpServer `gorm:"foreignKey:ServerID;references:ID"`
+}
+
+func (v1Tool) TableName() string { return "tools" }
+
+type v1ServerConfig struct {
+	gorm.Model
+
+	Mode        string `gorm:"type:varchar(12);not null"`
+	Initialized bool   `gorm:"not null;default:false"`
+	Settings    jsonbColumn
+}
+
+func (v1ServerConfig) TableName() string { return "server_configs" }
+
+type v1User struct {
+	gorm.Model
+
+	Username    string `gorm:"unique; not null"`
+	Role        string `gorm:"not null"`
+	AccessToken string `gorm:"unique; not null"`
+}
+
+func (v1User) TableName() string { return "users" }
+
+type v1McpClient struct {
+	gorm.Model
+
+	Name        indexedString `gorm:"uniqueIndex;not null"`
+	Description string
+	AccessToken string      `gorm:"unique; not null"`
+	AllowList   jsonbColumn `gorm:"not null"`
+}
+
+func (v1McpClient) TableName() string { return "mcp_clients" }
+
+type v1ToolGroup struct {
+	gorm.Model
+
+	Name          string `gorm:"unique; not null"`
+	Description   string
+	IncludedTools jsonbColumn `gorm:"not null"`
+}
+
+func (v1ToolGroup) TableName() string { return "tool_groups" }
+
+type v1RateLimit struct {
+	gorm.Model
+
+	Scope  indexedString `gorm:"uniqueIndex:idx_rate_limit_scope_target;not null"`
+	Target indexedString `gorm:"uniqueIndex:idx_rate_limit_scope_target;not null"`
+
+	RequestsPerMinute int
+	Burst             int
+	DailyQuota        int64
+	MonthlyQuota      int64
+}
+
+func (v1RateLimit) TableName() string { return "rate_limits" }
+
+type v1QuotaUsage struct {
+	ID uint `gorm:"primarykey"`
+
+	Scope  indexedString `gorm:"uniqueIndex:idx_quota_usage_period;not null"`
+	Target indexedString `gorm:"uniqueIndex:idx_quota_usage_period;not null"`
+	Period indexedString `gorm:"uniqueIndex:idx_quota_usage_period;not null"`
+
+	Calls     int64 `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
+
+func (v1QuotaUsage) TableName() string { return "quota_usages" }
+
+type v1ToolCachePolicy struct {
+	gorm.Model
+
+	Tool       indexedString `gorm:"uniqueIndex;not null"`
+	TTL        string        `gorm:"not null"`
+	MaxEntries int           `gorm:"not null"`
+}
+
+func (v1ToolCachePolicy) TableName() string { return "tool_cache_policies" }
+
+type v1ToolCallRecord struct {
+	ID        uint      `gorm:"primarykey"`
+	CreatedAt time.Time `gorm:"index"`
+
+	Server     string `gorm:"not null"`
+	Tool       string `gorm:"index;not null"`
+	Client     string `gorm:"index"`
+	Arguments  jsonbColumn
+	Result     jsonbColumn
+	Error      string
+	Outcome    string `gorm:"not null"`
+	DurationMs int64
+	Truncated  bool
+}
+
+func (v1ToolCallRecord) TableName() string { return "tool_call_records" }
+
+type v1ToolCallStat struct {
+	ID uint `gorm:"primarykey"`
+
+	Day    indexedString `gorm:"uniqueIndex:idx_tool_call_stat;not null"`
+	Server indexedString `gorm:"uniqueIndex:idx_tool_call_stat;not null"`
+	Tool   indexedString `gorm:"uniqueIndex:idx_tool_call_stat;not null"`
+	Client indexedString `gorm:"uniqueIndex:idx_tool_call_stat;not null"`
+
+	Calls            int64
+	Errors           int64
+	LatencyHistogram jsonbColumn
+	UpdatedAt        time.Time
+}
+
+func (v1ToolCallStat) TableName() string { return "tool_call_stats" }
+
+type v1Webhook struct {
+	gorm.Model
+
+	Name       indexedString `gorm:"uniqueIndex;not null"`
+	URL        string        `gorm:"not null"`
+	Secret     string        `gorm:"not null"`
+	EventTypes jsonbColumn
+}
+
+func (v1Webhook) TableName() string { return "webhooks" }
+
+type v1WebhookDelivery struct {
+	ID        uint `gorm:"primarykey"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	WebhookID  uint   `gorm:"index;not null"`
+	EventID    string `gorm:"not null"`
+	EventType  string `gorm:"not null"`
+	Status     string `gorm:"not null"`
+	Attempts   int
+	StatusCode int
+	Error      string
+}
+
+func (v1WebhookDelivery) TableName() string { return "webhook_deliveries" }
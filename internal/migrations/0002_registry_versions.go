@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// registryVersions creates the table of change counters used to keep mcpjungle servers that share a database in sync.
+// A row is created for every scope so that the counters only ever need to be incremented.
+// Existing rows are kept, so that the migration can be applied again if it failed halfway on MySQL.
+var registryVersions = Migration{
+	Version: 2,
+	Name:    "registry versions",
+	Up: func(tx *gorm.DB) error {
+		if err := tx.AutoMigrate(&v2RegistryVersion{}); err != nil {
+			return err
+		}
+		for _, s := range []string{"tools", "tool_groups"} {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&v2RegistryVersion{Scope: s, UpdatedAt: time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v2RegistryVersion{})
+	},
+}
+
+type v2RegistryVersion struct {
+	Scope     string `gorm:"primaryKey;size:191"`
+	Version   int64  `gorm:"not null"`
+	UpdatedAt time.Time
+}
+
+func (v2RegistryVersion) TableName() string { return "registry_versions" }
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// mcpSessions creates the table of MCP sessions, used when sessions are stored in the database.
+var mcpSessions = Migration{
+	Version: 3,
+	Name:    "mcp sessions",
+	Up: func(tx *gorm.DB) error {
+		return tx.AutoMigrate(&v3McpSession{})
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Migrator().DropTable(&v3McpSession{})
+	},
+}
+
+type v3McpSession struct {
+	ID         string `gorm:"primaryKey;size:191"`
+	Endpoint   string `gorm:"not null"`
+	CreatedAt  time.Time
+	LastUsedAt time.Time `gorm:"index;not null"`
+}
+
+func (v3McpSession) TableName() string { return "mcp_sessions" }
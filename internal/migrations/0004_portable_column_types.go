@@ -0,0 +1,51 @@
+package migrations
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// portableColumnTypes limits the length of the string columns covered by unique indexes to 191 characters,
+// the longest a MySQL index can cover, so that the schema is the same on all databases.
+// MySQL databases were created with these limits already and SQLite ignores them,
+// so the columns only need to be changed on PostgreSQL.
+// Changing a column to the type it already has does nothing, so the migration can be applied again.
+var portableColumnTypes = Migration{
+	Version: 4,
+	Name:    "portable column types",
+	Up: func(tx *gorm.DB) error {
+		return alterIndexedColumns(tx, "varchar(191)")
+	},
+	Down: func(tx *gorm.DB) error {
+		return alterIndexedColumns(tx, "text")
+	},
+}
+
+// indexedColumns contains the columns changed by portableColumnTypes by their table.
+var indexedColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"mcp_servers", []string{"name"}},
+	{"mcp_clients", []string{"name"}},
+	{"rate_limits", []string{"scope", "target"}},
+	{"quota_usages", []string{"scope", "target", "period"}},
+	{"tool_cache_policies", []string{"tool"}},
+	{"tool_call_stats", []string{"day", "server", "tool", "client"}},
+	{"webhooks", []string{"name"}},
+}
+
+func alterIndexedColumns(tx *gorm.DB, columnType string) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, t := range indexedColumns {
+		for _, c := range t.columns {
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", t.table, c, columnType)).Error; err != nil {
+				return fmt.Errorf("failed to change type of %s.%s: %w", t.table, c, err)
+			}
+		}
+	}
+	return nil
+}
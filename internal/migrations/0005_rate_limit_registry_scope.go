@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// rateLimitRegistryScope adds the change counter of rate limits,
+// which mcpjungle servers sharing a database keep in memory since this version.
+var rateLimitRegistryScope = Migration{
+	Version: 5,
+	Name:    "rate limit registry scope",
+	Up: func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&v2RegistryVersion{Scope: "rate_limits", UpdatedAt: time.Now()}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Delete(&v2RegistryVersion{Scope: "rate_limits"}).Error
+	},
+}
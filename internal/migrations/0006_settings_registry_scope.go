@@ -0,0 +1,21 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// settingsRegistryScope adds the change counter of the global settings,
+// which mcpjungle servers sharing a database apply as soon as they change since this version.
+var settingsRegistryScope = Migration{
+	Version: 6,
+	Name:    "settings registry scope",
+	Up: func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&v2RegistryVersion{Scope: "settings", UpdatedAt: time.Now()}).Error
+	},
+	Down: func(tx *gorm.DB) error {
+		return tx.Delete(&v2RegistryVersion{Scope: "settings"}).Error
+	},
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// The models used by migrations are copies of the application's models as they were when the migration was written,
+// so that a migration keeps creating the same schema when the application's models change.
+// The column types below let such copies declare columns whose type depends on the database.
+
+// jsonbColumn is a JSON column. It is stored as jsonb, except on MySQL which only has a JSON type.
+type jsonbColumn []byte
+
+func (jsonbColumn) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	if db.Dialector.Name() == "mysql" {
+		return "JSON"
+	}
+	return "jsonb"
+}
+
+// indexedString is a string column covered by a unique index.
+// MySQL can't index text columns, so it is stored as a varchar there.
+type indexedString string
+
+func (indexedString) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+	if db.Dialector.Name() == "mysql" {
+		return "varchar(191)"
+	}
+	return ""
+}
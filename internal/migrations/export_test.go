@@ -0,0 +1,5 @@
+package migrations
+
+// All is exported for the tests of this package, which live in migrations_test
+// because they use dbtest, which imports this package.
+var All = all
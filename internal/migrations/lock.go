@@ -0,0 +1,78 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"gorm.io/gorm"
+)
+
+const (
+	// lockName identifies the lock held while migrating a MySQL database
+	lockName = "mcpjungle_migrations"
+	// lockID identifies the advisory lock held while migrating a PostgreSQL database
+	lockID = 0x6d63706a756e676c
+	// lockTimeoutSeconds is the maximum time to wait for another server to finish migrating a MySQL database
+	lockTimeoutSeconds = 300
+)
+
+// errAlreadyApplied is returned from the transaction of a migration that another server applied in the meantime.
+var errAlreadyApplied = errors.New("migration has already been applied")
+
+// withLock calls fn while holding a lock that prevents other mcpjungle servers from migrating the database at the same time,
+// eg- when several servers sharing a database are started together.
+// fn must only use the connection it is passed.
+func withLock(db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	switch db.Dialector.Name() {
+	case "postgres":
+		return db.Connection(func(conn *gorm.DB) error {
+			if err := conn.Exec("SELECT pg_advisory_lock(?)", lockID).Error; err != nil {
+				return fmt.Errorf("failed to lock database for migration: %w", err)
+			}
+			defer func() {
+				if err := conn.Exec("SELECT pg_advisory_unlock(?)", lockID).Error; err != nil {
+					slog.Warn("failed to unlock database after migration", logging.KeyError, err)
+				}
+			}()
+			return fn(conn)
+		})
+	case "mysql":
+		return db.Connection(func(conn *gorm.DB) error {
+			var locked int
+			if err := conn.Raw("SELECT GET_LOCK(?, ?)", lockName, lockTimeoutSeconds).Scan(&locked).Error; err != nil {
+				return fmt.Errorf("failed to lock database for migration: %w", err)
+			}
+			if locked != 1 {
+				return errors.New("timed out waiting for another server to finish migrating the database")
+			}
+			defer func() {
+				if err := conn.Exec("SELECT RELEASE_LOCK(?)", lockName).Error; err != nil {
+					slog.Warn("failed to unlock database after migration", logging.KeyError, err)
+				}
+			}()
+			return fn(conn)
+		})
+	default:
+		// SQLite has no such locks. Instead, the transaction of every migration starts by taking the write lock
+		// of the database (see lockSchemaVersion), so only one server at a time can apply a migration.
+		return fn(db)
+	}
+}
+
+// lockSchemaVersion makes the transaction tx wait until no other transaction is writing to the database.
+// This is needed on SQLite, whose transactions only take the write lock when they first write.
+// On other databases, withLock already serializes migrations and this does nothing.
+func lockSchemaVersion(tx *gorm.DB) error {
+	return tx.Exec("UPDATE schema_version SET name = name WHERE 1 = 0").Error
+}
+
+// isApplied returns true if the migration with the given version has been applied to the database.
+func isApplied(tx *gorm.DB, version int) (bool, error) {
+	var count int64
+	if err := tx.Model(&SchemaVersion{}).Where("version = ?", version).Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to read schema versions: %w", err)
+	}
+	return count > 0, nil
+}
@@ -1,32 +1,249 @@
 // Package migrations provides database migration functionality for the MCPJungle application.
+//
+// The schema is changed through numbered migrations that are applied in order.
+// The migrations applied to a database are recorded in its schema_version table.
+// A migration must never be changed once it has been released. To change the schema,
+// add a new migration with the next version number to the list in this file.
+// Migrations must be idempotent, because MySQL can't roll back a migration that failed halfway (see Up).
 package migrations
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"gorm.io/gorm"
 )
 
-// Migrate performs the database migration for the application.
+// Migration is a numbered change to the database schema.
+type Migration struct {
+	// Version is the number of the migration. Migrations are applied in increasing order of their versions.
+	Version int
+	// Name briefly describes the change, eg- "add index on tool calls"
+	Name string
+
+	// Up applies the change
+	Up func(tx *gorm.DB) error
+	// Down reverts the change. It is nil if the change can't be reverted.
+	Down func(tx *gorm.DB) error
+}
+
+// all contains every migration in the order they are applied.
+var all = []Migration{
+	initialSchema,
+	registryVersions,
+	mcpSessions,
+	portableColumnTypes,
+	rateLimitRegistryScope,
+	settingsRegistryScope,
+}
+
+// SchemaVersion records a migration that has been applied to the database.
+type SchemaVersion struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"not null"`
+	AppliedAt time.Time
+}
+
+// TableName overrides the default table name so that it reads naturally in the database.
+func (SchemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// MigrationStatus describes whether a migration has been applied to the database.
+type MigrationStatus struct {
+	Version int
+	Name    string
+
+	// AppliedAt is the time the migration was applied, nil if it is pending
+	AppliedAt *time.Time
+}
+
+// Migrate applies all pending migrations to the database.
 func Migrate(db *gorm.DB) error {
-	if err := db.AutoMigrate(&model.McpServer{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for McpServer model: %v", err)
+	_, err := Up(db)
+	return err
+}
+
+// Up applies all pending migrations in order and returns the ones that were applied.
+// Every migration is applied in its own transaction, so a failed migration leaves the database
+// at the version of the last successful one.
+// On MySQL, statements that change the schema commit the transaction implicitly, so a failed migration
+// may be left partially applied, though it isn't recorded as applied. Because migrations are idempotent,
+// applying the pending migrations again completes it.
+// The database is locked while migrating, so servers started at the same time never apply a migration twice.
+func Up(db *gorm.DB) ([]Migration, error) {
+	var done []Migration
+	err := withLock(db, func(conn *gorm.DB) error {
+		var err error
+		done, err = up(conn)
+		return err
+	})
+	return done, err
+}
+
+func up(db *gorm.DB) ([]Migration, error) {
+	if err := db.AutoMigrate(&SchemaVersion{}); err != nil {
+		// on SQLite, another server may have created the table in the meantime
+		if !db.Migrator().HasTable(&SchemaVersion{}) {
+			return nil, fmt.Errorf("failed to create schema_version table: %w", err)
+		}
+	}
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range all {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := lockSchemaVersion(tx); err != nil {
+				return err
+			}
+			// another server may have applied the migration while this one was waiting for the lock
+			ok, err := isApplied(tx, m.Version)
+			if err != nil {
+				return err
+			}
+			if ok {
+				return errAlreadyApplied
+			}
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaVersion{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if errors.Is(err, errAlreadyApplied) {
+			continue
+		}
+		if err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down reverts the given number of most recently applied migrations and returns the ones that were reverted.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("number of migrations to revert must be at least 1, got %d", steps)
 	}
-	if err := db.AutoMigrate(&model.Tool{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for Tool model: %v", err)
+	var done []Migration
+	err := withLock(db, func(conn *gorm.DB) error {
+		var err error
+		done, err = down(conn, steps)
+		return err
+	})
+	return done, err
+}
+
+func down(db *gorm.DB, steps int) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(all) - 1; i >= 0 && len(done) < steps; i-- {
+		m := all[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		if m.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) can't be reverted", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&SchemaVersion{Version: m.Version}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("reverting migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	if len(done) == 0 {
+		return nil, errors.New("no migrations have been applied")
+	}
+	return done, nil
+}
+
+// Status returns all migrations along with whether they have been applied to the database.
+func Status(db *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	status := make([]MigrationStatus, len(all))
+	for i, m := range all {
+		status[i] = MigrationStatus{Version: m.Version, Name: m.Name}
+		if v, ok := applied[m.Version]; ok {
+			status[i].AppliedAt = &v.AppliedAt
+		}
+	}
+	return status, nil
+}
+
+// Pending returns the migrations that have not been applied to the database yet.
+func Pending(db *gorm.DB) ([]Migration, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range all {
+		if _, ok := applied[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// CurrentVersion returns the version of the most recent migration applied to the database, 0 if none have been applied.
+func CurrentVersion(db *gorm.DB) (int, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return 0, err
 	}
-	if err := db.AutoMigrate(&model.ServerConfig{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for ServerConfig model: %v", err)
+	current := 0
+	for v := range applied {
+		current = max(current, v)
 	}
-	if err := db.AutoMigrate(&model.User{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for User model: %v", err)
+	return current, nil
+}
+
+// IsEmpty returns true if the database contains nothing of mcpjungle's,
+// ie, no migrations have been applied to it and it has none of mcpjungle's tables.
+func IsEmpty(db *gorm.DB) (bool, error) {
+	applied, err := appliedVersions(db)
+	if err != nil {
+		return false, err
+	}
+	if len(applied) > 0 {
+		return false, nil
+	}
+	// databases created before versioned migrations were introduced have no schema versions
+	return !db.Migrator().HasTable(&model.ServerConfig{}), nil
+}
+
+// appliedVersions returns the migrations applied to the database by their version.
+func appliedVersions(db *gorm.DB) (map[int]SchemaVersion, error) {
+	if !db.Migrator().HasTable(&SchemaVersion{}) {
+		return map[int]SchemaVersion{}, nil
 	}
-	if err := db.AutoMigrate(&model.McpClient{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for McpClient model: %v", err)
+	var versions []SchemaVersion
+	if err := db.Find(&versions).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema versions: %w", err)
 	}
-	if err := db.AutoMigrate(&model.ToolGroup{}); err != nil {
-		return fmt.Errorf("auto‑migration failed for ToolGroup model: %v", err)
+	applied := make(map[int]SchemaVersion, len(versions))
+	for _, v := range versions {
+		applied[v.Version] = v
 	}
-	return nil
+	return applied, nil
 }
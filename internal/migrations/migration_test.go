@@ -0,0 +1,191 @@
+package migrations_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/db"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+func TestUpAndDown(t *testing.T) {
+	db := dbtest.NewEmpty(t)
+	if empty, err := migrations.IsEmpty(db); err != nil || !empty {
+		t.Fatalf("expected a new database to be empty, got %v, %v", empty, err)
+	}
+
+	applied, err := migrations.Up(db)
+	if err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	if len(applied) != len(migrations.All) {
+		t.Fatalf("expected %d migrations to be applied, got %d", len(migrations.All), len(applied))
+	}
+	if empty, _ := migrations.IsEmpty(db); empty {
+		t.Error("expected a migrated database not to be empty")
+	}
+	if !db.Migrator().HasTable(&model.McpServer{}) {
+		t.Error("expected the mcp_servers table to be created")
+	}
+
+	// applying the migrations again does nothing
+	applied, err = migrations.Up(db)
+	if err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected no migrations to be applied, got %d", len(applied))
+	}
+	latest := migrations.All[len(migrations.All)-1].Version
+	if v, err := migrations.CurrentVersion(db); err != nil || v != latest {
+		t.Errorf("expected the current version to be %d, got %d, %v", latest, v, err)
+	}
+	status, err := migrations.Status(db)
+	if err != nil {
+		t.Fatalf("failed to get migration status: %v", err)
+	}
+	for _, s := range status {
+		if s.AppliedAt == nil {
+			t.Errorf("expected migration %d to be applied", s.Version)
+		}
+	}
+
+	reverted, err := migrations.Down(db, len(migrations.All))
+	if err != nil {
+		t.Fatalf("failed to revert migrations: %v", err)
+	}
+	if len(reverted) != len(migrations.All) || reverted[0].Version != latest {
+		t.Errorf("expected all migrations to be reverted, latest first, got %+v", reverted)
+	}
+	if db.Migrator().HasTable(&model.McpServer{}) {
+		t.Error("expected the mcp_servers table to be dropped")
+	}
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		t.Fatalf("failed to get pending migrations: %v", err)
+	}
+	if len(pending) != len(migrations.All) {
+		t.Errorf("expected all migrations to be pending, got %d", len(pending))
+	}
+
+	if empty, _ := migrations.IsEmpty(db); !empty {
+		t.Error("expected a database with all migrations reverted to be empty")
+	}
+	if _, err := migrations.Down(db, 1); err == nil {
+		t.Error("expected an error when no migrations have been applied")
+	}
+}
+
+func TestUpgradeDatabaseWithoutSchemaVersion(t *testing.T) {
+	db := dbtest.NewEmpty(t)
+
+	// databases created by older versions of mcpjungle only have the tables created by AutoMigrate
+	if err := db.AutoMigrate(&model.ServerConfig{}); err != nil {
+		t.Fatalf("failed to create table: %v", err)
+	}
+	if err := db.Create(&model.ServerConfig{Mode: model.ModeDev, Initialized: true}).Error; err != nil {
+		t.Fatalf("failed to create server config: %v", err)
+	}
+	if empty, _ := migrations.IsEmpty(db); empty {
+		t.Fatal("expected a database with data not to be empty")
+	}
+
+	pending, err := migrations.Pending(db)
+	if err != nil {
+		t.Fatalf("failed to get pending migrations: %v", err)
+	}
+	if len(pending) != len(migrations.All) {
+		t.Fatalf("expected all migrations to be pending, got %d", len(pending))
+	}
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	var cfg model.ServerConfig
+	if err := db.First(&cfg).Error; err != nil || !cfg.Initialized {
+		t.Errorf("expected the existing server config to be kept, got %+v, %v", cfg, err)
+	}
+}
+
+func TestMigratedSchemaCoversModels(t *testing.T) {
+	db := dbtest.NewEmpty(t)
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	models := []any{
+		&model.McpServer{}, &model.Tool{}, &model.ServerConfig{}, &model.User{}, &model.McpClient{},
+		&model.ToolGroup{}, &model.RateLimit{}, &model.QuotaUsage{}, &model.ToolCachePolicy{},
+		&model.ToolCallRecord{}, &model.ToolCallStat{}, &model.Webhook{}, &model.WebhookDelivery{},
+		&model.RegistryVersion{}, &model.McpSession{},
+	}
+	for _, m := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			t.Fatalf("failed to parse model %T: %v", m, err)
+		}
+		if !db.Migrator().HasTable(m) {
+			t.Errorf("expected table %s of model %T to exist", stmt.Schema.Table, m)
+			continue
+		}
+		for _, f := range stmt.Schema.Fields {
+			if f.DBName != "" && !db.Migrator().HasColumn(m, f.DBName) {
+				t.Errorf("expected column %s.%s of model %T to exist", stmt.Schema.Table, f.DBName, m)
+			}
+		}
+	}
+}
+
+func TestMigrationsAreIdempotent(t *testing.T) {
+	db := dbtest.NewEmpty(t)
+	if err := migrations.Migrate(db); err != nil {
+		t.Fatalf("failed to apply migrations: %v", err)
+	}
+
+	// on MySQL, a migration that failed halfway isn't rolled back, so it must succeed when applied again
+	for _, m := range migrations.All {
+		if err := db.Transaction(m.Up); err != nil {
+			t.Errorf("migration %d (%s) failed when applied again: %v", m.Version, m.Name, err)
+		}
+	}
+}
+
+func TestConcurrentUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.db")
+	const servers = 4
+
+	var wg sync.WaitGroup
+	applied := make([]int, servers)
+	errs := make([]error, servers)
+	for i := range servers {
+		conn, err := db.NewDBConnection(db.Config{DSN: "sqlite://" + path})
+		if err != nil {
+			t.Fatalf("failed to open database: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done, err := migrations.Up(conn)
+			applied[i], errs[i] = len(done), err
+		}()
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range servers {
+		if errs[i] != nil {
+			t.Errorf("server %d failed to apply migrations: %v", i, errs[i])
+		}
+		total += applied[i]
+	}
+	if total != len(migrations.All) {
+		t.Errorf(
+			"expected every migration to be applied exactly once, got %d applications of %d migrations",
+			total, len(migrations.All),
+		)
+	}
+}
@@ -3,6 +3,8 @@ package model
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/datatypes"
@@ -30,18 +32,93 @@ type StdioConfig struct {
 	Env map[string]string `json:"env,omitempty"`
 }
 
+// ServerSettings contains the transport-independent settings that control how mcpjungle calls an MCP server.
+// Durations are stored in the format accepted by time.ParseDuration (eg- "30s", "1m").
+// Unset fields fall back to the global defaults of the mcpjungle server.
+type ServerSettings struct {
+	// CallTimeout is the maximum time allowed for a single attempt of a tool call,
+	// including setting up a session with the MCP server.
+	CallTimeout string `json:"call_timeout,omitempty"`
+
+	// MaxRetries is the number of times a tool call is retried if it fails due to a transport error.
+	// A tool call is never retried if the tool itself or the MCP server reports an error.
+	MaxRetries *int `json:"max_retries,omitempty"`
+
+	// RetryBackoff is the delay before the first retry. It doubles with every subsequent retry.
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+
+	// RetryOnTimeout allows retrying tool calls that timed out.
+	// The tool may have done its job before the call timed out, so this is only safe for idempotent tools.
+	RetryOnTimeout *bool `json:"retry_on_timeout,omitempty"`
+
+	// MaxConcurrency is the maximum number of tool calls in progress on the server at the same time.
+	// Zero means unlimited.
+	MaxConcurrency *int `json:"max_concurrency,omitempty"`
+
+	// MaxQueue is the maximum number of tool calls waiting for a free slot when MaxConcurrency is reached.
+	MaxQueue *int `json:"max_queue,omitempty"`
+
+	// QueueTimeout is the maximum time a tool call waits for a free slot.
+	QueueTimeout string `json:"queue_timeout,omitempty"`
+}
+
+// Validate checks that all durations in the settings are valid and that no value is negative.
+func (s *ServerSettings) Validate() error {
+	if s.CallTimeout != "" {
+		d, err := time.ParseDuration(s.CallTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid call_timeout '%s': %w", s.CallTimeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid call_timeout '%s': must be positive", s.CallTimeout)
+		}
+	}
+	if s.MaxRetries != nil && *s.MaxRetries < 0 {
+		return fmt.Errorf("invalid max_retries %d: must not be negative", *s.MaxRetries)
+	}
+	if s.RetryBackoff != "" {
+		d, err := time.ParseDuration(s.RetryBackoff)
+		if err != nil {
+			return fmt.Errorf("invalid retry_backoff '%s': %w", s.RetryBackoff, err)
+		}
+		if d < 0 {
+			return fmt.Errorf("invalid retry_backoff '%s': must not be negative", s.RetryBackoff)
+		}
+	}
+	if s.MaxConcurrency != nil && *s.MaxConcurrency < 0 {
+		return fmt.Errorf("invalid max_concurrency %d: must not be negative", *s.MaxConcurrency)
+	}
+	if s.MaxQueue != nil && *s.MaxQueue < 0 {
+		return fmt.Errorf("invalid max_queue %d: must not be negative", *s.MaxQueue)
+	}
+	if s.QueueTimeout != "" {
+		d, err := time.ParseDuration(s.QueueTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid queue_timeout '%s': %w", s.QueueTimeout, err)
+		}
+		if d <= 0 {
+			return fmt.Errorf("invalid queue_timeout '%s': must be positive", s.QueueTimeout)
+		}
+	}
+	return nil
+}
+
 // McpServer represents a MCP server registered in mcpjungle
 type McpServer struct {
 	gorm.Model
 
-	Name      string                   `json:"name" gorm:"uniqueIndex;not null"`
+	Name      string                   `json:"name" gorm:"uniqueIndex;size:191;not null"`
 	Transport types.McpServerTransport `json:"transport" gorm:"type:varchar(30);not null"`
 
 	Description string `json:"description"`
 
 	// Config describes the transport-specific configuration for the MCP server.
 	// It contains the JSON representation of either StreamableHTTPConfig or StdioConfig.
-	Config datatypes.JSON `json:"config" gorm:"type:jsonb;not null"`
+	Config datatypes.JSON `json:"config" gorm:"not null"`
+
+	// Settings contains the JSON representation of ServerSettings.
+	// It is empty if the server uses the global defaults for all settings.
+	Settings datatypes.JSON `json:"settings"`
 }
 
 // NewStreamableHTTPServer creates a new MCP server with streamable HTTP transport configuration.
@@ -88,6 +165,42 @@ func NewStdioServer(name, description, command string, args []string, env map[st
 	}, nil
 }
 
+// NewMcpServer creates a new MCP server from the input supplied to register it.
+// It validates the transport and settings of the server.
+func NewMcpServer(input *types.RegisterServerInput) (*McpServer, error) {
+	transport, err := types.ValidateTransport(input.Transport)
+	if err != nil {
+		return nil, err
+	}
+
+	var s *McpServer
+	if transport == types.TransportStreamableHTTP {
+		s, err = NewStreamableHTTPServer(input.Name, input.Description, input.URL, input.BearerToken)
+		if err != nil {
+			return nil, fmt.Errorf("error creating streamable http server: %w", err)
+		}
+	} else {
+		s, err = NewStdioServer(input.Name, input.Description, input.Command, input.Args, input.Env)
+		if err != nil {
+			return nil, fmt.Errorf("error creating stdio server: %w", err)
+		}
+	}
+
+	settings := &ServerSettings{
+		CallTimeout:    input.CallTimeout,
+		MaxRetries:     input.MaxRetries,
+		RetryBackoff:   input.RetryBackoff,
+		RetryOnTimeout: input.RetryOnTimeout,
+		MaxConcurrency: input.MaxConcurrency,
+		MaxQueue:       input.MaxQueue,
+		QueueTimeout:   input.QueueTimeout,
+	}
+	if err := s.SetSettings(settings); err != nil {
+		return nil, fmt.Errorf("invalid server settings: %w", err)
+	}
+	return s, nil
+}
+
 // GetStreamableHTTPConfig returns the configuration if this is a streamable HTTP server
 func (s *McpServer) GetStreamableHTTPConfig() (*StreamableHTTPConfig, error) {
 	if s.Transport != types.TransportStreamableHTTP {
@@ -111,3 +224,67 @@ func (s *McpServer) GetStdioConfig() (*StdioConfig, error) {
 	}
 	return &config, nil
 }
+
+// SetSettings validates the given settings and stores them in the server.
+func (s *McpServer) SetSettings(settings *ServerSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	s.Settings = settingsJSON
+	return nil
+}
+
+// GetSettings returns the settings of the server.
+// If no settings were stored, an empty ServerSettings is returned.
+func (s *McpServer) GetSettings() (*ServerSettings, error) {
+	var settings ServerSettings
+	if len(s.Settings) == 0 || string(s.Settings) == "null" {
+		return &settings, nil
+	}
+	if err := json.Unmarshal(s.Settings, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// ToInput converts the server back into the input that registers it, including its bearer token.
+func (s *McpServer) ToInput() (*types.RegisterServerInput, error) {
+	input := &types.RegisterServerInput{
+		Name:        s.Name,
+		Transport:   string(s.Transport),
+		Description: s.Description,
+	}
+	if s.Transport == types.TransportStreamableHTTP {
+		conf, err := s.GetStreamableHTTPConfig()
+		if err != nil {
+			return nil, err
+		}
+		input.URL = conf.URL
+		input.BearerToken = conf.BearerToken
+	} else {
+		conf, err := s.GetStdioConfig()
+		if err != nil {
+			return nil, err
+		}
+		input.Command = conf.Command
+		input.Args = conf.Args
+		input.Env = conf.Env
+	}
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		return nil, err
+	}
+	input.CallTimeout = settings.CallTimeout
+	input.MaxRetries = settings.MaxRetries
+	input.RetryBackoff = settings.RetryBackoff
+	input.RetryOnTimeout = settings.RetryOnTimeout
+	input.MaxConcurrency = settings.MaxConcurrency
+	input.MaxQueue = settings.MaxQueue
+	input.QueueTimeout = settings.QueueTimeout
+	return input, nil
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// McpSession is an MCP session opened by a client on one of mcpjungle's proxy endpoints.
+type McpSession struct {
+	// ID is the session ID sent to the client in the Mcp-Session-Id header
+	ID string `gorm:"primaryKey;size:191"`
+
+	// Endpoint is the path of the proxy endpoint the session was opened on, eg- "/mcp"
+	Endpoint string `gorm:"not null"`
+
+	CreatedAt time.Time
+
+	// LastUsedAt is the time of the most recent request made in the session.
+	// It is updated at most once a minute, to keep the number of writes low.
+	LastUsedAt time.Time `gorm:"index;not null"`
+}
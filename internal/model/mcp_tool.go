@@ -22,7 +22,7 @@ type Tool struct {
 	Description string `json:"description"`
 
 	// InputSchema is a JSON schema that describes the input parameters for the tool.
-	InputSchema datatypes.JSON `json:"input_schema" gorm:"type:jsonb"`
+	InputSchema datatypes.JSON `json:"input_schema"`
 
 	// ServerID is the ID of the MCP server that provides this tool.
 	ServerID uint      `json:"-" gorm:"not null"`
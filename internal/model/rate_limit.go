@@ -0,0 +1,61 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// RateLimit limits the tool calls made through the MCP proxy for a single client, server or tool.
+// There can be at most one rate limit per scope and target.
+type RateLimit struct {
+	gorm.Model
+
+	Scope  types.RateLimitScope `json:"scope" gorm:"uniqueIndex:idx_rate_limit_scope_target;size:191;not null"`
+	Target string               `json:"target" gorm:"uniqueIndex:idx_rate_limit_scope_target;size:191;not null"`
+
+	RequestsPerMinute int `json:"requests_per_minute"`
+	Burst             int `json:"burst"`
+
+	DailyQuota   int64 `json:"daily_quota"`
+	MonthlyQuota int64 `json:"monthly_quota"`
+}
+
+// Validate checks that the rate limit has a valid scope and target and enforces at least one limit.
+func (r *RateLimit) Validate() error {
+	switch r.Scope {
+	case types.RateLimitScopeClient, types.RateLimitScopeServer, types.RateLimitScopeTool:
+	default:
+		return fmt.Errorf("invalid rate limit scope %q, must be one of client, server or tool", r.Scope)
+	}
+	if r.Target == "" {
+		return errors.New("rate limit target must not be empty")
+	}
+	if r.RequestsPerMinute < 0 || r.Burst < 0 || r.DailyQuota < 0 || r.MonthlyQuota < 0 {
+		return errors.New("rate limits and quotas must not be negative")
+	}
+	if r.Burst > 0 && r.RequestsPerMinute == 0 {
+		return errors.New("burst can only be set along with requests per minute")
+	}
+	if r.RequestsPerMinute == 0 && r.DailyQuota == 0 && r.MonthlyQuota == 0 {
+		return errors.New("at least one of requests per minute, daily quota or monthly quota must be set")
+	}
+	return nil
+}
+
+// QuotaUsage counts the tool calls made against a rate limit's quota in a single period.
+// Counts are persisted so that quotas survive restarts of mcpjungle.
+type QuotaUsage struct {
+	ID uint `gorm:"primarykey"`
+
+	Scope  types.RateLimitScope `gorm:"uniqueIndex:idx_quota_usage_period;size:191;not null"`
+	Target string               `gorm:"uniqueIndex:idx_quota_usage_period;size:191;not null"`
+	// Period identifies the day (eg, 2025-01-31) or month (eg, 2025-01) the calls were made in.
+	Period string `gorm:"uniqueIndex:idx_quota_usage_period;size:191;not null"`
+
+	Calls     int64 `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// RegistryScope is a part of the registry that every mcpjungle server keeps in memory.
+type RegistryScope string
+
+const (
+	// RegistryScopeTools covers MCP servers, their tools and the cache policies of tools
+	RegistryScopeTools RegistryScope = "tools"
+	// RegistryScopeToolGroups covers tool groups
+	RegistryScopeToolGroups RegistryScope = "tool_groups"
+	// RegistryScopeRateLimits covers rate limits & quotas, but not the usage counted against them
+	RegistryScopeRateLimits RegistryScope = "rate_limits"
+	// RegistryScopeSettings covers the global settings changed through the API
+	RegistryScopeSettings RegistryScope = "settings"
+)
+
+// RegistryScopes contains all scopes of the registry.
+var RegistryScopes = []RegistryScope{
+	RegistryScopeTools, RegistryScopeToolGroups, RegistryScopeRateLimits, RegistryScopeSettings,
+}
+
+// RegistryVersion counts the changes made to a scope of the registry.
+// When several mcpjungle servers share a database, each of them watches these counters
+// and reloads a scope from the database when its counter changes.
+type RegistryVersion struct {
+	Scope RegistryScope `gorm:"primaryKey;size:191"`
+
+	// Version is incremented on every change to the scope
+	Version int64 `gorm:"not null"`
+
+	UpdatedAt time.Time
+}
@@ -1,8 +1,10 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -26,6 +28,70 @@ type ServerConfig struct {
 	// Initialized indicates whether the server has been initialized.
 	// If this is set to false, the server is not yet ready for use and all requests to it should be rejected.
 	Initialized bool `gorm:"not null;default:false"`
+
+	// Settings contains the JSON representation of GlobalSettings.
+	Settings datatypes.JSON
+}
+
+// GlobalSettings are the settings of the mcpjungle server that can be changed while it is running.
+// Unset fields fall back to the values given to the `start` command.
+type GlobalSettings struct {
+	// ServerSettings are the default call settings of all MCP servers that don't override them
+	ServerSettings
+
+	// LogLevel is the minimum level of the server logs, eg- "debug"
+	LogLevel string `json:"log_level,omitempty"`
+}
+
+// Merge overrides the settings with the fields that are set in other.
+func (s *GlobalSettings) Merge(other *GlobalSettings) {
+	if other.CallTimeout != "" {
+		s.CallTimeout = other.CallTimeout
+	}
+	if other.MaxRetries != nil {
+		s.MaxRetries = other.MaxRetries
+	}
+	if other.RetryBackoff != "" {
+		s.RetryBackoff = other.RetryBackoff
+	}
+	if other.MaxConcurrency != nil {
+		s.MaxConcurrency = other.MaxConcurrency
+	}
+	if other.MaxQueue != nil {
+		s.MaxQueue = other.MaxQueue
+	}
+	if other.QueueTimeout != "" {
+		s.QueueTimeout = other.QueueTimeout
+	}
+	if other.LogLevel != "" {
+		s.LogLevel = other.LogLevel
+	}
+}
+
+// Unset clears the given settings, so that they fall back to the values given to the `start` command.
+// Settings are named like their JSON fields, eg- "call_timeout".
+func (s *GlobalSettings) Unset(names []string) error {
+	for _, name := range names {
+		switch name {
+		case "call_timeout":
+			s.CallTimeout = ""
+		case "max_retries":
+			s.MaxRetries = nil
+		case "retry_backoff":
+			s.RetryBackoff = ""
+		case "max_concurrency":
+			s.MaxConcurrency = nil
+		case "max_queue":
+			s.MaxQueue = nil
+		case "queue_timeout":
+			s.QueueTimeout = ""
+		case "log_level":
+			s.LogLevel = ""
+		default:
+			return fmt.Errorf("unknown setting: %s", name)
+		}
+	}
+	return nil
 }
 
 func (c *ServerConfig) BeforeSave(tx *gorm.DB) (err error) {
@@ -35,3 +101,27 @@ func (c *ServerConfig) BeforeSave(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// SetSettings stores the given settings in the config.
+// The settings must be validated by the caller.
+func (c *ServerConfig) SetSettings(settings *GlobalSettings) error {
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	c.Settings = settingsJSON
+	return nil
+}
+
+// GetSettings returns the settings stored in the config.
+// If no settings were stored, an empty GlobalSettings is returned.
+func (c *ServerConfig) GetSettings() (*GlobalSettings, error) {
+	var settings GlobalSettings
+	if len(c.Settings) == 0 || string(c.Settings) == "null" {
+		return &settings, nil
+	}
+	if err := json.Unmarshal(c.Settings, &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
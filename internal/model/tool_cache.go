@@ -0,0 +1,43 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ToolCachePolicy enables caching of the results of a tool.
+// Caching is opt-in and should only be enabled for tools that always return the same result for the same arguments.
+type ToolCachePolicy struct {
+	gorm.Model
+
+	// Tool is the canonical name of the tool (ie, including the server name prefix)
+	Tool string `json:"tool" gorm:"uniqueIndex;size:191;not null"`
+
+	// TTL is the duration for which a cached result is served (eg- "5m")
+	TTL string `json:"ttl" gorm:"not null"`
+
+	// MaxEntries is the maximum number of results cached for the tool.
+	// Once reached, the least recently used result is evicted.
+	MaxEntries int `json:"max_entries" gorm:"not null"`
+}
+
+// Validate checks that the policy has a positive TTL and max entries.
+func (p *ToolCachePolicy) Validate() error {
+	if p.Tool == "" {
+		return errors.New("tool name must not be empty")
+	}
+	d, err := time.ParseDuration(p.TTL)
+	if err != nil {
+		return fmt.Errorf("invalid ttl '%s': %w", p.TTL, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("invalid ttl '%s': must be positive", p.TTL)
+	}
+	if p.MaxEntries <= 0 {
+		return fmt.Errorf("invalid max_entries %d: must be positive", p.MaxEntries)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ToolCallRecord is the record of a single call to an upstream tool, kept for debugging purposes.
+// Only the most recent calls are retained.
+type ToolCallRecord struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+
+	// Server is the name of the MCP server that provides the tool
+	Server string `json:"server" gorm:"not null"`
+	// Tool is the canonical name of the tool (ie, including the server name prefix)
+	Tool string `json:"tool" gorm:"index;not null"`
+	// Client is the name of the MCP client that made the call
+	Client string `json:"client" gorm:"index"`
+
+	// Arguments are the arguments the tool was called with
+	Arguments datatypes.JSON `json:"arguments"`
+	// Result is the result returned by the tool, in the form returned by the HTTP API.
+	// It is empty if the call failed.
+	Result datatypes.JSON `json:"result"`
+	// Error is the reason the call failed, if it did
+	Error string `json:"error"`
+
+	// Outcome is the outcome of the call as reported in metrics (eg- "success", "tool_error")
+	Outcome    string `json:"outcome" gorm:"not null"`
+	DurationMs int64  `json:"duration_ms"`
+
+	// Truncated indicates that the arguments and/or the result were too large to be recorded and were dropped
+	Truncated bool `json:"truncated"`
+}
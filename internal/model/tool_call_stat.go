@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ToolCallStat aggregates the calls made by an MCP client to a tool on a single day.
+type ToolCallStat struct {
+	ID uint `json:"id" gorm:"primarykey"`
+
+	// Day is the UTC date of the calls, formatted as YYYY-MM-DD
+	Day    string `json:"day" gorm:"uniqueIndex:idx_tool_call_stat;size:191;not null"`
+	Server string `json:"server" gorm:"uniqueIndex:idx_tool_call_stat;size:191;not null"`
+	// Tool is the canonical name of the tool (ie, including the server name prefix)
+	Tool   string `json:"tool" gorm:"uniqueIndex:idx_tool_call_stat;size:191;not null"`
+	Client string `json:"client" gorm:"uniqueIndex:idx_tool_call_stat;size:191;not null"`
+
+	Calls int64 `json:"calls"`
+	// Errors is the number of calls that did not succeed, including calls whose result has IsError set
+	Errors int64 `json:"errors"`
+
+	// LatencyHistogram holds the number of calls in each latency bucket, as a JSON array.
+	// The bucket boundaries are defined by the stats service.
+	LatencyHistogram datatypes.JSON `json:"latency_histogram"`
+
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -17,7 +17,7 @@ type ToolGroup struct {
 
 	// IncludedTools contains a list of tool names that are included in this group.
 	// storing the list of tool names as a JSON array is a convenient way for now.
-	IncludedTools datatypes.JSON `json:"included_tools" gorm:"type:jsonb; not null"`
+	IncludedTools datatypes.JSON `json:"included_tools" gorm:"not null"`
 }
 
 // GetTools unmarshals the IncludedTools JSON array into a slice of strings.
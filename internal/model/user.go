@@ -1,4 +1,9 @@
 // Package model provides data models for the MCPJungle application.
+//
+// The models must work with all supported databases (SQLite, PostgreSQL and MySQL):
+//   - JSON columns use datatypes.JSON without an explicit column type, so that GORM picks the right one for each database.
+//   - String columns in a uniqueIndex specify a size of 191, because MySQL can't index TEXT columns.
+//     GORM only limits the size of columns tagged "unique" or "index" on its own.
 package model
 
 import (
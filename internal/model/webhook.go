@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// validWebhookName matches valid webhook names, which must be safe to use in URLs.
+var validWebhookName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// Webhook is an HTTP endpoint that mcpjungle sends events to.
+type Webhook struct {
+	gorm.Model
+
+	Name string `json:"name" gorm:"uniqueIndex;size:191;not null"`
+	URL  string `json:"url" gorm:"not null"`
+
+	// Secret is the key used to sign the payloads sent to the webhook, so that the receiver can verify them
+	Secret string `json:"-" gorm:"not null"`
+
+	// EventTypes contains the types of events the webhook is subscribed to, as a JSON array.
+	// The webhook receives all events if the list is empty.
+	EventTypes datatypes.JSON `json:"event_types"`
+}
+
+// GetEventTypes unmarshals the EventTypes JSON array into a slice of event types.
+func (w *Webhook) GetEventTypes() ([]types.EventType, error) {
+	if w.EventTypes == nil {
+		return []types.EventType{}, nil
+	}
+	var eventTypes []types.EventType
+	err := json.Unmarshal(w.EventTypes, &eventTypes)
+	return eventTypes, err
+}
+
+// Subscribed returns true if the webhook should receive events of the given type.
+func (w *Webhook) Subscribed(t types.EventType) bool {
+	eventTypes, err := w.GetEventTypes()
+	if err != nil {
+		return false
+	}
+	if len(eventTypes) == 0 {
+		return true
+	}
+	for _, et := range eventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that the webhook has a name, an absolute HTTP(S) URL and valid event types.
+func (w *Webhook) Validate() error {
+	if w.Name == "" {
+		return errors.New("webhook name must not be empty")
+	}
+	if !validWebhookName.MatchString(w.Name) {
+		return errors.New(
+			"invalid webhook name: name must start with an alphanumeric character and " +
+				"can only contain alphanumeric characters, underscores, and hyphens",
+		)
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid url '%s': must be an absolute http or https URL", w.URL)
+	}
+	eventTypes, err := w.GetEventTypes()
+	if err != nil {
+		return fmt.Errorf("invalid event types: %w", err)
+	}
+	for _, t := range eventTypes {
+		if !types.IsValidEventType(t) {
+			return fmt.Errorf("unknown event type '%s'", t)
+		}
+	}
+	return nil
+}
+
+// WebhookDelivery records the delivery of a single event to a webhook.
+type WebhookDelivery struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	WebhookID uint `json:"webhook_id" gorm:"index;not null"`
+
+	EventID   string          `json:"event_id" gorm:"not null"`
+	EventType types.EventType `json:"event_type" gorm:"not null"`
+
+	Status types.WebhookDeliveryStatus `json:"status" gorm:"not null"`
+	// Attempts is the number of times the event has been sent to the webhook so far
+	Attempts int `json:"attempts"`
+	// StatusCode is the HTTP status code of the response to the last attempt, 0 if no response was received
+	StatusCode int `json:"status_code"`
+	// Error describes why the last attempt failed, if it did
+	Error string `json:"error"`
+}
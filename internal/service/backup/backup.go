@@ -0,0 +1,356 @@
+// Package backup takes snapshots of the registry and restores them.
+// Backups are independent of the database, so a backup taken from a server that uses SQLite
+// can be restored into one that uses PostgreSQL or MySQL.
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/migrations"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidBackup is returned when a backup can't be restored because it is malformed or incompatible with the server.
+var ErrInvalidBackup = errors.New("invalid backup")
+
+// Backup is a snapshot of the registry.
+// It contains everything needed to recreate the registry, including the access tokens of MCP clients & users and
+// the secrets of MCP servers & webhooks, so it must be stored securely.
+// Tool call history, usage statistics, quota usage and webhook deliveries are not included.
+type Backup struct {
+	types.BackupHeader
+
+	// Settings contains the JSON representation of the global settings changed through the API
+	Settings datatypes.JSON `json:"settings,omitempty"`
+
+	McpServers        []model.McpServer       `json:"mcp_servers"`
+	Tools             []ToolRecord            `json:"tools"`
+	ToolGroups        []model.ToolGroup       `json:"tool_groups"`
+	McpClients        []model.McpClient       `json:"mcp_clients"`
+	Users             []model.User            `json:"users"`
+	RateLimits        []model.RateLimit       `json:"rate_limits"`
+	ToolCachePolicies []model.ToolCachePolicy `json:"tool_cache_policies"`
+	Webhooks          []WebhookRecord         `json:"webhooks"`
+}
+
+// ToolRecord is a tool along with the name of the MCP server that provides it.
+// Tools refer to their server by name because the IDs of servers change when they are restored.
+type ToolRecord struct {
+	model.Tool
+	ServerName string `json:"server_name"`
+}
+
+// WebhookRecord is a webhook along with its signing secret, which is never included in the webhook's own JSON.
+type WebhookRecord struct {
+	model.Webhook
+	Secret string `json:"secret"`
+}
+
+// BackupService takes backups of the registry and restores them.
+type BackupService struct {
+	db *gorm.DB
+
+	mcpService       *mcp.MCPService
+	toolGroupService *toolgroup.ToolGroupService
+	rateLimitService *ratelimit.RateLimitService
+
+	// mu ensures that a single backup is restored at a time
+	mu sync.Mutex
+}
+
+func NewBackupService(
+	db *gorm.DB,
+	mcpService *mcp.MCPService,
+	toolGroupService *toolgroup.ToolGroupService,
+	rateLimitService *ratelimit.RateLimitService,
+) *BackupService {
+	return &BackupService{
+		db:               db,
+		mcpService:       mcpService,
+		toolGroupService: toolGroupService,
+		rateLimitService: rateLimitService,
+	}
+}
+
+// Create takes a backup of the registry.
+func (s *BackupService) Create() (*Backup, error) {
+	b := &Backup{}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		version, err := migrations.CurrentVersion(tx)
+		if err != nil {
+			return err
+		}
+		var config model.ServerConfig
+		if err := tx.First(&config).Error; err != nil {
+			return fmt.Errorf("failed to get server config: %w", err)
+		}
+		b.BackupHeader = types.BackupHeader{
+			SchemaVersion: version,
+			CreatedAt:     time.Now().UTC(),
+			Mode:          string(config.Mode),
+		}
+		b.Settings = config.Settings
+
+		var tools []model.Tool
+		for _, q := range []struct {
+			name string
+			dest any
+		}{
+			{"MCP servers", &b.McpServers},
+			{"tools", &tools},
+			{"tool groups", &b.ToolGroups},
+			{"MCP clients", &b.McpClients},
+			{"users", &b.Users},
+			{"rate limits", &b.RateLimits},
+			{"tool cache policies", &b.ToolCachePolicies},
+		} {
+			if err := tx.Order("id").Find(q.dest).Error; err != nil {
+				return fmt.Errorf("failed to read %s: %w", q.name, err)
+			}
+		}
+		var webhooks []model.Webhook
+		if err := tx.Order("id").Find(&webhooks).Error; err != nil {
+			return fmt.Errorf("failed to read webhooks: %w", err)
+		}
+
+		serverNames := make(map[uint]string, len(b.McpServers))
+		for _, srv := range b.McpServers {
+			serverNames[srv.ID] = srv.Name
+		}
+		b.Tools = make([]ToolRecord, 0, len(tools))
+		for _, t := range tools {
+			b.Tools = append(b.Tools, ToolRecord{Tool: t, ServerName: serverNames[t.ServerID]})
+		}
+		b.Webhooks = make([]WebhookRecord, 0, len(webhooks))
+		for _, w := range webhooks {
+			b.Webhooks = append(b.Webhooks, WebhookRecord{Webhook: w, Secret: w.Secret})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup: %w", err)
+	}
+	return b, nil
+}
+
+// Restore replaces the contents of the registry with the backup.
+// Nothing is changed if the backup is invalid or can't be restored completely.
+// MCP servers are not contacted, so their tools are restored exactly as they were when the backup was taken.
+func (s *BackupService) Restore(b *Backup) (*types.RestoreResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.validate(b); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// delete tools before the servers they belong to
+		for _, m := range []any{
+			&model.Tool{}, &model.McpServer{}, &model.ToolGroup{}, &model.McpClient{}, &model.User{},
+			&model.RateLimit{}, &model.ToolCachePolicy{}, &model.Webhook{},
+		} {
+			// soft-deleted rows are deleted too, because they still occupy their unique names
+			err := tx.Session(&gorm.Session{AllowGlobalUpdate: true}).Unscoped().Delete(m).Error
+			if err != nil {
+				return fmt.Errorf("failed to clear registry: %w", err)
+			}
+		}
+
+		// IDs are assigned by the database, so that they don't collide with its sequences
+		serverIDs := make(map[string]uint, len(b.McpServers))
+		for i := range b.McpServers {
+			srv := b.McpServers[i]
+			srv.Model = resetModel(srv.Model)
+			if err := tx.Create(&srv).Error; err != nil {
+				return fmt.Errorf("failed to restore MCP server %s: %w", srv.Name, err)
+			}
+			serverIDs[srv.Name] = srv.ID
+		}
+		for i := range b.Tools {
+			t := b.Tools[i].Tool
+			t.Model = resetModel(t.Model)
+			t.ServerID = serverIDs[b.Tools[i].ServerName]
+			if err := tx.Create(&t).Error; err != nil {
+				return fmt.Errorf("failed to restore tool %s: %w", t.Name, err)
+			}
+			// the enabled column defaults to true, so a disabled tool is only stored as disabled by an update
+			if !b.Tools[i].Enabled {
+				if err := tx.Model(&t).Update("enabled", false).Error; err != nil {
+					return fmt.Errorf("failed to restore tool %s: %w", t.Name, err)
+				}
+			}
+		}
+		for i := range b.ToolGroups {
+			b.ToolGroups[i].Model = resetModel(b.ToolGroups[i].Model)
+		}
+		for i := range b.McpClients {
+			b.McpClients[i].Model = resetModel(b.McpClients[i].Model)
+		}
+		for i := range b.Users {
+			b.Users[i].Model = resetModel(b.Users[i].Model)
+		}
+		for i := range b.RateLimits {
+			b.RateLimits[i].Model = resetModel(b.RateLimits[i].Model)
+		}
+		for i := range b.ToolCachePolicies {
+			b.ToolCachePolicies[i].Model = resetModel(b.ToolCachePolicies[i].Model)
+		}
+		webhooks := make([]model.Webhook, len(b.Webhooks))
+		for i := range b.Webhooks {
+			webhooks[i] = b.Webhooks[i].Webhook
+			webhooks[i].Model = resetModel(webhooks[i].Model)
+			webhooks[i].Secret = b.Webhooks[i].Secret
+		}
+		for _, rows := range []struct {
+			name string
+			rows any
+			n    int
+		}{
+			{"tool groups", &b.ToolGroups, len(b.ToolGroups)},
+			{"MCP clients", &b.McpClients, len(b.McpClients)},
+			{"users", &b.Users, len(b.Users)},
+			{"rate limits", &b.RateLimits, len(b.RateLimits)},
+			{"tool cache policies", &b.ToolCachePolicies, len(b.ToolCachePolicies)},
+			{"webhooks", &webhooks, len(webhooks)},
+		} {
+			if rows.n == 0 {
+				continue
+			}
+			if err := tx.Create(rows.rows).Error; err != nil {
+				return fmt.Errorf("failed to restore %s: %w", rows.name, err)
+			}
+		}
+
+		var config model.ServerConfig
+		if err := tx.First(&config).Error; err != nil {
+			return fmt.Errorf("failed to get server config: %w", err)
+		}
+		if err := tx.Model(&config).Update("settings", b.Settings).Error; err != nil {
+			return fmt.Errorf("failed to restore server settings: %w", err)
+		}
+		// other mcpjungle servers sharing the database reload the restored registry as well
+		return changes.Record(tx, model.RegistryScopes...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// bring the MCP proxy and the tool groups in line with the restored registry
+	if err := s.mcpService.Reload(); err != nil {
+		return nil, fmt.Errorf("backup was restored but the MCP proxy could not be reloaded: %w", err)
+	}
+	if err := s.toolGroupService.Reload(); err != nil {
+		return nil, fmt.Errorf("backup was restored but the tool groups could not be reloaded: %w", err)
+	}
+	if err := s.rateLimitService.Reload(); err != nil {
+		return nil, fmt.Errorf("backup was restored but the rate limits could not be reloaded: %w", err)
+	}
+
+	return &types.RestoreResult{
+		McpServers:        len(b.McpServers),
+		Tools:             len(b.Tools),
+		ToolGroups:        len(b.ToolGroups),
+		McpClients:        len(b.McpClients),
+		Users:             len(b.Users),
+		RateLimits:        len(b.RateLimits),
+		ToolCachePolicies: len(b.ToolCachePolicies),
+		Webhooks:          len(b.Webhooks),
+	}, nil
+}
+
+// validate checks that the backup is well-formed and compatible with this server.
+func (s *BackupService) validate(b *Backup) error {
+	if b.SchemaVersion == 0 {
+		return fmt.Errorf("%w: schema version is missing, this doesn't look like a backup of mcpjungle", ErrInvalidBackup)
+	}
+	version, err := migrations.CurrentVersion(s.db)
+	if err != nil {
+		return err
+	}
+	if b.SchemaVersion > version {
+		return fmt.Errorf(
+			"%w: backup has schema version %d but the database only has version %d,"+
+				" upgrade mcpjungle and run `mcpjungle migrate up` before restoring it",
+			ErrInvalidBackup, b.SchemaVersion, version,
+		)
+	}
+	if b.SchemaVersion < version {
+		return fmt.Errorf(
+			"%w: backup has schema version %d but the database has version %d,"+
+				" restore it with the version of mcpjungle that created it and then upgrade",
+			ErrInvalidBackup, b.SchemaVersion, version,
+		)
+	}
+
+	var config model.ServerConfig
+	if err := s.db.First(&config).Error; err != nil {
+		return fmt.Errorf("failed to get server config: %w", err)
+	}
+	if b.Mode != string(config.Mode) {
+		return fmt.Errorf(
+			"%w: backup was taken from a server in %s mode but this server is in %s mode",
+			ErrInvalidBackup, b.Mode, config.Mode,
+		)
+	}
+	if config.Mode == model.ModeProd {
+		// without an admin, nobody could manage the server after the restore
+		hasAdmin := false
+		for _, u := range b.Users {
+			hasAdmin = hasAdmin || u.Role == types.UserRoleAdmin
+		}
+		if !hasAdmin {
+			return fmt.Errorf("%w: backup of a server in production mode must contain an admin user", ErrInvalidBackup)
+		}
+	}
+
+	servers := make(map[string]bool, len(b.McpServers))
+	for i := range b.McpServers {
+		if _, err := b.McpServers[i].ToInput(); err != nil {
+			return fmt.Errorf("%w: MCP server %s: %w", ErrInvalidBackup, b.McpServers[i].Name, err)
+		}
+		servers[b.McpServers[i].Name] = true
+	}
+	for _, t := range b.Tools {
+		if !servers[t.ServerName] {
+			return fmt.Errorf("%w: tool %s belongs to unknown MCP server '%s'", ErrInvalidBackup, t.Name, t.ServerName)
+		}
+	}
+	for i := range b.RateLimits {
+		if err := b.RateLimits[i].Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+	}
+	for i := range b.ToolCachePolicies {
+		if err := b.ToolCachePolicies[i].Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+	}
+	for i := range b.Webhooks {
+		if err := b.Webhooks[i].Validate(); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidBackup, err)
+		}
+	}
+	if b.Settings != nil {
+		settings := model.ServerConfig{Settings: b.Settings}
+		if _, err := settings.GetSettings(); err != nil {
+			return fmt.Errorf("%w: settings: %w", ErrInvalidBackup, err)
+		}
+	}
+	return nil
+}
+
+// resetModel keeps the timestamps of a restored object but clears its ID, so that the database assigns a new one.
+func resetModel(m gorm.Model) gorm.Model {
+	return gorm.Model{CreatedAt: m.CreatedAt, UpdatedAt: m.UpdatedAt}
+}
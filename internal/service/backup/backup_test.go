@@ -0,0 +1,187 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/config"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/ratelimit"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+func newTestService(t *testing.T, mode model.ServerMode) *BackupService {
+	t.Helper()
+	db := dbtest.New(t)
+	if _, err := config.NewServerConfigService(db).Init(mode); err != nil {
+		t.Fatalf("failed to initialize server: %v", err)
+	}
+	mcpService, err := mcp.NewMCPService(db, server.NewMCPServer("test", "0.1.0"))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	if err != nil {
+		t.Fatalf("failed to create tool group service: %v", err)
+	}
+	rateLimitService, err := ratelimit.NewRateLimitService(db)
+	if err != nil {
+		t.Fatalf("failed to create rate limit service: %v", err)
+	}
+	return NewBackupService(db, mcpService, toolGroupService, rateLimitService)
+}
+
+// createServer registers an MCP server and its tools directly in the database.
+func createServer(t *testing.T, db *gorm.DB, name string, tools map[string]bool) {
+	t.Helper()
+	s, err := model.NewStreamableHTTPServer(name, "", "http://localhost:9999/mcp", "secret-token")
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	for tool, enabled := range tools {
+		tm := &model.Tool{Name: tool, ServerID: s.ID, InputSchema: datatypes.JSON(`{"type":"object"}`)}
+		if err := db.Create(tm).Error; err != nil {
+			t.Fatalf("failed to create tool: %v", err)
+		}
+		if err := db.Model(tm).Update("enabled", enabled).Error; err != nil {
+			t.Fatalf("failed to update tool: %v", err)
+		}
+	}
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	s := newTestService(t, model.ModeDev)
+	createServer(t, s.db, "github", map[string]bool{"create_issue": true, "delete_repo": false})
+	group := &model.ToolGroup{Name: "triage", IncludedTools: datatypes.JSON(`["github__create_issue"]`)}
+	if err := s.db.Create(group).Error; err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	webhook := &model.Webhook{Name: "alerts", URL: "https://example.com/hook", Secret: "s3cret"}
+	if err := s.db.Create(webhook).Error; err != nil {
+		t.Fatalf("failed to create webhook: %v", err)
+	}
+	if err := s.mcpService.Reload(); err != nil {
+		t.Fatalf("failed to reload MCP service: %v", err)
+	}
+
+	b, err := s.Create()
+	if err != nil {
+		t.Fatalf("failed to create backup: %v", err)
+	}
+	if b.SchemaVersion == 0 || b.Mode != string(model.ModeDev) {
+		t.Fatalf("unexpected backup header: %+v", b.BackupHeader)
+	}
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("failed to marshal backup: %v", err)
+	}
+
+	// change the registry after the backup was taken
+	if err := s.mcpService.DeregisterMcpServer("github"); err != nil {
+		t.Fatalf("failed to deregister server: %v", err)
+	}
+	createServer(t, s.db, "slack", map[string]bool{"post": true})
+	if err := s.mcpService.Reload(); err != nil {
+		t.Fatalf("failed to reload MCP service: %v", err)
+	}
+
+	var restored Backup
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("failed to unmarshal backup: %v", err)
+	}
+	result, err := s.Restore(&restored)
+	if err != nil {
+		t.Fatalf("failed to restore backup: %v", err)
+	}
+	if result.McpServers != 1 || result.Tools != 2 || result.ToolGroups != 1 || result.Webhooks != 1 {
+		t.Errorf("unexpected restore result: %+v", result)
+	}
+
+	servers, _ := s.mcpService.ListMcpServers()
+	if len(servers) != 1 || servers[0].Name != "github" {
+		t.Fatalf("expected only the github server to be restored, got %+v", servers)
+	}
+	if cfg, err := servers[0].GetStreamableHTTPConfig(); err != nil || cfg.BearerToken != "secret-token" {
+		t.Errorf("expected the server's bearer token to be restored, got %+v, %v", cfg, err)
+	}
+	tool, err := s.mcpService.GetTool("github__delete_repo")
+	if err != nil || tool.Enabled {
+		t.Errorf("expected github__delete_repo to be restored as disabled, got %+v, %v", tool, err)
+	}
+
+	// the MCP proxy & tool groups only serve the restored, enabled tools
+	if _, ok := s.mcpService.GetToolInstance("github__create_issue"); !ok {
+		t.Error("expected github__create_issue to be served by the MCP proxy")
+	}
+	for _, name := range []string{"github__delete_repo", "slack__post"} {
+		if _, ok := s.mcpService.GetToolInstance(name); ok {
+			t.Errorf("expected %s not to be served by the MCP proxy", name)
+		}
+	}
+	groupServer, ok := s.toolGroupService.GetToolGroupMCPServer("triage")
+	if !ok {
+		t.Fatal("expected the triage group to be restored")
+	}
+	resp, _ := json.Marshal(groupServer.HandleMessage(
+		context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+	))
+	if !strings.Contains(string(resp), "github__create_issue") {
+		t.Errorf("expected the triage group to serve github__create_issue, got %s", resp)
+	}
+
+	var w model.Webhook
+	if err := s.db.Where("name = ?", "alerts").First(&w).Error; err != nil || w.Secret != "s3cret" {
+		t.Errorf("expected the webhook secret to be restored, got %q, %v", w.Secret, err)
+	}
+}
+
+func TestRestoreValidation(t *testing.T) {
+	s := newTestService(t, model.ModeProd)
+	createServer(t, s.db, "github", map[string]bool{"create_issue": true})
+
+	valid, err := s.Create()
+	if err != nil {
+		t.Fatalf("failed to create backup: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(b *Backup)
+	}{
+		{"missing schema version", func(b *Backup) { b.SchemaVersion = 0 }},
+		{"newer schema version", func(b *Backup) { b.SchemaVersion++ }},
+		{"different mode", func(b *Backup) { b.Mode = string(model.ModeDev) }},
+		// the backup has no users because the server was never given an admin
+		{"no admin in production mode", func(b *Backup) {}},
+		{"tool of unknown server", func(b *Backup) {
+			b.Users = []model.User{{Username: "admin", Role: "admin", AccessToken: "token"}}
+			b.Tools[0].ServerName = "gitlab"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := *valid
+			b.Tools = append([]ToolRecord(nil), valid.Tools...)
+			tt.modify(&b)
+			if _, err := s.Restore(&b); !errors.Is(err, ErrInvalidBackup) {
+				t.Errorf("expected ErrInvalidBackup, got %v", err)
+			}
+		})
+	}
+
+	// nothing is changed by a backup that fails validation
+	if servers, _ := s.mcpService.ListMcpServers(); len(servers) != 1 {
+		t.Errorf("expected the registry to be unchanged, got %d servers", len(servers))
+	}
+}
@@ -2,16 +2,40 @@
 package config
 
 import (
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"log/slog"
+	"sync"
 
+	"github.com/mcpjungle/mcpjungle/internal"
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidSettings is returned when settings can't be stored because they contain invalid values.
+	ErrInvalidSettings = errors.New("invalid settings")
+
+	// ErrModeSwitchNotAllowed is returned when the server can't be switched to the requested mode.
+	ErrModeSwitchNotAllowed = errors.New("mode switch not allowed")
+
+	// ErrInvalidConfirmationToken is returned when switching to production mode without the right confirmation token.
+	ErrInvalidConfirmationToken = errors.New("invalid confirmation token")
+)
+
 // ServerConfigService provides methods to manage server configuration in the database.
 type ServerConfigService struct {
 	db *gorm.DB
+
+	// switchToProdToken must be given to switch the server to production mode, see SwitchToProdToken.
+	switchToProdToken string
+	mu                sync.Mutex
 }
 
 func NewServerConfigService(db *gorm.DB) *ServerConfigService {
@@ -51,3 +75,143 @@ func (s *ServerConfigService) Init(mode model.ServerMode) (bool, error) {
 	}
 	return true, s.db.Create(&config).Error
 }
+
+// GetSettings returns the settings stored in the server configuration.
+func (s *ServerConfigService) GetSettings() (*model.GlobalSettings, error) {
+	config, err := s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	settings, err := config.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server settings: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings clears the stored settings named in unset, overrides them with the fields that are set in update
+// and returns the resulting settings.
+// The server must be initialized.
+func (s *ServerConfigService) UpdateSettings(update *model.GlobalSettings, unset []string) (*model.GlobalSettings, error) {
+	if err := update.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+	if err := logging.ValidateLevel(update.LogLevel); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+
+	config, err := s.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !config.Initialized {
+		return nil, errors.New("server is not initialized")
+	}
+	settings, err := config.GetSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server settings: %w", err)
+	}
+	if err := settings.Unset(unset); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSettings, err)
+	}
+	settings.Merge(update)
+	if err := config.SetSettings(settings); err != nil {
+		return nil, err
+	}
+	if err := s.db.Model(&config).Update("settings", config.Settings).Error; err != nil {
+		return nil, fmt.Errorf("failed to save server settings: %w", err)
+	}
+	s.recordChange()
+	return settings, nil
+}
+
+// recordChange notifies the other mcpjungle servers that share the database that the settings have changed.
+// Failing to do so doesn't fail the change, which has already been made.
+func (s *ServerConfigService) recordChange() {
+	if err := changes.Record(s.db, model.RegistryScopeSettings); err != nil {
+		slog.Error("other servers may not apply the changed settings until they restart", logging.KeyError, err)
+	}
+}
+
+// SwitchToProdToken returns the confirmation token needed to switch this server to production mode.
+// In development mode, anyone who can reach the API is allowed to use it. The token is meant to be printed
+// to the server's logs, so that only its operator can take over the server by becoming its admin.
+// It is created on first use and stays the same until the process exits.
+func (s *ServerConfigService) SwitchToProdToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.switchToProdToken == "" {
+		token, err := internal.GenerateAccessToken()
+		if err != nil {
+			return "", err
+		}
+		s.switchToProdToken = token
+	}
+	return s.switchToProdToken, nil
+}
+
+// SwitchToProd switches a server running in development mode to production mode and creates its admin user.
+// confirmationToken must be the token returned by SwitchToProdToken.
+// All MCP servers, tools and tool groups are kept.
+// In production mode, MCP clients need an access token to use the MCP proxy. If clientName is not empty,
+// an MCP client with access to all registered MCP servers is created, so that existing integrations
+// only need its access token to keep working.
+// Either everything succeeds or nothing is changed.
+func (s *ServerConfigService) SwitchToProd(confirmationToken, clientName string) (*model.User, *model.McpClient, error) {
+	s.mu.Lock()
+	expected := s.switchToProdToken
+	s.mu.Unlock()
+	if expected == "" || subtle.ConstantTimeCompare([]byte(confirmationToken), []byte(expected)) != 1 {
+		return nil, nil, ErrInvalidConfirmationToken
+	}
+
+	var admin *model.User
+	var client *model.McpClient
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var config model.ServerConfig
+		if err := tx.First(&config).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("%w: server is not initialized", ErrModeSwitchNotAllowed)
+			}
+			return fmt.Errorf("failed to fetch server configuration from db: %w", err)
+		}
+		if !config.Initialized || config.Mode != model.ModeDev {
+			return fmt.Errorf("%w: only a server in %s mode can be switched to %s mode",
+				ErrModeSwitchNotAllowed, model.ModeDev, model.ModeProd)
+		}
+		if err := tx.Model(&config).Update("mode", model.ModeProd).Error; err != nil {
+			return fmt.Errorf("failed to update server mode: %w", err)
+		}
+
+		var err error
+		admin, err = user.NewUserService(tx).CreateAdminUser()
+		if err != nil {
+			return err
+		}
+
+		if clientName == "" {
+			return nil
+		}
+		servers := make([]string, 0)
+		if err := tx.Model(&model.McpServer{}).Pluck("name", &servers).Error; err != nil {
+			return fmt.Errorf("failed to list MCP servers: %w", err)
+		}
+		allowList, err := json.Marshal(servers)
+		if err != nil {
+			return err
+		}
+		client, err = mcpclient.NewMCPClientService(tx).CreateClient(model.McpClient{
+			Name:        clientName,
+			Description: "Created when switching to production mode",
+			AllowList:   allowList,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create MCP client %s: %w", clientName, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return admin, client, nil
+}
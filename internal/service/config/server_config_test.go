@@ -0,0 +1,181 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func newTestService(t *testing.T) *ServerConfigService {
+	t.Helper()
+	db := dbtest.New(t)
+	return NewServerConfigService(db)
+}
+
+func TestUpdateSettings(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.Init(model.ModeDev); err != nil {
+		t.Fatalf("failed to initialize server: %v", err)
+	}
+
+	retries := 2
+	if _, err := s.UpdateSettings(&model.GlobalSettings{
+		ServerSettings: model.ServerSettings{CallTimeout: "30s", MaxRetries: &retries},
+	}, nil); err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+
+	// later updates only change the settings they set
+	settings, err := s.UpdateSettings(&model.GlobalSettings{LogLevel: "debug"}, nil)
+	if err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+	if settings.CallTimeout != "30s" || settings.MaxRetries == nil || *settings.MaxRetries != 2 ||
+		settings.LogLevel != "debug" {
+		t.Errorf("unexpected settings after update: %+v", settings)
+	}
+
+	stored, err := s.GetSettings()
+	if err != nil {
+		t.Fatalf("failed to get settings: %v", err)
+	}
+	if !reflect.DeepEqual(stored, settings) {
+		t.Errorf("expected stored settings %+v, got %+v", settings, stored)
+	}
+
+	invalid := []*model.GlobalSettings{
+		{ServerSettings: model.ServerSettings{CallTimeout: "-1s"}},
+		{ServerSettings: model.ServerSettings{QueueTimeout: "soon"}},
+		{LogLevel: "verbose"},
+	}
+	for _, u := range invalid {
+		if _, err := s.UpdateSettings(u, nil); !errors.Is(err, ErrInvalidSettings) {
+			t.Errorf("expected ErrInvalidSettings for %+v, got %v", u, err)
+		}
+	}
+	if _, err := s.UpdateSettings(&model.GlobalSettings{}, []string{"verbosity"}); !errors.Is(err, ErrInvalidSettings) {
+		t.Errorf("expected ErrInvalidSettings when unsetting an unknown setting, got %v", err)
+	}
+}
+
+func TestUnsetSettings(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.Init(model.ModeDev); err != nil {
+		t.Fatalf("failed to initialize server: %v", err)
+	}
+	retries := 2
+	if _, err := s.UpdateSettings(&model.GlobalSettings{
+		ServerSettings: model.ServerSettings{CallTimeout: "30s", MaxRetries: &retries},
+		LogLevel:       "debug",
+	}, nil); err != nil {
+		t.Fatalf("failed to update settings: %v", err)
+	}
+	before, err := changes.Versions(s.db)
+	if err != nil {
+		t.Fatalf("failed to read registry versions: %v", err)
+	}
+
+	settings, err := s.UpdateSettings(&model.GlobalSettings{}, []string{"call_timeout", "log_level"})
+	if err != nil {
+		t.Fatalf("failed to unset settings: %v", err)
+	}
+	want := &model.GlobalSettings{ServerSettings: model.ServerSettings{MaxRetries: &retries}}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("expected settings %+v after unsetting, got %+v", want, settings)
+	}
+
+	// the other servers sharing the database are notified of the change
+	after, err := changes.Versions(s.db)
+	if err != nil {
+		t.Fatalf("failed to read registry versions: %v", err)
+	}
+	if after[model.RegistryScopeSettings] != before[model.RegistryScopeSettings]+1 {
+		t.Errorf("expected the settings version to be incremented, got %d -> %d",
+			before[model.RegistryScopeSettings], after[model.RegistryScopeSettings])
+	}
+}
+
+func TestSwitchToProd(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.Init(model.ModeDev); err != nil {
+		t.Fatalf("failed to initialize server: %v", err)
+	}
+	servers := []model.McpServer{
+		{Name: "github", Transport: "streamable_http", Config: []byte(`{"url":"https://example.com/mcp"}`)},
+		{Name: "time", Transport: "stdio", Config: []byte(`{"command":"uvx"}`)},
+	}
+	if err := s.db.Create(&servers).Error; err != nil {
+		t.Fatalf("failed to create MCP servers: %v", err)
+	}
+
+	token, err := s.SwitchToProdToken()
+	if err != nil {
+		t.Fatalf("failed to create confirmation token: %v", err)
+	}
+	for _, wrong := range []string{"", token + "x"} {
+		if _, _, err := s.SwitchToProd(wrong, "cursor"); !errors.Is(err, ErrInvalidConfirmationToken) {
+			t.Errorf("expected ErrInvalidConfirmationToken for token %q, got %v", wrong, err)
+		}
+	}
+	if config, _ := s.GetConfig(); config.Mode != model.ModeDev {
+		t.Fatalf("expected the server to stay in development mode without the confirmation token, got %s", config.Mode)
+	}
+
+	admin, client, err := s.SwitchToProd(token, "cursor")
+	if err != nil {
+		t.Fatalf("failed to switch to production mode: %v", err)
+	}
+	if admin.Role != "admin" || admin.AccessToken == "" {
+		t.Errorf("expected an admin user with an access token, got %+v", admin)
+	}
+	if !client.CheckHasServerAccess("github") || !client.CheckHasServerAccess("time") {
+		t.Errorf("expected the MCP client to access all servers, got allow list %s", client.AllowList)
+	}
+
+	config, err := s.GetConfig()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	if config.Mode != model.ModeProd || !config.Initialized {
+		t.Errorf("expected an initialized server in production mode, got %+v", config)
+	}
+
+	if _, _, err := s.SwitchToProd(token, ""); !errors.Is(err, ErrModeSwitchNotAllowed) {
+		t.Errorf("expected ErrModeSwitchNotAllowed when switching twice, got %v", err)
+	}
+}
+
+func TestSwitchToProdIsAtomic(t *testing.T) {
+	s := newTestService(t)
+	if _, err := s.Init(model.ModeDev); err != nil {
+		t.Fatalf("failed to initialize server: %v", err)
+	}
+	if err := s.db.Create(&model.McpClient{Name: "cursor", AccessToken: "t", AllowList: []byte("[]")}).Error; err != nil {
+		t.Fatalf("failed to create MCP client: %v", err)
+	}
+
+	token, err := s.SwitchToProdToken()
+	if err != nil {
+		t.Fatalf("failed to create confirmation token: %v", err)
+	}
+	// creating the MCP client fails because one with the same name exists
+	if _, _, err := s.SwitchToProd(token, "cursor"); err == nil {
+		t.Fatal("expected switching to fail")
+	}
+	config, err := s.GetConfig()
+	if err != nil {
+		t.Fatalf("failed to get config: %v", err)
+	}
+	if config.Mode != model.ModeDev {
+		t.Errorf("expected the server to stay in development mode, got %s", config.Mode)
+	}
+	var admins int64
+	s.db.Model(&model.User{}).Count(&admins)
+	if admins != 0 {
+		t.Errorf("expected no admin user to be created, got %d users", admins)
+	}
+}
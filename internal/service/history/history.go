@@ -0,0 +1,194 @@
+// Package history records recent tool calls so that they can be inspected and replayed when debugging.
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMaxRecords is the default number of most recent tool calls that are retained
+	DefaultMaxRecords = 1000
+	// DefaultMaxRecordSize is the default maximum size in bytes of the arguments & result recorded for a call
+	DefaultMaxRecordSize = 64 * 1024
+
+	// pendingBufferSize is the number of calls that can wait to be written to the DB.
+	// Calls are dropped from the history beyond this, so that recording never slows down tool calls.
+	pendingBufferSize = 256
+)
+
+// ErrToolCallNotFound is returned when a tool call does not exist in the history.
+var ErrToolCallNotFound = errors.New("tool call not found in history")
+
+// ToolCallFilter narrows down the tool calls returned from the history.
+// Empty fields match all calls.
+type ToolCallFilter struct {
+	Tool   string
+	Client string
+	// Limit is the maximum number of calls returned
+	Limit int
+}
+
+// HistoryService records the most recent tool calls in the database.
+type HistoryService struct {
+	db *gorm.DB
+
+	// maxRecords is the number of most recent calls retained, 0 disables the history
+	maxRecords int
+	// maxRecordSize is the maximum combined size in bytes of the arguments & result of a call.
+	// Larger results (and arguments) are dropped from the record.
+	maxRecordSize int
+
+	pending chan *mcp.ToolCall
+}
+
+func NewHistoryService(db *gorm.DB, maxRecords, maxRecordSize int) *HistoryService {
+	return &HistoryService{
+		db:            db,
+		maxRecords:    maxRecords,
+		maxRecordSize: maxRecordSize,
+		pending:       make(chan *mcp.ToolCall, pendingBufferSize),
+	}
+}
+
+// Enabled returns true if tool calls are being recorded.
+func (h *HistoryService) Enabled() bool {
+	return h.maxRecords > 0
+}
+
+// Start writes the recorded tool calls to the database in the background until ctx is cancelled.
+func (h *HistoryService) Start(ctx context.Context) {
+	if !h.Enabled() {
+		return
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case call := <-h.pending:
+				if err := h.save(call); err != nil {
+					slog.Warn("failed to record tool call in history", logging.KeyTool, call.Tool, logging.KeyError, err)
+				}
+			}
+		}
+	}()
+}
+
+// RecordToolCall queues a tool call to be recorded in the history.
+// It never blocks, if too many calls are waiting to be recorded, the call is dropped.
+// Its signature matches mcp.ToolCallCallback.
+func (h *HistoryService) RecordToolCall(call *mcp.ToolCall) {
+	if !h.Enabled() {
+		return
+	}
+	select {
+	case h.pending <- call:
+	default:
+		slog.Warn("too many tool calls waiting to be recorded, dropping call from history", logging.KeyTool, call.Tool)
+	}
+}
+
+// save writes a tool call to the database and removes the calls that no longer fit in the history.
+func (h *HistoryService) save(call *mcp.ToolCall) error {
+	rec, err := h.newRecord(call)
+	if err != nil {
+		return err
+	}
+	if err := h.db.Create(rec).Error; err != nil {
+		return fmt.Errorf("failed to save tool call: %w", err)
+	}
+	// IDs increase monotonically, so everything older than the last maxRecords calls can be deleted by ID
+	if rec.ID > uint(h.maxRecords) {
+		err := h.db.Where("id <= ?", rec.ID-uint(h.maxRecords)).Delete(&model.ToolCallRecord{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to prune tool call history: %w", err)
+		}
+	}
+	return nil
+}
+
+// newRecord converts a tool call into its DB record, dropping the result and arguments if they exceed the size limit.
+func (h *HistoryService) newRecord(call *mcp.ToolCall) (*model.ToolCallRecord, error) {
+	rec := &model.ToolCallRecord{
+		CreatedAt:  call.Start,
+		Server:     call.Server,
+		Tool:       call.Tool,
+		Client:     call.Client,
+		Outcome:    call.Outcome,
+		DurationMs: call.Duration.Milliseconds(),
+	}
+	if call.Err != nil {
+		rec.Error = call.Err.Error()
+	}
+
+	args, err := json.Marshal(call.Arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize tool arguments: %w", err)
+	}
+	var result []byte
+	if call.Result != nil {
+		result, err = json.Marshal(mcp.ToolInvokeResultOf(call.Result))
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize tool result: %w", err)
+		}
+	}
+
+	// a truncated JSON document is useless (and rejected by some databases), so oversized parts are dropped entirely.
+	// The result goes first because the arguments are needed to replay the call.
+	if len(args)+len(result) > h.maxRecordSize {
+		result = nil
+		rec.Truncated = true
+	}
+	if len(args) > h.maxRecordSize {
+		args = nil
+	}
+	if args != nil {
+		rec.Arguments = datatypes.JSON(args)
+	}
+	if result != nil {
+		rec.Result = datatypes.JSON(result)
+	}
+	return rec, nil
+}
+
+// ListToolCalls returns the most recent tool calls matching the filter, newest first.
+func (h *HistoryService) ListToolCalls(filter ToolCallFilter) ([]model.ToolCallRecord, error) {
+	q := h.db.Order("id DESC")
+	if filter.Tool != "" {
+		q = q.Where("tool = ?", filter.Tool)
+	}
+	if filter.Client != "" {
+		q = q.Where("client = ?", filter.Client)
+	}
+	if filter.Limit > 0 {
+		q = q.Limit(filter.Limit)
+	}
+
+	var records []model.ToolCallRecord
+	if err := q.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetToolCall returns a single tool call from the history.
+func (h *HistoryService) GetToolCall(id uint) (*model.ToolCallRecord, error) {
+	var rec model.ToolCallRecord
+	if err := h.db.First(&rec, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrToolCallNotFound
+		}
+		return nil, err
+	}
+	return &rec, nil
+}
@@ -0,0 +1,106 @@
+package history
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	mcpgo "github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+)
+
+func newTestService(t *testing.T, maxRecords, maxRecordSize int) *HistoryService {
+	t.Helper()
+	db := dbtest.New(t)
+	return NewHistoryService(db, maxRecords, maxRecordSize)
+}
+
+func newToolCall(tool, client, text string) *mcp.ToolCall {
+	return &mcp.ToolCall{
+		Server:    "docs",
+		Tool:      tool,
+		Client:    client,
+		Arguments: map[string]any{"q": "go"},
+		Result:    mcpgo.NewToolResultText(text),
+		Outcome:   "success",
+		Start:     time.Now(),
+		Duration:  15 * time.Millisecond,
+	}
+}
+
+func TestHistoryRetainsMostRecentCalls(t *testing.T) {
+	h := newTestService(t, 3, DefaultMaxRecordSize)
+	for i := 0; i < 5; i++ {
+		if err := h.save(newToolCall("docs__lookup", "agent", "ok")); err != nil {
+			t.Fatalf("save() error = %v", err)
+		}
+	}
+
+	records, err := h.ListToolCalls(ToolCallFilter{})
+	if err != nil {
+		t.Fatalf("ListToolCalls() error = %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("ListToolCalls() returned %d records, want 3", len(records))
+	}
+	if records[0].ID != 5 || records[2].ID != 3 {
+		t.Errorf("ListToolCalls() returned IDs %d..%d, want 5..3", records[0].ID, records[2].ID)
+	}
+
+	if _, err := h.GetToolCall(1); !errors.Is(err, ErrToolCallNotFound) {
+		t.Errorf("GetToolCall(1) error = %v, want ErrToolCallNotFound", err)
+	}
+}
+
+func TestHistoryFilters(t *testing.T) {
+	h := newTestService(t, 10, DefaultMaxRecordSize)
+	calls := []*mcp.ToolCall{
+		newToolCall("docs__lookup", "agent", "ok"),
+		newToolCall("docs__search", "agent", "ok"),
+		newToolCall("docs__lookup", "ide", "ok"),
+	}
+	for _, c := range calls {
+		if err := h.save(c); err != nil {
+			t.Fatalf("save() error = %v", err)
+		}
+	}
+
+	records, err := h.ListToolCalls(ToolCallFilter{Tool: "docs__lookup", Client: "agent"})
+	if err != nil {
+		t.Fatalf("ListToolCalls() error = %v", err)
+	}
+	if len(records) != 1 || records[0].ID != 1 {
+		t.Errorf("ListToolCalls() = %+v, want only call 1", records)
+	}
+
+	records, err = h.ListToolCalls(ToolCallFilter{Tool: "docs__lookup", Limit: 1})
+	if err != nil {
+		t.Fatalf("ListToolCalls() error = %v", err)
+	}
+	if len(records) != 1 || records[0].ID != 3 {
+		t.Errorf("ListToolCalls() = %+v, want only call 3", records)
+	}
+}
+
+func TestHistoryDropsOversizedResults(t *testing.T) {
+	h := newTestService(t, 10, 200)
+	if err := h.save(newToolCall("docs__lookup", "agent", strings.Repeat("x", 300))); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	rec, err := h.GetToolCall(1)
+	if err != nil {
+		t.Fatalf("GetToolCall() error = %v", err)
+	}
+	if !rec.Truncated {
+		t.Error("record is not marked as truncated")
+	}
+	if rec.Result != nil {
+		t.Errorf("oversized result was recorded: %s", rec.Result)
+	}
+	if string(rec.Arguments) != `{"q":"go"}` {
+		t.Errorf("arguments = %s, want them to be recorded", rec.Arguments)
+	}
+}
@@ -0,0 +1,304 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// BreakerConfig configures the circuit breakers that protect mcpjungle from failing upstream MCP servers.
+// Every MCP server gets its own circuit breaker.
+type BreakerConfig struct {
+	// FailureRatio is the ratio of failed tool calls (0 to 1) within a window that opens the circuit.
+	// Circuit breakers are disabled if it is zero.
+	FailureRatio float64
+
+	// MinRequests is the minimum number of tool calls within a window before the failure ratio is evaluated.
+	// This prevents a single failure from opening the circuit of a server that is rarely used.
+	MinRequests int
+
+	// Window is the duration over which tool calls are counted.
+	// The counts are reset at the start of every window.
+	Window time.Duration
+
+	// OpenDuration is the time for which calls are rejected once the circuit opens.
+	// After that, a single probe call is let through (half-open state) to check whether the server has recovered.
+	OpenDuration time.Duration
+}
+
+// DefaultBreakerConfig is the circuit breaker configuration used unless configured otherwise.
+var DefaultBreakerConfig = BreakerConfig{
+	FailureRatio: 0.5,
+	MinRequests:  5,
+	Window:       time.Minute,
+	OpenDuration: 30 * time.Second,
+}
+
+// Validate checks that the configuration does not contain invalid values.
+func (c BreakerConfig) Validate() error {
+	if c.FailureRatio < 0 || c.FailureRatio > 1 {
+		return fmt.Errorf("circuit breaker failure ratio must be between 0 and 1, got %v", c.FailureRatio)
+	}
+	if c.FailureRatio == 0 {
+		// circuit breakers are disabled, other values don't matter
+		return nil
+	}
+	if c.MinRequests < 1 {
+		return fmt.Errorf("circuit breaker min requests must be at least 1, got %d", c.MinRequests)
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("circuit breaker window must be positive, got %s", c.Window)
+	}
+	if c.OpenDuration <= 0 {
+		return fmt.Errorf("circuit breaker open duration must be positive, got %s", c.OpenDuration)
+	}
+	return nil
+}
+
+// CircuitOpenError is returned when a tool call is rejected without contacting the upstream MCP server
+// because the server's circuit breaker is open.
+type CircuitOpenError struct {
+	Server string
+	// RetryAfter is the time after which the server will be probed again
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(
+		"MCP server %s is unavailable because too many recent calls to it have failed, try again in %s",
+		e.Server, e.RetryAfter.Round(time.Second),
+	)
+}
+
+// breakerResult is the result of a tool call as seen by a circuit breaker.
+type breakerResult int
+
+const (
+	// breakerSuccess means the upstream server responded, even if the tool itself or the server reported an error
+	breakerSuccess breakerResult = iota
+	// breakerFailure means the call failed due to a transport error or timeout
+	breakerFailure
+	// breakerIgnored means the call was abandoned by the caller, so it says nothing about the server's health
+	breakerIgnored
+)
+
+// circuitBreaker tracks the recent tool calls to a single MCP server.
+type circuitBreaker struct {
+	state types.CircuitBreakerState
+
+	windowStart time.Time
+	requests    int
+	failures    int
+
+	openedAt time.Time
+	// probing is true while the single probe call of the half-open state is in flight
+	probing bool
+
+	// generation changes whenever the breaker changes state.
+	// Calls are tagged with the generation in which they were allowed, so that the results of calls
+	// that were still in flight when the state changed don't affect the new state.
+	generation uint64
+}
+
+// circuitBreakers holds the circuit breakers of all MCP servers, keyed by server name.
+type circuitBreakers struct {
+	conf     BreakerConfig
+	breakers map[string]*circuitBreaker
+	// generations is the last generation handed out to a breaker.
+	// It is shared by all breakers so that a breaker that replaces a removed one never reuses a generation.
+	generations uint64
+	mu          sync.Mutex
+}
+
+func newCircuitBreakers(conf BreakerConfig) *circuitBreakers {
+	return &circuitBreakers{
+		conf:     conf,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// get returns the circuit breaker of the given server, creating it if it doesn't exist.
+// The caller must hold the lock.
+func (b *circuitBreakers) get(server string, now time.Time) *circuitBreaker {
+	cb, ok := b.breakers[server]
+	if !ok {
+		b.generations++
+		cb = &circuitBreaker{state: types.CircuitClosed, windowStart: now, generation: b.generations}
+		b.breakers[server] = cb
+	}
+	return cb
+}
+
+// setState moves the breaker into a new state.
+// The caller must hold the lock.
+func (b *circuitBreakers) setState(server string, cb *circuitBreaker, state types.CircuitBreakerState) {
+	cb.state = state
+	b.generations++
+	cb.generation = b.generations
+	metrics.SetCircuitBreakerState(server, string(state))
+}
+
+// allow reports whether a call to the given server may proceed.
+// If so, it returns the breaker generation that the call's result must be recorded with.
+// If not, it returns the time after which the server will be probed again.
+func (b *circuitBreakers) allow(server string) (generation uint64, retryAfter time.Duration, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conf.FailureRatio == 0 {
+		return 0, 0, true
+	}
+
+	now := time.Now()
+	cb := b.get(server, now)
+	switch cb.state {
+	case types.CircuitOpen:
+		reopensAt := cb.openedAt.Add(b.conf.OpenDuration)
+		if now.Before(reopensAt) {
+			return 0, reopensAt.Sub(now), false
+		}
+		// the cool-down period is over, let this call through to probe the server
+		b.setState(server, cb, types.CircuitHalfOpen)
+		cb.probing = true
+		slog.Info("circuit breaker is half-open, probing MCP server", logging.KeyServer, server)
+		return cb.generation, 0, true
+	case types.CircuitHalfOpen:
+		if cb.probing {
+			// only one probe at a time, reject the rest until its outcome is known
+			return 0, b.conf.OpenDuration, false
+		}
+		cb.probing = true
+		return cb.generation, 0, true
+	default:
+		return cb.generation, 0, true
+	}
+}
+
+// record updates the breaker of the given server with the result of a call that was allowed to proceed.
+// generation is the one returned by allow for the call. Results of calls that were allowed in an earlier
+// generation are ignored, eg, a slow call from before the circuit opened must not close it again.
+func (b *circuitBreakers) record(server string, generation uint64, result breakerResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conf.FailureRatio == 0 {
+		return
+	}
+
+	cb, ok := b.breakers[server]
+	if !ok || cb.generation != generation {
+		// the breaker was reset or changed state while the call was in flight
+		return
+	}
+
+	now := time.Now()
+	switch cb.state {
+	case types.CircuitHalfOpen:
+		cb.probing = false
+		switch result {
+		case breakerSuccess:
+			b.setState(server, cb, types.CircuitClosed)
+			cb.windowStart, cb.requests, cb.failures = now, 0, 0
+			slog.Info("circuit breaker closed, MCP server has recovered", logging.KeyServer, server)
+		case breakerFailure:
+			b.setState(server, cb, types.CircuitOpen)
+			cb.openedAt = now
+			slog.Warn("circuit breaker re-opened, MCP server is still failing", logging.KeyServer, server)
+		}
+	case types.CircuitClosed:
+		if result == breakerIgnored {
+			return
+		}
+		if now.Sub(cb.windowStart) >= b.conf.Window {
+			cb.windowStart, cb.requests, cb.failures = now, 0, 0
+		}
+		cb.requests++
+		if result == breakerFailure {
+			cb.failures++
+		}
+		if cb.requests >= b.conf.MinRequests &&
+			float64(cb.failures)/float64(cb.requests) >= b.conf.FailureRatio {
+			b.setState(server, cb, types.CircuitOpen)
+			cb.openedAt = now
+			slog.Warn(
+				"circuit breaker opened, rejecting calls to MCP server",
+				logging.KeyServer, server,
+				"failures", cb.failures, "requests", cb.requests, "open_for", b.conf.OpenDuration,
+			)
+		}
+	}
+}
+
+// state returns the current state of the breaker of the given server.
+func (b *circuitBreakers) state(server string) types.CircuitBreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conf.FailureRatio == 0 {
+		return ""
+	}
+	cb, ok := b.breakers[server]
+	if !ok {
+		return types.CircuitClosed
+	}
+	return cb.state
+}
+
+// remove forgets the breaker of the given server.
+func (b *circuitBreakers) remove(server string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.breakers, server)
+	metrics.DeleteCircuitBreakerState(server)
+}
+
+// serverNames returns the names of all servers that have a breaker.
+func (b *circuitBreakers) serverNames() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Collect(maps.Keys(b.breakers))
+}
+
+// setConfig replaces the configuration of all breakers.
+// The state of existing breakers is reset so that they are evaluated from scratch with the new configuration.
+func (b *circuitBreakers) setConfig(conf BreakerConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conf = conf
+	for server := range b.breakers {
+		metrics.DeleteCircuitBreakerState(server)
+	}
+	b.breakers = make(map[string]*circuitBreaker)
+}
+
+// breakerResultOf classifies the outcome of a tool call attempt for the circuit breaker.
+// ctx is the context of the whole tool call.
+func breakerResultOf(ctx context.Context, err error) breakerResult {
+	if err == nil {
+		return breakerSuccess
+	}
+	if ctx.Err() != nil {
+		// the caller gave up, this is not the upstream server's fault
+		return breakerIgnored
+	}
+	if isServerError(err) && !errors.Is(err, errCallTimeout) {
+		// the server is up, it only rejected this request, eg- because of invalid arguments
+		return breakerSuccess
+	}
+	return breakerFailure
+}
+
+// SetBreakerConfig changes the configuration of the circuit breakers of all MCP servers.
+func (m *MCPService) SetBreakerConfig(conf BreakerConfig) error {
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+	m.breakers.setConfig(conf)
+	return nil
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// call makes a call through the breaker of server s that ends with the given result.
+func call(t *testing.T, b *circuitBreakers, result breakerResult) {
+	t.Helper()
+	generation, _, ok := b.allow("s")
+	if !ok {
+		t.Fatal("allow() = false, want the call to be allowed")
+	}
+	b.record("s", generation, result)
+}
+
+func TestCircuitBreakers(t *testing.T) {
+	conf := BreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		OpenDuration: 50 * time.Millisecond,
+	}
+
+	t.Run("opens after failure ratio is reached", func(t *testing.T) {
+		b := newCircuitBreakers(conf)
+		for _, r := range []breakerResult{breakerSuccess, breakerFailure, breakerSuccess} {
+			call(t, b, r)
+		}
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s, want %s", got, types.CircuitClosed)
+		}
+
+		call(t, b, breakerFailure)
+		if got := b.state("s"); got != types.CircuitOpen {
+			t.Fatalf("state() = %s, want %s", got, types.CircuitOpen)
+		}
+		_, retryAfter, ok := b.allow("s")
+		if ok {
+			t.Fatal("allow() = true while circuit is open")
+		}
+		if retryAfter <= 0 || retryAfter > conf.OpenDuration {
+			t.Errorf("allow() retryAfter = %s, want between 0 and %s", retryAfter, conf.OpenDuration)
+		}
+	})
+
+	t.Run("ignored results don't count", func(t *testing.T) {
+		b := newCircuitBreakers(conf)
+		for i := 0; i < 10; i++ {
+			call(t, b, breakerIgnored)
+		}
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s, want %s", got, types.CircuitClosed)
+		}
+	})
+
+	t.Run("half-open probe closes or re-opens the circuit", func(t *testing.T) {
+		b := newCircuitBreakers(conf)
+		for i := 0; i < conf.MinRequests; i++ {
+			call(t, b, breakerFailure)
+		}
+		time.Sleep(conf.OpenDuration)
+
+		probe, _, ok := b.allow("s")
+		if !ok {
+			t.Fatal("allow() = false after open duration elapsed")
+		}
+		if got := b.state("s"); got != types.CircuitHalfOpen {
+			t.Fatalf("state() = %s, want %s", got, types.CircuitHalfOpen)
+		}
+		if _, _, ok := b.allow("s"); ok {
+			t.Fatal("allow() = true while a probe is in flight")
+		}
+
+		b.record("s", probe, breakerFailure)
+		if got := b.state("s"); got != types.CircuitOpen {
+			t.Fatalf("state() = %s after failed probe, want %s", got, types.CircuitOpen)
+		}
+
+		time.Sleep(conf.OpenDuration)
+		call(t, b, breakerSuccess)
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s after successful probe, want %s", got, types.CircuitClosed)
+		}
+	})
+
+	t.Run("results of earlier generations are ignored", func(t *testing.T) {
+		b := newCircuitBreakers(conf)
+		// a slow call that started while the circuit was closed
+		slow, _, _ := b.allow("s")
+		for i := 0; i < conf.MinRequests; i++ {
+			call(t, b, breakerFailure)
+		}
+		time.Sleep(conf.OpenDuration)
+		probe, _, ok := b.allow("s")
+		if !ok {
+			t.Fatal("allow() = false after open duration elapsed")
+		}
+
+		// the slow call finishing successfully must neither close the circuit nor end the probe
+		b.record("s", slow, breakerSuccess)
+		if got := b.state("s"); got != types.CircuitHalfOpen {
+			t.Fatalf("state() = %s after a stale result, want %s", got, types.CircuitHalfOpen)
+		}
+		if _, _, ok := b.allow("s"); ok {
+			t.Fatal("allow() = true while a probe is in flight")
+		}
+
+		b.record("s", probe, breakerSuccess)
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s after successful probe, want %s", got, types.CircuitClosed)
+		}
+		// the probe can't be recorded twice
+		b.record("s", probe, breakerFailure)
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s after a stale probe result, want %s", got, types.CircuitClosed)
+		}
+	})
+
+	t.Run("results from before a reset are ignored", func(t *testing.T) {
+		b := newCircuitBreakers(conf)
+		stale, _, _ := b.allow("s")
+		b.remove("s")
+		for i := 0; i < conf.MinRequests-1; i++ {
+			call(t, b, breakerFailure)
+		}
+		b.record("s", stale, breakerFailure)
+		if got := b.state("s"); got != types.CircuitClosed {
+			t.Fatalf("state() = %s, want %s", got, types.CircuitClosed)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		b := newCircuitBreakers(BreakerConfig{})
+		for i := 0; i < 10; i++ {
+			call(t, b, breakerFailure)
+		}
+		if got := b.state("s"); got != "" {
+			t.Errorf("state() = %s, want empty", got)
+		}
+	})
+}
+
+func TestBreakerResultOf(t *testing.T) {
+	if got := breakerResultOf(context.Background(), nil); got != breakerSuccess {
+		t.Errorf("breakerResultOf(nil) = %v, want success", got)
+	}
+	if got := breakerResultOf(context.Background(), errors.New("boom")); got != breakerFailure {
+		t.Errorf("breakerResultOf(err) = %v, want failure", got)
+	}
+	if got := breakerResultOf(context.Background(), &serverError{Code: -32602, Message: "invalid params"}); got != breakerSuccess {
+		t.Errorf("breakerResultOf(server error) = %v, want success", got)
+	}
+	timeout := fmt.Errorf("%w after 1s: %w", errCallTimeout, &serverError{Message: "late"})
+	if got := breakerResultOf(context.Background(), timeout); got != breakerFailure {
+		t.Errorf("breakerResultOf(timeout) = %v, want failure", got)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if got := breakerResultOf(ctx, context.Canceled); got != breakerIgnored {
+		t.Errorf("breakerResultOf(canceled) = %v, want ignored", got)
+	}
+}
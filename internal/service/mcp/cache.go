@@ -0,0 +1,324 @@
+package mcp
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// ErrToolCachePolicyNotFound is returned when caching is not enabled for a tool.
+var ErrToolCachePolicyNotFound = errors.New("caching is not enabled for this tool")
+
+// cacheEntry is a single cached tool result.
+type cacheEntry struct {
+	key     string
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+// toolCache holds the cached results of a single tool, evicting the least recently used one when full.
+type toolCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	entries map[string]*list.Element
+	// lru orders the entries from the most to the least recently used
+	lru *list.List
+
+	hits   uint64
+	misses uint64
+}
+
+func newToolCache(ttl time.Duration, maxEntries int) *toolCache {
+	return &toolCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// evict removes the least recently used entries until the cache is within its size limit.
+func (c *toolCache) evict() {
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// resultCache caches the results of the tools that have caching enabled, keyed by their canonical names.
+// Results are kept in memory, so they are lost when mcpjungle restarts.
+type resultCache struct {
+	tools map[string]*toolCache
+	mu    sync.Mutex
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{tools: make(map[string]*toolCache)}
+}
+
+// setPolicy enables caching for the given tool or changes the settings of its cache.
+// Results that are already cached are kept, but they expire according to their original TTL.
+func (r *resultCache) setPolicy(tool string, ttl time.Duration, maxEntries int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.tools[tool]
+	if !ok {
+		r.tools[tool] = newToolCache(ttl, maxEntries)
+		return
+	}
+	c.ttl, c.maxEntries = ttl, maxEntries
+	c.evict()
+}
+
+// removePolicy disables caching for the given tool and drops its cached results.
+func (r *resultCache) removePolicy(tool string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tools, tool)
+}
+
+// policyTools returns the names of all tools that have caching enabled.
+func (r *resultCache) policyTools() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Collect(maps.Keys(r.tools))
+}
+
+// key returns the cache key for a call to the given tool with the given arguments.
+// It returns false if caching is not enabled for the tool.
+func (r *resultCache) key(tool string, args any) (string, bool) {
+	r.mu.Lock()
+	_, ok := r.tools[tool]
+	r.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+	key, err := canonicalArgsKey(args)
+	if err != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// get returns the cached result of the given tool for the given key, if it hasn't expired yet.
+func (r *resultCache) get(tool, key string) (*mcp.CallToolResult, bool) {
+	server, toolName, _ := splitServerToolName(tool)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.tools[tool]
+	if !ok {
+		return nil, false
+	}
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*cacheEntry)
+		if time.Now().Before(e.expires) {
+			c.lru.MoveToFront(el)
+			c.hits++
+			metrics.ObserveToolCacheLookup(server, toolName, true)
+			return e.result, true
+		}
+		c.lru.Remove(el)
+		delete(c.entries, key)
+	}
+	c.misses++
+	metrics.ObserveToolCacheLookup(server, toolName, false)
+	return nil, false
+}
+
+// put caches the result of the given tool for the given key.
+func (r *resultCache) put(tool, key string, result *mcp.CallToolResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.tools[tool]
+	if !ok {
+		// caching was disabled while the call was in progress
+		return
+	}
+	e := &cacheEntry{key: key, result: result, expires: time.Now().Add(c.ttl)}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(e)
+	c.evict()
+}
+
+// clear removes all cached results of the given tool and returns their number.
+func (r *resultCache) clear(tool string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.tools[tool]
+	if !ok {
+		return 0
+	}
+	n := c.lru.Len()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	return n
+}
+
+// clearServer removes the cached results of all tools of the given server.
+func (r *resultCache) clearServer(server string) {
+	r.mu.Lock()
+	tools := make([]string, 0)
+	for tool := range r.tools {
+		if s, _, ok := splitServerToolName(tool); ok && s == server {
+			tools = append(tools, tool)
+		}
+	}
+	r.mu.Unlock()
+	for _, tool := range tools {
+		r.clear(tool)
+	}
+}
+
+// serverNames returns the names of all servers that have cached results.
+func (r *resultCache) serverNames() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var servers []string
+	for tool, c := range r.tools {
+		if s, _, ok := splitServerToolName(tool); ok && c.lru.Len() > 0 && !slices.Contains(servers, s) {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+// stats returns the number of cached results of the given tool and the number of cache hits & misses so far.
+func (r *resultCache) stats(tool string) (entries int, hits, misses uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.tools[tool]
+	if !ok {
+		return 0, 0, 0
+	}
+	return c.lru.Len(), c.hits, c.misses
+}
+
+// canonicalArgsKey returns a key that is identical for equivalent tool arguments,
+// regardless of the order of keys in JSON objects.
+func canonicalArgsKey(args any) (string, error) {
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", err
+	}
+	// encoding/json sorts the keys of maps, so re-encoding the generic value yields a canonical form
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// loadToolCachePolicies enables caching for all tools that have a cache policy in the DB.
+func (m *MCPService) loadToolCachePolicies() error {
+	policies, err := m.ListToolCachePolicies()
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		ttl, err := time.ParseDuration(p.TTL)
+		if err != nil {
+			return fmt.Errorf("invalid ttl in cache policy of tool %s: %w", p.Tool, err)
+		}
+		m.resultCache.setPolicy(p.Tool, ttl, p.MaxEntries)
+	}
+	return nil
+}
+
+// SetToolCachePolicy enables caching of the results of a tool or changes its cache settings.
+// The tool does not need to be registered yet.
+func (m *MCPService) SetToolCachePolicy(p *model.ToolCachePolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if _, _, ok := splitServerToolName(p.Tool); !ok {
+		return fmt.Errorf("invalid input: tool name does not contain a %s separator", serverToolNameSep)
+	}
+	ttl, _ := time.ParseDuration(p.TTL)
+
+	var existing model.ToolCachePolicy
+	err := m.db.Where("tool = ?", p.Tool).First(&existing).Error
+	switch {
+	case err == nil:
+		existing.TTL = p.TTL
+		existing.MaxEntries = p.MaxEntries
+		if err := m.db.Save(&existing).Error; err != nil {
+			return fmt.Errorf("failed to update cache policy: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := m.db.Create(p).Error; err != nil {
+			return fmt.Errorf("failed to create cache policy: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to get cache policy: %w", err)
+	}
+
+	m.resultCache.setPolicy(p.Tool, ttl, p.MaxEntries)
+	m.recordChange()
+	return nil
+}
+
+// ListToolCachePolicies returns the cache policies of all tools that have caching enabled.
+func (m *MCPService) ListToolCachePolicies() ([]model.ToolCachePolicy, error) {
+	var policies []model.ToolCachePolicy
+	if err := m.db.Order("tool").Find(&policies).Error; err != nil {
+		return nil, err
+	}
+	return policies, nil
+}
+
+// GetToolCacheStats returns the number of cached results of a tool and the number of cache hits & misses
+// since mcpjungle started.
+func (m *MCPService) GetToolCacheStats(tool string) (entries int, hits, misses uint64) {
+	return m.resultCache.stats(tool)
+}
+
+// DeleteToolCachePolicy disables caching for a tool and drops its cached results.
+func (m *MCPService) DeleteToolCachePolicy(tool string) error {
+	result := m.db.Unscoped().Where("tool = ?", tool).Delete(&model.ToolCachePolicy{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete cache policy: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrToolCachePolicyNotFound
+	}
+	m.resultCache.removePolicy(tool)
+	m.recordChange()
+	return nil
+}
+
+// ClearToolCache removes all cached results of a tool and returns their number.
+// Caching remains enabled for the tool.
+func (m *MCPService) ClearToolCache(tool string) (int, error) {
+	var p model.ToolCachePolicy
+	if err := m.db.Where("tool = ?", tool).First(&p).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrToolCachePolicyNotFound
+		}
+		return 0, fmt.Errorf("failed to get cache policy: %w", err)
+	}
+	return m.resultCache.clear(tool), nil
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCanonicalArgsKey(t *testing.T) {
+	a, err := canonicalArgsKey(map[string]any{"q": "go", "opts": map[string]any{"limit": 10, "lang": "en"}})
+	if err != nil {
+		t.Fatalf("canonicalArgsKey() error = %v", err)
+	}
+	b, err := canonicalArgsKey(map[string]any{"opts": map[string]any{"lang": "en", "limit": 10.0}, "q": "go"})
+	if err != nil {
+		t.Fatalf("canonicalArgsKey() error = %v", err)
+	}
+	if a != b {
+		t.Errorf("canonicalArgsKey() differs for equivalent arguments: %s != %s", a, b)
+	}
+
+	c, err := canonicalArgsKey(map[string]any{"q": "rust"})
+	if err != nil {
+		t.Fatalf("canonicalArgsKey() error = %v", err)
+	}
+	if a == c {
+		t.Error("canonicalArgsKey() is identical for different arguments")
+	}
+}
+
+func TestResultCache(t *testing.T) {
+	const tool = "docs__lookup"
+	result := mcp.NewToolResultText("ok")
+
+	t.Run("disabled by default", func(t *testing.T) {
+		r := newResultCache()
+		if _, ok := r.key(tool, nil); ok {
+			t.Fatal("key() = true for a tool without a cache policy")
+		}
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		r := newResultCache()
+		r.setPolicy(tool, time.Minute, 2)
+		r.put(tool, "a", result)
+		r.put(tool, "b", result)
+		if _, ok := r.get(tool, "a"); !ok {
+			t.Fatal("get(a) = miss, want hit")
+		}
+		r.put(tool, "c", result)
+
+		if _, ok := r.get(tool, "b"); ok {
+			t.Error("get(b) = hit, want it to be evicted")
+		}
+		if _, ok := r.get(tool, "a"); !ok {
+			t.Error("get(a) = miss, want hit")
+		}
+		if entries, hits, misses := r.stats(tool); entries != 2 || hits != 2 || misses != 1 {
+			t.Errorf("stats() = %d, %d, %d, want 2, 2, 1", entries, hits, misses)
+		}
+	})
+
+	t.Run("expires entries", func(t *testing.T) {
+		r := newResultCache()
+		r.setPolicy(tool, 10*time.Millisecond, 10)
+		r.put(tool, "a", result)
+		time.Sleep(20 * time.Millisecond)
+		if _, ok := r.get(tool, "a"); ok {
+			t.Error("get(a) = hit after ttl, want miss")
+		}
+	})
+
+	t.Run("clear server", func(t *testing.T) {
+		r := newResultCache()
+		r.setPolicy(tool, time.Minute, 10)
+		r.setPolicy("other__lookup", time.Minute, 10)
+		r.put(tool, "a", result)
+		r.put("other__lookup", "a", result)
+
+		r.clearServer("docs")
+		if _, ok := r.get(tool, "a"); ok {
+			t.Error("get() = hit after the server's cache was cleared")
+		}
+		if _, ok := r.get("other__lookup", "a"); !ok {
+			t.Error("get() = miss for a tool of another server")
+		}
+	})
+}
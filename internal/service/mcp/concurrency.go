@@ -0,0 +1,171 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+)
+
+// ServerBusyError is returned when a tool call is rejected because the MCP server
+// is already serving its maximum number of concurrent calls and no slot became free in time.
+type ServerBusyError struct {
+	Server string
+	// QueueFull is true if the call was rejected immediately because the wait queue was full.
+	// Otherwise, the call waited in the queue for QueueTimeout.
+	QueueFull    bool
+	QueueTimeout time.Duration
+}
+
+func (e *ServerBusyError) Error() string {
+	if e.QueueFull {
+		return fmt.Sprintf("MCP server %s is busy and too many calls are already waiting for it, try again later", e.Server)
+	}
+	return fmt.Sprintf(
+		"MCP server %s is busy, no call slot became free within %s, try again later", e.Server, e.QueueTimeout,
+	)
+}
+
+// serverSlots tracks the calls in progress and waiting for a single MCP server.
+type serverSlots struct {
+	active int
+	// waiters are the calls waiting for a free slot, in order of arrival.
+	// A waiter's channel is closed when a slot is handed over to it.
+	waiters []chan struct{}
+}
+
+// concurrencyLimits limits the number of concurrent tool calls per MCP server.
+// This matters most for stdio servers, where every call starts a new process.
+type concurrencyLimits struct {
+	servers map[string]*serverSlots
+	mu      sync.Mutex
+}
+
+func newConcurrencyLimits() *concurrencyLimits {
+	return &concurrencyLimits{servers: make(map[string]*serverSlots)}
+}
+
+// acquire waits until the given server has fewer than limit calls in progress and takes a slot.
+// At most maxQueue calls may wait at a time, and each of them for at most timeout.
+// The returned function must be called to free the slot once the call is done.
+// If limit is zero, calls are not limited and acquire returns immediately.
+func (c *concurrencyLimits) acquire(
+	ctx context.Context, server string, limit, maxQueue int, timeout time.Duration,
+) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	c.mu.Lock()
+	s, ok := c.servers[server]
+	if !ok {
+		s = &serverSlots{}
+		c.servers[server] = s
+	}
+	if s.active < limit && len(s.waiters) == 0 {
+		s.active++
+		c.reportLocked(server, s)
+		c.mu.Unlock()
+		return c.releaseFunc(server, s), nil
+	}
+	if len(s.waiters) >= maxQueue {
+		c.mu.Unlock()
+		return nil, &ServerBusyError{Server: server, QueueFull: true}
+	}
+	ready := make(chan struct{})
+	s.waiters = append(s.waiters, ready)
+	c.reportLocked(server, s)
+	c.mu.Unlock()
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	var err error
+	select {
+	case <-ready:
+		return c.releaseFunc(server, s), nil
+	case <-t.C:
+		err = &ServerBusyError{Server: server, QueueTimeout: timeout}
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if i := slices.Index(s.waiters, ready); i >= 0 {
+		s.waiters = slices.Delete(s.waiters, i, i+1)
+		c.reportLocked(server, s)
+		return nil, err
+	}
+	// a slot was handed over just as we gave up, pass it on
+	c.releaseLocked(server, s)
+	return nil, err
+}
+
+// releaseFunc returns a function that frees a slot of the given server exactly once.
+func (c *concurrencyLimits) releaseFunc(server string, s *serverSlots) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.releaseLocked(server, s)
+		})
+	}
+}
+
+// releaseLocked frees a slot of the given server, handing it over to the longest waiting call if any.
+// The caller must hold the lock.
+func (c *concurrencyLimits) releaseLocked(server string, s *serverSlots) {
+	if len(s.waiters) > 0 {
+		close(s.waiters[0])
+		s.waiters = s.waiters[1:]
+	} else {
+		s.active--
+	}
+	c.reportLocked(server, s)
+	if s.active == 0 && len(s.waiters) == 0 && c.servers[server] == s {
+		delete(c.servers, server)
+	}
+}
+
+// reportLocked updates the metrics of the given server.
+// Slots that were forgotten while calls were still using them are not reported.
+// The caller must hold the lock.
+func (c *concurrencyLimits) reportLocked(server string, s *serverSlots) {
+	if c.servers[server] != s {
+		return
+	}
+	metrics.SetServerCalls(server, s.active, len(s.waiters))
+}
+
+// forget drops the slots of the given server and its metrics.
+// Calls that hold or wait for one of its slots finish as usual, but new calls no longer count against them.
+func (c *concurrencyLimits) forget(server string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.servers, server)
+	metrics.DeleteServerCalls(server)
+}
+
+// serverNames returns the names of all servers that have calls in progress or waiting.
+func (c *concurrencyLimits) serverNames() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Collect(maps.Keys(c.servers))
+}
+
+// usage returns the number of calls in progress and waiting for the given server.
+// It only tracks servers whose concurrency is limited.
+func (c *concurrencyLimits) usage(server string) (active, queued int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.servers[server]
+	if !ok {
+		return 0, 0
+	}
+	return s.active, len(s.waiters)
+}
@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unlimited", func(t *testing.T) {
+		c := newConcurrencyLimits()
+		for i := 0; i < 10; i++ {
+			if _, err := c.acquire(ctx, "s", 0, 0, time.Second); err != nil {
+				t.Fatalf("acquire() error = %v", err)
+			}
+		}
+		if active, queued := c.usage("s"); active != 0 || queued != 0 {
+			t.Errorf("usage() = %d, %d, want 0, 0 for unlimited server", active, queued)
+		}
+	})
+
+	t.Run("queued call gets the freed slot", func(t *testing.T) {
+		c := newConcurrencyLimits()
+		release, err := c.acquire(ctx, "s", 1, 1, time.Second)
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+
+		acquired := make(chan error)
+		go func() {
+			release, err := c.acquire(ctx, "s", 1, 1, time.Second)
+			if err == nil {
+				release()
+			}
+			acquired <- err
+		}()
+		waitForQueued(t, c, "s", 1)
+
+		// the queue is full, so the next call is rejected immediately
+		_, err = c.acquire(ctx, "s", 1, 1, time.Second)
+		var busyErr *ServerBusyError
+		if !errors.As(err, &busyErr) || !busyErr.QueueFull {
+			t.Fatalf("acquire() error = %v, want a queue full error", err)
+		}
+
+		release()
+		release() // releasing twice must not free another slot
+		if err := <-acquired; err != nil {
+			t.Fatalf("queued acquire() error = %v", err)
+		}
+		if active, queued := c.usage("s"); active != 0 || queued != 0 {
+			t.Errorf("usage() = %d, %d, want 0, 0 after all calls finished", active, queued)
+		}
+	})
+
+	t.Run("queue timeout", func(t *testing.T) {
+		c := newConcurrencyLimits()
+		release, err := c.acquire(ctx, "s", 1, 1, time.Second)
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		defer release()
+
+		_, err = c.acquire(ctx, "s", 1, 1, 10*time.Millisecond)
+		var busyErr *ServerBusyError
+		if !errors.As(err, &busyErr) || busyErr.QueueFull {
+			t.Fatalf("acquire() error = %v, want a queue timeout error", err)
+		}
+		if active, queued := c.usage("s"); active != 1 || queued != 0 {
+			t.Errorf("usage() = %d, %d, want 1, 0", active, queued)
+		}
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		c := newConcurrencyLimits()
+		release, err := c.acquire(ctx, "s", 1, 1, time.Second)
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		defer release()
+
+		cctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := c.acquire(cctx, "s", 1, 1, time.Second); !errors.Is(err, context.Canceled) {
+			t.Fatalf("acquire() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("forgotten while in use", func(t *testing.T) {
+		c := newConcurrencyLimits()
+		oldRelease, err := c.acquire(ctx, "s", 1, 1, time.Second)
+		if err != nil {
+			t.Fatalf("acquire() error = %v", err)
+		}
+		c.forget("s")
+		if names := c.serverNames(); len(names) != 0 {
+			t.Errorf("serverNames() = %v after forget, want none", names)
+		}
+
+		// calls made after forget don't wait for the calls made before it
+		release, err := c.acquire(ctx, "s", 1, 1, 10*time.Millisecond)
+		if err != nil {
+			t.Fatalf("acquire() error = %v after forget, want a free slot", err)
+		}
+		oldRelease()
+		if active, queued := c.usage("s"); active != 1 || queued != 0 {
+			t.Errorf("usage() = %d, %d after releasing a forgotten slot, want 1, 0", active, queued)
+		}
+		release()
+		if active, queued := c.usage("s"); active != 0 || queued != 0 {
+			t.Errorf("usage() = %d, %d, want 0, 0 after all calls finished", active, queued)
+		}
+	})
+}
+
+// waitForQueued waits until the given number of calls are waiting for the server.
+func waitForQueued(t *testing.T, c *concurrencyLimits, server string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, queued := c.usage(server); queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d queued calls", n)
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// quotaEvents remembers which exhausted quotas have already been reported,
+// so that a single event is published per quota and period rather than one for every rejected call.
+type quotaEvents struct {
+	// reported maps a quota to the time at which it resets
+	reported map[string]time.Time
+	mu       sync.Mutex
+}
+
+func newQuotaEvents() *quotaEvents {
+	return &quotaEvents{reported: make(map[string]time.Time)}
+}
+
+// shouldReport returns true if the given exhausted quota hasn't been reported in its current period yet.
+func (q *quotaEvents) shouldReport(e *RateLimitError) bool {
+	key := string(e.Scope) + "/" + e.Target + "/" + e.Limit
+	now := time.Now()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if resetsAt, ok := q.reported[key]; ok && now.Before(resetsAt) {
+		return false
+	}
+	q.reported[key] = now.Add(e.RetryAfter)
+	return true
+}
+
+// SetEventPublisher registers the publisher that events about MCP servers, tools and quotas are published to.
+func (m *MCPService) SetEventPublisher(p events.Publisher) {
+	m.events = p
+}
+
+// publishEvent publishes an event if an event publisher is registered.
+func (m *MCPService) publishEvent(t types.EventType, data map[string]any) {
+	if m.events == nil {
+		return
+	}
+	m.events.Publish(t, data)
+}
+
+// publishToolsEnabled publishes an event about tools that were enabled or disabled by an admin.
+func (m *MCPService) publishToolsEnabled(enabled bool, toolNames ...string) {
+	t := types.EventToolDisabled
+	if enabled {
+		t = types.EventToolEnabled
+	}
+	m.publishEvent(t, map[string]any{"tools": toolNames})
+}
+
+// publishLimitExceeded publishes an event if a tool call was rejected because a quota is exhausted.
+// Exceeding a rate limit is usually transient, so it isn't published.
+func (m *MCPService) publishLimitExceeded(e *RateLimitError, client, tool string) {
+	if e.Limit != LimitDailyQuota && e.Limit != LimitMonthlyQuota {
+		return
+	}
+	if !m.quotaEvents.shouldReport(e) {
+		return
+	}
+	m.publishEvent(types.EventQuotaExceeded, map[string]any{
+		"scope":             e.Scope,
+		"target":            e.Target,
+		"limit":             e.Limit,
+		"client":            client,
+		"tool":              tool,
+		"retryAfterSeconds": int(e.RetryAfter.Seconds()),
+	})
+}
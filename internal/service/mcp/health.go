@@ -0,0 +1,273 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// HealthCheckConfig configures the background health checker of MCP servers.
+type HealthCheckConfig struct {
+	// Interval is the time between two consecutive health checks of all MCP servers.
+	// Health checks are disabled if it is zero.
+	Interval time.Duration
+
+	// Timeout is the maximum time allowed for a single health check,
+	// including setting up a session with the MCP server.
+	Timeout time.Duration
+
+	// FailureThreshold is the number of consecutive failed health checks after which
+	// the tools of an MCP server are removed from the MCP proxy.
+	// The tools are added back as soon as a health check succeeds.
+	// If it is zero, tools are never removed automatically.
+	FailureThreshold int
+}
+
+// serverHealth tracks the results of health checks of a single MCP server.
+type serverHealth struct {
+	lastSuccess time.Time
+	lastFailure time.Time
+	lastError   string
+	latency     time.Duration
+
+	consecutiveFailures int
+
+	// toolsDisabled is true if the server's tools have been removed from the MCP proxy
+	// because the server is unhealthy
+	toolsDisabled bool
+}
+
+func (h *serverHealth) status() *types.McpServerStatus {
+	s := &types.McpServerStatus{
+		Health:              types.ServerHealthUnknown,
+		LatencyMs:           h.latency.Milliseconds(),
+		ConsecutiveFailures: h.consecutiveFailures,
+		ToolsDisabled:       h.toolsDisabled,
+		LastError:           h.lastError,
+	}
+	if !h.lastSuccess.IsZero() {
+		t := h.lastSuccess
+		s.LastSuccess = &t
+		s.Health = types.ServerHealthHealthy
+	}
+	if !h.lastFailure.IsZero() {
+		t := h.lastFailure
+		s.LastFailure = &t
+		if h.consecutiveFailures > 0 {
+			s.Health = types.ServerHealthUnhealthy
+		}
+	}
+	return s
+}
+
+// StartHealthChecker starts checking the health of all registered MCP servers in the background.
+// Every check establishes a new session with the server and sends it an MCP ping.
+// Checks count against the server's concurrency limit like tool calls do.
+// The checker stops when ctx is done.
+func (m *MCPService) StartHealthChecker(ctx context.Context, conf HealthCheckConfig) {
+	if conf.Interval <= 0 {
+		slog.Info("health checks of MCP servers are disabled")
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(conf.Interval)
+		defer ticker.Stop()
+		for {
+			m.checkAllServers(ctx, conf)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// checkAllServers runs a health check on all registered MCP servers concurrently
+// and waits for all of them to finish.
+func (m *MCPService) checkAllServers(ctx context.Context, conf HealthCheckConfig) {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		slog.Error("failed to list MCP servers for health check", logging.KeyError, err)
+		return
+	}
+
+	// forget about servers that have been deregistered since the last run
+	registered := make(map[string]struct{}, len(servers))
+	for _, s := range servers {
+		registered[s.Name] = struct{}{}
+	}
+	m.healthMu.Lock()
+	for name := range m.health {
+		if _, ok := registered[name]; !ok {
+			delete(m.health, name)
+		}
+	}
+	m.healthMu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range servers {
+		wg.Add(1)
+		go func(s *model.McpServer) {
+			defer wg.Done()
+			m.checkServer(ctx, s, conf)
+		}(&servers[i])
+	}
+	wg.Wait()
+}
+
+// checkServer runs a single health check on the given MCP server and records its result.
+func (m *MCPService) checkServer(ctx context.Context, s *model.McpServer, conf HealthCheckConfig) {
+	if conf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conf.Timeout)
+		defer cancel()
+	}
+
+	// a check takes a call slot so that it never exceeds the server's concurrency limit,
+	// which matters most for stdio servers where every check starts a new process
+	policy := m.callPolicyFor(s)
+	release, err := m.concurrency.acquire(ctx, s.Name, policy.MaxConcurrency, policy.MaxQueue, policy.QueueTimeout)
+	if err != nil {
+		// a server that is busy serving tool calls isn't unhealthy, so the check is skipped
+		slog.Debug("skipping health check of busy MCP server", logging.KeyServer, s.Name, logging.KeyError, err)
+		return
+	}
+	defer release()
+
+	start := time.Now()
+	err = m.pingServer(ctx, s)
+	m.recordHealthCheck(s, err, time.Since(start), conf.FailureThreshold)
+}
+
+// pingServer establishes a new session with the MCP server and sends it a ping.
+func (m *MCPService) pingServer(ctx context.Context, s *model.McpServer) error {
+	c, err := m.newMcpServerSession(ctx, s)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if err := c.Ping(ctx); err != nil {
+		return fmt.Errorf("ping failed: %w", err)
+	}
+	return nil
+}
+
+// recordHealthCheck updates the health of the given server with the result of a health check.
+// If the number of consecutive failures reaches the threshold, the server's tools are removed from the proxy.
+// They are restored once a check succeeds again.
+func (m *MCPService) recordHealthCheck(s *model.McpServer, checkErr error, latency time.Duration, threshold int) {
+	logger := slog.With(logging.KeyServer, s.Name)
+
+	m.healthMu.Lock()
+	h, ok := m.health[s.Name]
+	if !ok {
+		h = &serverHealth{}
+		m.health[s.Name] = h
+	}
+	h.latency = latency
+
+	var suspend, restore, wentUnhealthy, recovered bool
+	if checkErr != nil {
+		h.lastFailure = time.Now().UTC()
+		h.lastError = checkErr.Error()
+		h.consecutiveFailures++
+		if h.consecutiveFailures == 1 {
+			logger.Warn("MCP server health check failed", logging.KeyError, checkErr)
+			wentUnhealthy = true
+		}
+		if threshold > 0 && h.consecutiveFailures >= threshold && !h.toolsDisabled {
+			h.toolsDisabled = true
+			suspend = true
+		}
+	} else {
+		if h.consecutiveFailures > 0 {
+			logger.Info("MCP server has recovered", "failed_checks", h.consecutiveFailures)
+			recovered = true
+		}
+		h.lastSuccess = time.Now().UTC()
+		h.lastError = ""
+		h.consecutiveFailures = 0
+		if h.toolsDisabled {
+			h.toolsDisabled = false
+			restore = true
+		}
+	}
+	m.healthMu.Unlock()
+
+	if wentUnhealthy {
+		m.publishEvent(types.EventServerUnhealthy, map[string]any{"server": s.Name, "error": checkErr.Error()})
+	}
+	if recovered {
+		m.publishEvent(types.EventServerRecovered, map[string]any{"server": s.Name})
+	}
+
+	// the proxy is updated outside the lock because tool callbacks may call back into this service
+	if suspend {
+		logger.Warn("removing tools of unhealthy MCP server from the proxy", "failed_checks", threshold)
+		if err := m.suspendServerTools(s); err != nil {
+			logger.Error("failed to remove tools of unhealthy MCP server from the proxy", logging.KeyError, err)
+		}
+	}
+	if restore {
+		logger.Info("restoring tools of recovered MCP server in the proxy")
+		if err := m.restoreServerTools(s); err != nil {
+			logger.Error("failed to restore tools of recovered MCP server in the proxy", logging.KeyError, err)
+		}
+	}
+}
+
+// serverToolsSuspended returns true if the tools of the given server are currently
+// removed from the MCP proxy because the server is unhealthy.
+func (m *MCPService) serverToolsSuspended(name string) bool {
+	m.healthMu.Lock()
+	defer m.healthMu.Unlock()
+	h, ok := m.health[name]
+	return ok && h.toolsDisabled
+}
+
+// suspendServerTools removes all enabled tools of the given server from the MCP proxy.
+// Unlike disabling the tools, their state in the DB is left untouched.
+func (m *MCPService) suspendServerTools(s *model.McpServer) error {
+	var tools []model.Tool
+	if err := m.db.Where("server_id = ? AND enabled = ?", s.ID, true).Find(&tools).Error; err != nil {
+		return fmt.Errorf("failed to get tools for server %s from DB: %w", s.Name, err)
+	}
+	if len(tools) == 0 {
+		return nil
+	}
+	names := make([]string, len(tools))
+	for i := range tools {
+		names[i] = mergeServerToolNames(s.Name, tools[i].Name)
+	}
+	m.mcpProxyServer.DeleteTools(names...)
+	m.deleteToolInstances(names...)
+	m.notifyToolDeletion(names...)
+	return nil
+}
+
+// restoreServerTools adds all enabled tools of the given server back to the MCP proxy.
+func (m *MCPService) restoreServerTools(s *model.McpServer) error {
+	var tools []model.Tool
+	if err := m.db.Where("server_id = ? AND enabled = ?", s.ID, true).Find(&tools).Error; err != nil {
+		return fmt.Errorf("failed to get tools for server %s from DB: %w", s.Name, err)
+	}
+	for i := range tools {
+		mcpTool, err := convertToolModelToMcpObject(&tools[i])
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tools[i].Name, err)
+		}
+		mcpTool.Name = mergeServerToolNames(s.Name, tools[i].Name)
+
+		m.mcpProxyServer.AddTool(mcpTool, m.MCPProxyToolCallHandler)
+		m.addToolInstance(mcpTool)
+		m.notifyToolAddition(mcpTool.Name)
+	}
+	return nil
+}
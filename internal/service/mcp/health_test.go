@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestHealthCheckTransitions(t *testing.T) {
+	m := newTestService(t)
+	events := &eventRecorder{}
+	m.SetEventPublisher(events)
+	upstream := newTestUpstream(t)
+	s := upstream.register(t, m, "up", nil)
+	events.take()
+
+	conf := HealthCheckConfig{Timeout: 5 * time.Second, FailureThreshold: 2}
+	steps := []struct {
+		name         string
+		down         bool
+		wantHealth   types.McpServerHealth
+		wantFailures int
+		wantTools    bool
+		wantEvents   []types.EventType
+	}{
+		{"healthy", false, types.ServerHealthHealthy, 0, true, nil},
+		{"first failure", true, types.ServerHealthUnhealthy, 1, true, []types.EventType{types.EventServerUnhealthy}},
+		{"failure threshold reached", true, types.ServerHealthUnhealthy, 2, false, []types.EventType{types.EventToolRemoved}},
+		{"still failing", true, types.ServerHealthUnhealthy, 3, false, nil},
+		{
+			"recovered", false, types.ServerHealthHealthy, 0, true,
+			// a tool.added event is published for each of the upstream's tools
+			[]types.EventType{types.EventServerRecovered, types.EventToolAdded, types.EventToolAdded, types.EventToolAdded},
+		},
+		{"still healthy", false, types.ServerHealthHealthy, 0, true, nil},
+	}
+	for _, step := range steps {
+		upstream.down.Store(step.down)
+		m.checkServer(context.Background(), s, conf)
+
+		status := m.GetServerStatus("up")
+		if status.Health != step.wantHealth || status.ConsecutiveFailures != step.wantFailures {
+			t.Errorf("%s: health = %s with %d failures, want %s with %d",
+				step.name, status.Health, status.ConsecutiveFailures, step.wantHealth, step.wantFailures)
+		}
+		if status.ToolsDisabled == step.wantTools {
+			t.Errorf("%s: tools disabled = %v, want %v", step.name, status.ToolsDisabled, !step.wantTools)
+		}
+		if _, ok := m.GetToolInstance("up__echo"); ok != step.wantTools {
+			t.Errorf("%s: tool served by the proxy = %v, want %v", step.name, ok, step.wantTools)
+		}
+		if got := events.take(); !slices.Equal(got, step.wantEvents) {
+			t.Errorf("%s: events = %v, want %v", step.name, got, step.wantEvents)
+		}
+	}
+}
+
+func TestHealthCheckRespectsConcurrencyLimit(t *testing.T) {
+	m := newTestService(t)
+	upstream := newTestUpstream(t)
+	s := upstream.register(t, m, "up", nil)
+
+	one, zero := 1, 0
+	if err := s.SetSettings(&model.ServerSettings{MaxConcurrency: &one, MaxQueue: &zero}); err != nil {
+		t.Fatalf("failed to set settings: %v", err)
+	}
+	policy := m.callPolicyFor(s)
+	release, err := m.concurrency.acquire(context.Background(), "up", 1, 0, policy.QueueTimeout)
+	if err != nil {
+		t.Fatalf("failed to take the only call slot: %v", err)
+	}
+
+	// the only slot is taken by a tool call, so the check is skipped rather than failed
+	upstream.down.Store(true)
+	m.checkServer(context.Background(), s, HealthCheckConfig{Timeout: 5 * time.Second})
+	if status := m.GetServerStatus("up"); status.Health != types.ServerHealthUnknown {
+		t.Errorf("health = %s while the server is busy, want %s", status.Health, types.ServerHealthUnknown)
+	}
+
+	release()
+	m.checkServer(context.Background(), s, HealthCheckConfig{Timeout: 5 * time.Second})
+	if status := m.GetServerStatus("up"); status.Health != types.ServerHealthUnhealthy {
+		t.Errorf("health = %s once a slot is free, want %s", status.Health, types.ServerHealthUnhealthy)
+	}
+	if active, queued := m.concurrency.usage("up"); active != 0 || queued != 0 {
+		t.Errorf("usage() = %d, %d after the check, want 0, 0", active, queued)
+	}
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// serverLogBufferSize is the maximum number of stderr lines retained in memory for each MCP server.
+// Once the buffer is full, the oldest lines are discarded to make room for new ones.
+const serverLogBufferSize = 1000
+
+// serverLogFollowerBufferSize is the number of lines that can be queued for a follower before
+// it is considered too slow and new lines start getting dropped for it.
+const serverLogFollowerBufferSize = 100
+
+// serverLogBuffer is a bounded ring buffer of the most recent stderr lines written by an MCP server.
+// It also fans out new lines to any followers.
+type serverLogBuffer struct {
+	entries []types.ServerLogEntry
+	// start is the index of the oldest entry in the buffer once it is full
+	start int
+
+	followers map[chan types.ServerLogEntry]struct{}
+}
+
+func (b *serverLogBuffer) append(e types.ServerLogEntry) {
+	if len(b.entries) < serverLogBufferSize {
+		b.entries = append(b.entries, e)
+	} else {
+		b.entries[b.start] = e
+		b.start = (b.start + 1) % serverLogBufferSize
+	}
+	for ch := range b.followers {
+		select {
+		case ch <- e:
+		default:
+			// don't let a slow follower block the MCP server's stderr
+		}
+	}
+}
+
+// tail returns the last n entries in chronological order.
+// If n is zero or negative, all entries are returned.
+func (b *serverLogBuffer) tail(n int) []types.ServerLogEntry {
+	ordered := make([]types.ServerLogEntry, 0, len(b.entries))
+	ordered = append(ordered, b.entries[b.start:]...)
+	ordered = append(ordered, b.entries[:b.start]...)
+	if n > 0 && n < len(ordered) {
+		ordered = ordered[len(ordered)-n:]
+	}
+	return ordered
+}
+
+// serverLogs holds the log buffers of all MCP servers, keyed by server name.
+type serverLogs struct {
+	buffers map[string]*serverLogBuffer
+	mu      sync.Mutex
+}
+
+func newServerLogs() *serverLogs {
+	return &serverLogs{buffers: make(map[string]*serverLogBuffer)}
+}
+
+// buffer returns the log buffer of the given server, creating it if it doesn't exist.
+// The caller must hold the lock.
+func (l *serverLogs) buffer(server string) *serverLogBuffer {
+	b, ok := l.buffers[server]
+	if !ok {
+		b = &serverLogBuffer{followers: make(map[chan types.ServerLogEntry]struct{})}
+		l.buffers[server] = b
+	}
+	return b
+}
+
+// append records a line written by the given server.
+func (l *serverLogs) append(server, line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.buffer(server).append(types.ServerLogEntry{Time: time.Now().UTC(), Line: line})
+}
+
+// tail returns the last n lines written by the given server.
+func (l *serverLogs) tail(server string, n int) []types.ServerLogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buffers[server]
+	if !ok {
+		return []types.ServerLogEntry{}
+	}
+	return b.tail(n)
+}
+
+// follow returns the last n lines written by the given server and a channel that receives all lines
+// written after that.
+// The channel is closed once ctx is done or the server's logs are discarded.
+func (l *serverLogs) follow(
+	ctx context.Context, server string, n int,
+) ([]types.ServerLogEntry, <-chan types.ServerLogEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.buffer(server)
+	ch := make(chan types.ServerLogEntry, serverLogFollowerBufferSize)
+	b.followers[ch] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, ok := b.followers[ch]; ok {
+			delete(b.followers, ch)
+			close(ch)
+		}
+	}()
+
+	return b.tail(n), ch
+}
+
+// discard deletes all lines of the given server and stops all its followers.
+func (l *serverLogs) discard(server string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buffers[server]
+	if !ok {
+		return
+	}
+	for ch := range b.followers {
+		close(ch)
+	}
+	b.followers = nil
+	delete(l.buffers, server)
+}
+
+// serverNames returns the names of all servers that have logs.
+func (l *serverLogs) serverNames() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Collect(maps.Keys(l.buffers))
+}
+
+// GetServerLogs returns the last n lines written to stderr by the given MCP server, oldest first.
+// If n is zero or negative, all retained lines are returned.
+// Only stdio MCP servers produce logs, so the result is always empty for streamable http servers.
+func (m *MCPService) GetServerLogs(name string, n int) ([]types.ServerLogEntry, error) {
+	if err := m.checkServerExists(name); err != nil {
+		return nil, err
+	}
+	return m.serverLogs.tail(name, n), nil
+}
+
+// FollowServerLogs returns the last n lines written to stderr by the given MCP server along with
+// a channel that receives every line written after that.
+// The channel is closed when ctx is done or the server is deregistered.
+func (m *MCPService) FollowServerLogs(
+	ctx context.Context, name string, n int,
+) ([]types.ServerLogEntry, <-chan types.ServerLogEntry, error) {
+	if err := m.checkServerExists(name); err != nil {
+		return nil, nil, err
+	}
+	backlog, ch := m.serverLogs.follow(ctx, name, n)
+	return backlog, ch, nil
+}
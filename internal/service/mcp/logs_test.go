@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// lines returns the text of the given log entries.
+func lines(entries []types.ServerLogEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Line
+	}
+	return out
+}
+
+func TestServerLogsWrapAround(t *testing.T) {
+	l := newServerLogs()
+	for i := range serverLogBufferSize + 10 {
+		l.append("s", strconv.Itoa(i))
+	}
+
+	got := lines(l.tail("s", 0))
+	if len(got) != serverLogBufferSize {
+		t.Fatalf("buffer holds %d lines, want %d", len(got), serverLogBufferSize)
+	}
+	// the 10 oldest lines were overwritten and the rest are still in order
+	for i, line := range got {
+		if want := strconv.Itoa(i + 10); line != want {
+			t.Fatalf("line %d = %s, want %s", i, line, want)
+		}
+	}
+}
+
+func TestServerLogsTail(t *testing.T) {
+	l := newServerLogs()
+	for i := range 5 {
+		l.append("s", strconv.Itoa(i))
+	}
+	tests := []struct {
+		n    int
+		want []string
+	}{
+		{n: 2, want: []string{"3", "4"}},
+		{n: 5, want: []string{"0", "1", "2", "3", "4"}},
+		{n: 10, want: []string{"0", "1", "2", "3", "4"}},
+		{n: 0, want: []string{"0", "1", "2", "3", "4"}},
+		{n: -1, want: []string{"0", "1", "2", "3", "4"}},
+	}
+	for _, tt := range tests {
+		got := lines(l.tail("s", tt.n))
+		if len(got) != len(tt.want) {
+			t.Errorf("tail(%d) = %v, want %v", tt.n, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("tail(%d) = %v, want %v", tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+
+	if got := l.tail("unknown", 10); got == nil || len(got) != 0 {
+		t.Errorf("tail() of an unknown server = %v, want an empty slice", got)
+	}
+}
+
+func TestServerLogsFollow(t *testing.T) {
+	l := newServerLogs()
+	l.append("s", "old-1")
+	l.append("s", "old-2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	backlog, ch := l.follow(ctx, "s", 1)
+	if got := lines(backlog); len(got) != 1 || got[0] != "old-2" {
+		t.Fatalf("backlog = %v, want [old-2]", got)
+	}
+
+	l.append("s", "new")
+	l.append("other", "not for this follower")
+	select {
+	case e := <-ch:
+		if e.Line != "new" {
+			t.Fatalf("followed line = %s, want new", e.Line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("new line was not delivered to the follower")
+	}
+
+	cancel()
+	select {
+	case e, ok := <-ch:
+		if ok {
+			t.Fatalf("received %s after the context was cancelled, want the channel to be closed", e.Line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel was not closed after the context was cancelled")
+	}
+	// lines written after the follower stopped don't block or panic
+	l.append("s", "after")
+}
+
+func TestServerLogsDiscardStopsFollowers(t *testing.T) {
+	l := newServerLogs()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, ch := l.follow(ctx, "s", 0)
+	l.append("s", "line")
+
+	l.discard("s")
+	if e := <-ch; e.Line != "line" {
+		t.Fatalf("followed line = %s, want line", e.Line)
+	}
+	if _, ok := <-ch; ok {
+		t.Fatal("channel is still open after the logs were discarded")
+	}
+	if got := l.tail("s", 0); len(got) != 0 {
+		t.Errorf("tail() = %v after discard, want no lines", lines(got))
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/events"
 	"gorm.io/gorm"
 )
 
@@ -26,6 +27,43 @@ type MCPService struct {
 	// toolAdditionCallback is a callback that gets invoked when one or more tools is added
 	// (registered or (re)enabled) in mcpjungle.
 	toolAdditionCallback ToolAdditionCallback
+	// toolCallCallbacks are invoked after every call to an upstream tool
+	toolCallCallbacks []ToolCallCallback
+	toolCallMu        sync.RWMutex
+
+	// serverLogs retains the most recent stderr output of every stdio MCP server.
+	serverLogs *serverLogs
+
+	// health keeps track of the health check results of all MCP servers, keyed by server name.
+	health   map[string]*serverHealth
+	healthMu sync.Mutex
+
+	// connections holds the connection of every registered MCP server that the runtime state above belongs to,
+	// keyed by server name. It reveals servers that were changed by other mcpjungle servers sharing the database.
+	connections   map[string]serverConnection
+	connectionsMu sync.Mutex
+
+	// defaultCallPolicy is the call policy for all MCP servers that don't override it in their settings.
+	defaultCallPolicy CallPolicy
+	policyMu          sync.RWMutex
+
+	// breakers holds the circuit breakers of all MCP servers
+	breakers *circuitBreakers
+
+	// concurrency limits the number of tool calls in progress on every MCP server
+	concurrency *concurrencyLimits
+
+	// resultCache holds the cached results of tools that have caching enabled
+	resultCache *resultCache
+
+	// limiter enforces rate limits and quotas on tool calls made through the MCP proxy.
+	// It is nil if no limits are enforced.
+	limiter CallLimiter
+
+	// events receives events about changes to MCP servers & tools. It is nil if events are not published.
+	events events.Publisher
+	// quotaEvents prevents the same exhausted quota from being published repeatedly
+	quotaEvents *quotaEvents
 }
 
 // NewMCPService creates a new instance of MCPService.
@@ -41,9 +79,26 @@ func NewMCPService(db *gorm.DB, mcpProxyServer *server.MCPServer) (*MCPService,
 		// initialize the callbacks to NOOP functions
 		toolDeletionCallback: func(toolNames ...string) {},
 		toolAdditionCallback: func(toolName string) error { return nil },
+
+		serverLogs: newServerLogs(),
+		health:     make(map[string]*serverHealth),
+
+		connections: make(map[string]serverConnection),
+
+		defaultCallPolicy: DefaultCallPolicy,
+		breakers:          newCircuitBreakers(DefaultBreakerConfig),
+		concurrency:       newConcurrencyLimits(),
+		resultCache:       newResultCache(),
+		quotaEvents:       newQuotaEvents(),
+	}
+	if err := s.loadToolCachePolicies(); err != nil {
+		return nil, fmt.Errorf("failed to load tool cache policies: %w", err)
 	}
 	if err := s.initMCPProxyServer(); err != nil {
 		return nil, fmt.Errorf("failed to initialize MCP proxy server: %w", err)
 	}
+	if err := s.forgetStaleServers(); err != nil {
+		return nil, err
+	}
 	return s, nil
 }
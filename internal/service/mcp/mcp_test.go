@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// testUpstream is an upstream streamable HTTP MCP server for tests. It serves these tools:
+//   - echo returns the "text" argument it is called with
+//   - fail responds with a JSON-RPC error
+//   - slow returns after a second, unless the call is canceled before that
+type testUpstream struct {
+	*httptest.Server
+
+	// down makes the server answer every request with 503 Service Unavailable
+	down atomic.Bool
+	// requests counts the messages posted to the server, including those answered while it is down
+	requests atomic.Int32
+	// toolCalls counts the calls made to each tool
+	toolCalls map[string]*atomic.Int32
+}
+
+func newTestUpstream(t *testing.T) *testUpstream {
+	t.Helper()
+	u := &testUpstream{toolCalls: map[string]*atomic.Int32{"echo": {}, "fail": {}, "slow": {}}}
+
+	s := server.NewMCPServer("upstream", "0.1.0", server.WithToolCapabilities(true))
+	s.AddTool(
+		mcp.NewTool("echo", mcp.WithString("text")),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			u.toolCalls["echo"].Add(1)
+			return mcp.NewToolResultText(request.GetString("text", "")), nil
+		},
+	)
+	s.AddTool(
+		mcp.NewTool("fail"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			u.toolCalls["fail"].Add(1)
+			return nil, errors.New("upstream failure")
+		},
+	)
+	s.AddTool(
+		mcp.NewTool("slow"),
+		func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			u.toolCalls["slow"].Add(1)
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Second):
+				return mcp.NewToolResultText("done"), nil
+			}
+		},
+	)
+	h := server.NewStreamableHTTPServer(s)
+	u.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			u.requests.Add(1)
+		}
+		if u.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		h.ServeHTTP(w, r)
+	}))
+	t.Cleanup(u.Close)
+	return u
+}
+
+// register registers the upstream in the service under the given name and with the given settings, if any.
+func (u *testUpstream) register(t *testing.T, m *MCPService, name string, settings *model.ServerSettings) *model.McpServer {
+	t.Helper()
+	s, err := model.NewStreamableHTTPServer(name, "", u.URL+"/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server model: %v", err)
+	}
+	if settings != nil {
+		if err := s.SetSettings(settings); err != nil {
+			t.Fatalf("failed to set server settings: %v", err)
+		}
+	}
+	if err := m.RegisterMcpServer(context.Background(), s); err != nil {
+		t.Fatalf("failed to register server: %v", err)
+	}
+	return s
+}
+
+func newTestService(t *testing.T) *MCPService {
+	t.Helper()
+	m, err := NewMCPService(dbtest.New(t), server.NewMCPServer("test", "0.1.0", server.WithToolCapabilities(true)))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	return m
+}
+
+// eventRecorder is an events.Publisher that records the events published to it.
+type eventRecorder struct {
+	events []types.EventType
+	mu     sync.Mutex
+}
+
+func (r *eventRecorder) Publish(t types.EventType, _ map[string]any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, t)
+}
+
+// take returns the events published since the last call.
+func (r *eventRecorder) take() []types.EventType {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := r.events
+	r.events = nil
+	return e
+}
@@ -0,0 +1,185 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// maxRetryDelay caps the exponentially growing delay between two retries of a tool call.
+const maxRetryDelay = 30 * time.Second
+
+// CallPolicy controls how long mcpjungle waits for a tool call to an upstream MCP server
+// and how failed calls are retried.
+type CallPolicy struct {
+	// Timeout is the maximum time allowed for a single attempt of a tool call,
+	// including setting up a session with the MCP server.
+	Timeout time.Duration
+
+	// MaxRetries is the number of times a tool call is retried after a transport error.
+	// Tool calls are not guaranteed to be idempotent, so retries are disabled by default.
+	MaxRetries int
+
+	// RetryBackoff is the delay before the first retry. It doubles with every subsequent retry.
+	RetryBackoff time.Duration
+
+	// RetryOnTimeout allows retrying a tool call that timed out after it was sent to the server.
+	// The tool may have done its job regardless, so a server has to opt into this.
+	RetryOnTimeout bool
+
+	// MaxConcurrency is the maximum number of tool calls that can be in progress on a server at the same time.
+	// Further calls wait in a queue until a call finishes. Concurrency is not limited if it is zero.
+	MaxConcurrency int
+
+	// MaxQueue is the maximum number of calls that can wait for a server at the same time.
+	// Calls beyond that are rejected immediately.
+	MaxQueue int
+
+	// QueueTimeout is the maximum time a call waits in the queue before it is rejected.
+	QueueTimeout time.Duration
+}
+
+// DefaultCallPolicy is the call policy used for MCP servers that don't override it.
+var DefaultCallPolicy = CallPolicy{
+	Timeout:      60 * time.Second,
+	MaxRetries:   0,
+	RetryBackoff: 500 * time.Millisecond,
+
+	MaxConcurrency: 0,
+	MaxQueue:       100,
+	QueueTimeout:   30 * time.Second,
+}
+
+// Validate checks that the policy does not contain invalid values.
+func (p CallPolicy) Validate() error {
+	if p.Timeout <= 0 {
+		return fmt.Errorf("call timeout must be positive, got %s", p.Timeout)
+	}
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative, got %d", p.MaxRetries)
+	}
+	if p.RetryBackoff < 0 {
+		return fmt.Errorf("retry backoff must not be negative, got %s", p.RetryBackoff)
+	}
+	if p.MaxConcurrency < 0 {
+		return fmt.Errorf("max concurrency must not be negative, got %d", p.MaxConcurrency)
+	}
+	if p.MaxQueue < 0 {
+		return fmt.Errorf("max queue must not be negative, got %d", p.MaxQueue)
+	}
+	if p.QueueTimeout <= 0 {
+		return fmt.Errorf("queue timeout must be positive, got %s", p.QueueTimeout)
+	}
+	return nil
+}
+
+// retryDelay returns the time to wait before making the next attempt after the given number of failed attempts.
+func (p CallPolicy) retryDelay(failedAttempts int) time.Duration {
+	d := p.RetryBackoff
+	for i := 1; i < failedAttempts && d < maxRetryDelay; i++ {
+		d *= 2
+	}
+	return min(d, maxRetryDelay)
+}
+
+// SetDefaultCallPolicy changes the call policy used for all MCP servers that don't override it.
+func (m *MCPService) SetDefaultCallPolicy(p CallPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	m.policyMu.Lock()
+	defer m.policyMu.Unlock()
+	m.defaultCallPolicy = p
+	return nil
+}
+
+// GetDefaultCallPolicy returns the call policy used for all MCP servers that don't override it.
+func (m *MCPService) GetDefaultCallPolicy() CallPolicy {
+	m.policyMu.RLock()
+	defer m.policyMu.RUnlock()
+	return m.defaultCallPolicy
+}
+
+// callPolicyFor returns the effective call policy for the given server,
+// ie, the default policy overridden by the server's own settings.
+func (m *MCPService) callPolicyFor(s *model.McpServer) CallPolicy {
+	p := m.GetDefaultCallPolicy()
+
+	settings, err := s.GetSettings()
+	if err != nil {
+		// settings are validated before they're stored, so this should never happen
+		slog.Error(
+			"failed to read MCP server settings, using defaults", logging.KeyServer, s.Name, logging.KeyError, err,
+		)
+		return p
+	}
+	return p.WithSettings(settings)
+}
+
+// WithSettings returns the policy overridden by the fields that are set in the given settings.
+func (p CallPolicy) WithSettings(settings *model.ServerSettings) CallPolicy {
+	if d, err := time.ParseDuration(settings.CallTimeout); err == nil && d > 0 {
+		p.Timeout = d
+	}
+	if settings.MaxRetries != nil {
+		p.MaxRetries = *settings.MaxRetries
+	}
+	if d, err := time.ParseDuration(settings.RetryBackoff); err == nil && d >= 0 {
+		p.RetryBackoff = d
+	}
+	if settings.RetryOnTimeout != nil {
+		p.RetryOnTimeout = *settings.RetryOnTimeout
+	}
+	if settings.MaxConcurrency != nil {
+		p.MaxConcurrency = *settings.MaxConcurrency
+	}
+	if settings.MaxQueue != nil {
+		p.MaxQueue = *settings.MaxQueue
+	}
+	if d, err := time.ParseDuration(settings.QueueTimeout); err == nil && d > 0 {
+		p.QueueTimeout = d
+	}
+	return p
+}
+
+// errCallTimeout is wrapped by the errors of tool call attempts that exceeded their timeout.
+var errCallTimeout = errors.New("tool call timed out")
+
+// wrapCallTimeout returns a clear error if a tool call attempt failed because it exceeded its timeout.
+// parent is the context of the whole tool call and attemptCtx is the context of the failed attempt.
+func wrapCallTimeout(parent, attemptCtx context.Context, timeout time.Duration, err error) error {
+	if timedOut(parent, attemptCtx) {
+		return fmt.Errorf("%w after %s: %w", errCallTimeout, timeout, err)
+	}
+	return err
+}
+
+// timedOut returns true if a tool call attempt exceeded its own timeout, rather than the whole call being canceled.
+func timedOut(parent, attemptCtx context.Context) bool {
+	return parent.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded)
+}
+
+// isServerError returns true if the error was reported by the MCP server in a JSON-RPC error response,
+// ie, the server received the request and refused or failed to handle it.
+func isServerError(err error) bool {
+	var se *serverError
+	return errors.As(err, &se)
+}
+
+// sleepCtx waits for the given duration and returns true.
+// It returns false if ctx is done before that.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+func TestCallPolicyRetryDelay(t *testing.T) {
+	p := CallPolicy{RetryBackoff: 500 * time.Millisecond}
+	tests := []struct {
+		failedAttempts int
+		want           time.Duration
+	}{
+		{1, 500 * time.Millisecond},
+		{2, time.Second},
+		{3, 2 * time.Second},
+		{10, maxRetryDelay},
+		{100, maxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := p.retryDelay(tt.failedAttempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.failedAttempts, got, tt.want)
+		}
+	}
+}
+
+func TestCallPolicyFor(t *testing.T) {
+	m := &MCPService{defaultCallPolicy: DefaultCallPolicy}
+
+	t.Run("no settings", func(t *testing.T) {
+		got := m.callPolicyFor(&model.McpServer{Name: "s"})
+		if got != DefaultCallPolicy {
+			t.Errorf("callPolicyFor() = %+v, want defaults %+v", got, DefaultCallPolicy)
+		}
+	})
+
+	t.Run("overridden settings", func(t *testing.T) {
+		s := &model.McpServer{Name: "s"}
+		retries, concurrency := 3, 2
+		err := s.SetSettings(&model.ServerSettings{
+			CallTimeout:    "5s",
+			MaxRetries:     &retries,
+			MaxConcurrency: &concurrency,
+			QueueTimeout:   "10s",
+		})
+		if err != nil {
+			t.Fatalf("SetSettings() error = %v", err)
+		}
+		want := DefaultCallPolicy
+		want.Timeout = 5 * time.Second
+		want.MaxRetries = 3
+		want.MaxConcurrency = 2
+		want.QueueTimeout = 10 * time.Second
+		if got := m.callPolicyFor(s); got != want {
+			t.Errorf("callPolicyFor() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("zero retries override a non-zero default", func(t *testing.T) {
+		m := &MCPService{defaultCallPolicy: CallPolicy{Timeout: time.Second, MaxRetries: 2}}
+		s := &model.McpServer{Name: "s"}
+		retries := 0
+		if err := s.SetSettings(&model.ServerSettings{MaxRetries: &retries}); err != nil {
+			t.Fatalf("SetSettings() error = %v", err)
+		}
+		if got := m.callPolicyFor(s); got.MaxRetries != 0 {
+			t.Errorf("callPolicyFor().MaxRetries = %d, want 0", got.MaxRetries)
+		}
+	})
+}
+
+// TestIsServerError pins how the errors returned by the mcp-go client are classified,
+// so that upgrading mcp-go can't silently change which tool calls are retried.
+func TestIsServerError(t *testing.T) {
+	m := newTestService(t)
+	upstream := newTestUpstream(t)
+	s := upstream.register(t, m, "up", nil)
+	ctx := context.Background()
+
+	call := func(t *testing.T, tool string) error {
+		t.Helper()
+		c, err := m.newMcpServerSession(ctx, s)
+		if err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		defer c.Close()
+		req := mcp.CallToolRequest{}
+		req.Params.Name = tool
+		_, err = callTool(ctx, s, c, req)
+		if err == nil {
+			t.Fatalf("calling tool %s succeeded, want an error", tool)
+		}
+		return err
+	}
+
+	t.Run("error responses", func(t *testing.T) {
+		for _, tool := range []string{"fail", "unknown"} {
+			err := call(t, tool)
+			if !isServerError(err) {
+				t.Errorf("isServerError(%v) = false for tool %s, want true", err, tool)
+			}
+			if strings.Contains(err.Error(), "transport error") {
+				t.Errorf("error %q of tool %s is reported as a transport error", err, tool)
+			}
+		}
+	})
+
+	t.Run("failures to reach the server", func(t *testing.T) {
+		c, err := m.newMcpServerSession(ctx, s)
+		if err != nil {
+			t.Fatalf("failed to create session: %v", err)
+		}
+		upstream.down.Store(true)
+		defer upstream.down.Store(false)
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "echo"
+		_, err = callTool(ctx, s, c, req)
+		if err == nil || isServerError(err) {
+			t.Errorf("isServerError(%v) = true for an unavailable server, want false", err)
+		}
+		_ = c.Close()
+	})
+
+	t.Run("errors of the client library", func(t *testing.T) {
+		// a client that was not initialized fails without sending anything, with a plain error
+		c := client.NewClient(&serverErrorTransport{Interface: nil})
+		_, err := c.CallTool(ctx, mcp.CallToolRequest{})
+		if err == nil || isServerError(err) {
+			t.Errorf("isServerError(%v) = true for an error of the client, want false", err)
+		}
+		if isServerError(errors.New("transport closed")) {
+			t.Error("isServerError() = true for a plain error, want false")
+		}
+	})
+}
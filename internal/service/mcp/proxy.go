@@ -2,9 +2,14 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 )
 
@@ -12,6 +17,7 @@ import (
 // by forwarding the request to the appropriate upstream MCP server and
 // relaying the response back.
 func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	start := time.Now()
 	name := request.Params.Name
 	serverName, toolName, ok := splitServerToolName(name)
 	if !ok {
@@ -38,17 +44,28 @@ func (m *MCPService) MCPProxyToolCallHandler(ctx context.Context, request mcp.Ca
 		)
 	}
 
-	mcpClient, err := newMcpServerSession(ctx, server)
-	if err != nil {
-		return nil, err
+	// enforce rate limits & quotas before the call reaches the upstream server
+	if err := m.checkCallLimits(ctx, serverName, name); err != nil {
+		var rateLimitErr *RateLimitError
+		if !errors.As(err, &rateLimitErr) {
+			return nil, fmt.Errorf("failed to check rate limits: %w", err)
+		}
+		caller := callerName(ctx)
+		metrics.ObserveToolCall(serverName, toolName, caller, metrics.OutcomeRejected, time.Since(start))
+		slog.Debug(
+			"tool call rejected by rate limit",
+			logging.KeyServer, serverName, logging.KeyTool, toolName, logging.KeyClient, caller,
+			logging.KeyError, err,
+		)
+		m.publishLimitExceeded(rateLimitErr, caller, name)
+		return rateLimitErr.toolResult(), nil
 	}
-	defer mcpClient.Close()
 
 	// Ensure the tool name is set correctly, ie, without the server name prefix
 	request.Params.Name = toolName
 
 	// forward the request to the upstream MCP server and relay the response back
-	return mcpClient.CallTool(ctx, request)
+	return m.callUpstreamTool(ctx, server, request)
 }
 
 // initMCPProxyServer initializes the MCP proxy server.
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// rateLimitMetaKey is the key under which details of a rejected call are added to the _meta of its result.
+const rateLimitMetaKey = "mcpjungle/rateLimit"
+
+// Kinds of limits a tool call can exceed.
+const (
+	LimitRate         = "rate"
+	LimitDailyQuota   = "daily_quota"
+	LimitMonthlyQuota = "monthly_quota"
+)
+
+// CallLimiter decides whether a tool call made through the MCP proxy may be forwarded to the upstream server.
+type CallLimiter interface {
+	// AllowToolCall records a call to the given tool (canonical name) on the given server made by the given client.
+	// client is empty if the call was not made by an authenticated MCP client.
+	// It returns a *RateLimitError if the call exceeds a limit and must be rejected.
+	AllowToolCall(client, server, tool string) error
+}
+
+// RateLimitError is returned when a tool call is rejected because it exceeds a rate limit or quota.
+type RateLimitError struct {
+	Scope  types.RateLimitScope
+	Target string
+	// Limit is the kind of limit that was exceeded, one of LimitRate, LimitDailyQuota or LimitMonthlyQuota
+	Limit string
+	// RetryAfter is the time after which the call can succeed again
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	desc := "rate limit"
+	switch e.Limit {
+	case LimitDailyQuota:
+		desc = "daily quota"
+	case LimitMonthlyQuota:
+		desc = "monthly quota"
+	}
+	return fmt.Sprintf(
+		"%s %s has exceeded its %s, try again in %s", e.Scope, e.Target, desc, e.RetryAfter.Round(time.Second),
+	)
+}
+
+// toolResult converts the error into a tool result that MCP clients (and LLMs) can act upon.
+// mcp-go discards everything but the message of errors returned by tool handlers,
+// so the details of the limit are returned as part of an error result instead.
+func (e *RateLimitError) toolResult() *mcp.CallToolResult {
+	r := mcp.NewToolResultError(e.Error())
+	r.Meta = map[string]any{
+		rateLimitMetaKey: map[string]any{
+			"scope":             e.Scope,
+			"target":            e.Target,
+			"limit":             e.Limit,
+			"retryAfterSeconds": int(math.Ceil(e.RetryAfter.Seconds())),
+		},
+	}
+	return r
+}
+
+// SetCallLimiter registers the limiter that is consulted before a tool call from the MCP proxy is forwarded.
+func (m *MCPService) SetCallLimiter(l CallLimiter) {
+	m.limiter = l
+}
+
+// checkCallLimits asks the call limiter, if any, whether a call to the given tool may proceed.
+func (m *MCPService) checkCallLimits(ctx context.Context, server, tool string) error {
+	if m.limiter == nil {
+		return nil
+	}
+	client := ""
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil {
+		client = c.Name
+	}
+	return m.limiter.AllowToolCall(client, server, tool)
+}
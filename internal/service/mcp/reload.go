@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// Reload makes the in-memory state of the service match the registry database.
+// It is needed when the database was changed without going through this service,
+// eg- when a backup is restored or another mcpjungle server sharing the database changed the registry.
+// Enabled tools that are missing from the MCP proxy or have changed are (re)added to it and
+// tools that no longer exist or are disabled are removed from it, notifying the registered callbacks.
+// Tools of servers that are currently suspended because they are unhealthy are left out.
+// No events are published, since they were already published when the change was made.
+func (m *MCPService) Reload() error {
+	tools, err := m.ListTools()
+	if err != nil {
+		return fmt.Errorf("failed to list tools from DB: %w", err)
+	}
+	want := make(map[string]mcp.Tool, len(tools))
+	for i := range tools {
+		if !tools[i].Enabled || m.serverToolsSuspended(ServerOfTool(tools[i].Name)) {
+			continue
+		}
+		tool, err := convertToolModelToMcpObject(&tools[i])
+		if err != nil {
+			return fmt.Errorf("failed to convert tool model to MCP object for tool %s: %w", tools[i].Name, err)
+		}
+		want[tool.GetName()] = tool
+	}
+
+	var removed []string
+	m.mu.RLock()
+	for name := range m.toolInstances {
+		if _, ok := want[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	m.mu.RUnlock()
+	if len(removed) > 0 {
+		slices.Sort(removed)
+		m.mcpProxyServer.DeleteTools(removed...)
+		m.deleteToolInstances(removed...)
+		m.toolDeletionCallback(removed...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(want)) {
+		tool := want[name]
+		if cur, ok := m.GetToolInstance(name); ok && reflect.DeepEqual(cur, tool) {
+			continue
+		}
+		m.mcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
+		m.addToolInstance(tool)
+		if err := m.toolAdditionCallback(name); err != nil {
+			slog.Error("tool addition callback failed", logging.KeyTool, name, logging.KeyError, err)
+		}
+	}
+
+	if err := m.reloadToolCachePolicies(); err != nil {
+		return fmt.Errorf("failed to reload tool cache policies: %w", err)
+	}
+	return m.forgetStaleServers()
+}
+
+// recordChange notifies the other mcpjungle servers that share the database that the tools have changed.
+// Failing to do so doesn't fail the change, which has already been made.
+func (m *MCPService) recordChange() {
+	if err := changes.Record(m.db, model.RegistryScopeTools); err != nil {
+		slog.Error("other servers may not see the change to the tools until they restart", logging.KeyError, err)
+	}
+}
+
+// reloadToolCachePolicies applies the cache policies in the DB and disables caching for tools that no longer have one.
+func (m *MCPService) reloadToolCachePolicies() error {
+	policies, err := m.ListToolCachePolicies()
+	if err != nil {
+		return err
+	}
+	keep := make(map[string]bool, len(policies))
+	for _, p := range policies {
+		keep[p.Tool] = true
+	}
+	for _, tool := range m.resultCache.policyTools() {
+		if !keep[tool] {
+			m.resultCache.removePolicy(tool)
+		}
+	}
+	return m.loadToolCachePolicies()
+}
+
+// serverConnection identifies the upstream of a registered MCP server.
+// It changes when the server's transport or connection config is updated, or when it is deregistered
+// and registered again, which gives it a new ID.
+type serverConnection struct {
+	id        uint
+	transport types.McpServerTransport
+	config    string
+}
+
+func connectionOf(s *model.McpServer) serverConnection {
+	return serverConnection{id: s.ID, transport: s.Transport, config: string(s.Config)}
+}
+
+// setConnection records the connection that the runtime state of the given server belongs to.
+func (m *MCPService) setConnection(s *model.McpServer) {
+	m.connectionsMu.Lock()
+	defer m.connectionsMu.Unlock()
+	m.connections[s.Name] = connectionOf(s)
+}
+
+// forgetStaleServers drops the runtime state of MCP servers that are no longer registered or whose
+// connection has changed since the state was collected, eg- because another mcpjungle server
+// updated the server or deregistered it and registered it again.
+func (m *MCPService) forgetStaleServers() error {
+	servers, err := m.ListMcpServers()
+	if err != nil {
+		return fmt.Errorf("failed to list MCP servers from DB: %w", err)
+	}
+	registered := make(map[string]serverConnection, len(servers))
+	for i := range servers {
+		registered[servers[i].Name] = connectionOf(&servers[i])
+	}
+
+	stale := make(map[string]bool)
+	m.connectionsMu.Lock()
+	for name, conn := range m.connections {
+		if cur, ok := registered[name]; !ok || cur != conn {
+			stale[name] = true
+		}
+	}
+	m.connections = registered
+	m.connectionsMu.Unlock()
+	for _, name := range m.serversWithState() {
+		if _, ok := registered[name]; !ok {
+			stale[name] = true
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(stale)) {
+		m.resetServerState(name)
+	}
+	return nil
+}
+
+// serversWithState returns the names of all MCP servers that have runtime state in memory.
+func (m *MCPService) serversWithState() []string {
+	m.healthMu.Lock()
+	names := slices.Collect(maps.Keys(m.health))
+	m.healthMu.Unlock()
+	names = append(names, m.breakers.serverNames()...)
+	names = append(names, m.concurrency.serverNames()...)
+	names = append(names, m.resultCache.serverNames()...)
+	names = append(names, m.serverLogs.serverNames()...)
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// resetServerState drops the health, circuit breaker, concurrency, cached results and logs of the given server.
+func (m *MCPService) resetServerState(name string) {
+	m.healthMu.Lock()
+	delete(m.health, name)
+	m.healthMu.Unlock()
+	m.breakers.remove(name)
+	m.concurrency.forget(name)
+	m.resultCache.clearServer(name)
+	m.serverLogs.discard(name)
+}
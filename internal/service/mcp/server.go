@@ -2,11 +2,23 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
 )
 
+// ErrServerNotFound is returned when the requested MCP server is not registered in mcpjungle.
+var ErrServerNotFound = errors.New("mcp server not found")
+
 // RegisterMcpServer registers a new MCP server in the database.
 // It also registers all the Tools provided by the server.
 // Tool registration is on best-effort basis and does not fail the server registration.
@@ -16,7 +28,7 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 		return err
 	}
 
-	mcpClient, err := newMcpServerSession(ctx, s)
+	mcpClient, err := m.newMcpServerSession(ctx, s)
 	if err != nil {
 		return err
 	}
@@ -26,10 +38,15 @@ func (m *MCPService) RegisterMcpServer(ctx context.Context, s *model.McpServer)
 	if err := m.db.Create(s).Error; err != nil {
 		return fmt.Errorf("failed to register mcp server: %w", err)
 	}
+	defer m.recordChange()
+	// drop any state left behind by a server of the same name that was deregistered by another mcpjungle server
+	m.setConnection(s)
+	m.resetServerState(s.Name)
 
 	if err = m.registerServerTools(ctx, s, mcpClient); err != nil {
 		return fmt.Errorf("failed to register tools for MCP server %s: %w", s.Name, err)
 	}
+	m.publishEvent(types.EventServerRegistered, map[string]any{"server": s.Name})
 	return nil
 }
 
@@ -49,9 +66,138 @@ func (m *MCPService) DeregisterMcpServer(name string) error {
 			err,
 		)
 	}
+	defer m.recordChange()
 	if err := m.db.Unscoped().Delete(s).Error; err != nil {
 		return fmt.Errorf("failed to deregister server %s: %w", name, err)
 	}
+	m.connectionsMu.Lock()
+	delete(m.connections, name)
+	m.connectionsMu.Unlock()
+	m.resetServerState(name)
+
+	m.publishEvent(types.EventServerDeregistered, map[string]any{"server": name})
+	return nil
+}
+
+// UpdateMcpServer changes the configuration of a registered MCP server.
+// Changes to its description and settings are applied in place.
+// If its transport or connection config changed, its tools are fetched from the new upstream
+// and replace the old ones, which enables all of them.
+// The update is atomic: the server keeps its old configuration and tools unless it could connect
+// to the new upstream and the new configuration and tools were all stored.
+func (m *MCPService) UpdateMcpServer(ctx context.Context, s *model.McpServer) error {
+	cur, err := m.GetMcpServer(s.Name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServerNotFound
+		}
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", s.Name, err)
+	}
+	curInput, err := cur.ToInput()
+	if err != nil {
+		return fmt.Errorf("failed to read configuration of MCP server %s: %w", s.Name, err)
+	}
+	newInput, err := s.ToInput()
+	if err != nil {
+		return fmt.Errorf("failed to read new configuration of MCP server %s: %w", s.Name, err)
+	}
+
+	if curInput.Transport == newInput.Transport &&
+		curInput.URL == newInput.URL &&
+		curInput.BearerToken == newInput.BearerToken &&
+		curInput.Command == newInput.Command &&
+		slices.Equal(curInput.Args, newInput.Args) &&
+		maps.Equal(curInput.Env, newInput.Env) {
+		// the connection is unchanged, settings are read on every tool call so they take effect immediately
+		err := m.db.Model(cur).Select("Description", "Settings").Updates(&model.McpServer{
+			Description: s.Description,
+			Settings:    s.Settings,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update MCP server %s: %w", s.Name, err)
+		}
+		m.publishEvent(types.EventServerUpdated, map[string]any{"server": s.Name})
+		return nil
+	}
+
+	// fetch the tools from the new upstream before anything is changed
+	mcpClient, err := m.newMcpServerSession(ctx, s)
+	if err != nil {
+		return fmt.Errorf("failed to connect to MCP server %s with its new configuration: %w", s.Name, err)
+	}
+	defer mcpClient.Close()
+	resp, err := mcpClient.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		return fmt.Errorf("failed to fetch tools from MCP server %s with its new configuration: %w", s.Name, err)
+	}
+	oldTools, err := m.ListToolsByServer(s.Name)
+	if err != nil {
+		return err
+	}
+
+	newTools := make([]model.Tool, len(resp.Tools))
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(cur).Select("Transport", "Description", "Config", "Settings").Updates(&model.McpServer{
+			Transport:   s.Transport,
+			Description: s.Description,
+			Config:      s.Config,
+			Settings:    s.Settings,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update MCP server %s: %w", s.Name, err)
+		}
+		if err := tx.Unscoped().Where("server_id = ?", cur.ID).Delete(&model.Tool{}).Error; err != nil {
+			return fmt.Errorf("failed to delete tools of MCP server %s: %w", s.Name, err)
+		}
+		for i, tool := range resp.Tools {
+			jsonSchema, _ := json.Marshal(tool.InputSchema)
+			newTools[i] = model.Tool{
+				ServerID:    cur.ID,
+				Name:        tool.GetName(),
+				Description: tool.Description,
+				InputSchema: jsonSchema,
+			}
+			if err := tx.Create(&newTools[i]).Error; err != nil {
+				return fmt.Errorf("failed to register tool %s of MCP server %s: %w", tool.GetName(), s.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	defer m.recordChange()
+	s.Model = cur.Model
+
+	// swap the tools in the MCP proxy
+	oldToolNames := make([]string, len(oldTools))
+	for i, tool := range oldTools {
+		oldToolNames[i] = tool.Name
+	}
+	m.mcpProxyServer.DeleteTools(oldToolNames...)
+	m.deleteToolInstances(oldToolNames...)
+	m.notifyToolDeletion(oldToolNames...)
+
+	// the runtime state of the server belongs to its old upstream
+	m.setConnection(s)
+	m.resetServerState(s.Name)
+
+	for i := range newTools {
+		tool, err := convertToolModelToMcpObject(&newTools[i])
+		if err != nil {
+			slog.Error(
+				"failed to add tool to the MCP proxy",
+				logging.KeyServer, s.Name, logging.KeyTool, newTools[i].Name, logging.KeyError, err,
+			)
+			continue
+		}
+		tool.Name = mergeServerToolNames(s.Name, tool.Name)
+		m.mcpProxyServer.AddTool(tool, m.MCPProxyToolCallHandler)
+		m.addToolInstance(tool)
+		m.notifyToolAddition(tool.Name)
+	}
+
+	m.publishEvent(types.EventServerUpdated, map[string]any{"server": s.Name})
 	return nil
 }
 
@@ -72,3 +218,30 @@ func (m *MCPService) GetMcpServer(name string) (*model.McpServer, error) {
 	}
 	return &serverModel, nil
 }
+
+// GetServerStatus returns the runtime status of the given MCP server.
+func (m *MCPService) GetServerStatus(name string) *types.McpServerStatus {
+	m.healthMu.Lock()
+	var status *types.McpServerStatus
+	if h, ok := m.health[name]; ok {
+		status = h.status()
+	} else {
+		status = &types.McpServerStatus{Health: types.ServerHealthUnknown}
+	}
+	m.healthMu.Unlock()
+
+	status.CircuitBreaker = m.breakers.state(name)
+	status.ActiveCalls, status.QueuedCalls = m.concurrency.usage(name)
+	return status
+}
+
+// checkServerExists returns ErrServerNotFound if no MCP server with the given name is registered.
+func (m *MCPService) checkServerExists(name string) error {
+	if _, err := m.GetMcpServer(name); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrServerNotFound
+		}
+		return fmt.Errorf("failed to get MCP server %s from DB: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestUpdateMcpServer(t *testing.T) {
+	m := newTestService(t)
+	upstream := newTestUpstream(t)
+	upstream.register(t, m, "up", nil)
+	if _, err := m.DisableTools("up__echo"); err != nil {
+		t.Fatalf("failed to disable tool: %v", err)
+	}
+	events := &eventRecorder{}
+	m.SetEventPublisher(events)
+
+	// the new upstream can't be reached, so nothing is changed
+	unreachable, err := model.NewStreamableHTTPServer("up", "moved", "http://127.0.0.1:1/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server model: %v", err)
+	}
+	if err := m.UpdateMcpServer(context.Background(), unreachable); err == nil {
+		t.Fatal("UpdateMcpServer() error = nil for an unreachable upstream, want error")
+	}
+	s, err := m.GetMcpServer("up")
+	if err != nil {
+		t.Fatalf("failed to get server: %v", err)
+	}
+	if in, _ := s.ToInput(); in.URL != upstream.URL+"/mcp" || in.Description != "" {
+		t.Errorf("server = %+v after a failed update, want it unchanged", in)
+	}
+	if _, ok := m.GetToolInstance("up__fail"); !ok {
+		t.Error("tool up__fail is no longer served after a failed update")
+	}
+	if got := events.take(); len(got) != 0 {
+		t.Errorf("events = %v after a failed update, want none", got)
+	}
+	if tool, err := m.GetTool("up__echo"); err != nil || tool.Enabled {
+		t.Errorf("tool up__echo = %+v, %v after a failed update, want it to stay disabled", tool, err)
+	}
+
+	// the tools of the new upstream replace the old ones and are all enabled
+	moved := newTestUpstream(t)
+	update, err := model.NewStreamableHTTPServer("up", "moved", moved.URL+"/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server model: %v", err)
+	}
+	if err := m.UpdateMcpServer(context.Background(), update); err != nil {
+		t.Fatalf("UpdateMcpServer() error = %v", err)
+	}
+	if got := events.take(); !slices.Contains(got, types.EventServerUpdated) {
+		t.Errorf("events = %v after the update, want %s", got, types.EventServerUpdated)
+	}
+	s, err = m.GetMcpServer("up")
+	if err != nil {
+		t.Fatalf("failed to get server: %v", err)
+	}
+	if in, _ := s.ToInput(); in.URL != moved.URL+"/mcp" || in.Description != "moved" || s.ID != update.ID {
+		t.Errorf("server = %+v (ID %d) after the update, want the new configuration in the same row", in, s.ID)
+	}
+	tools, err := m.ListToolsByServer("up")
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	if len(tools) != 3 {
+		t.Errorf("server has %d tools after the update, want 3", len(tools))
+	}
+	for _, tool := range tools {
+		if !tool.Enabled {
+			t.Errorf("tool %s is disabled after the update, want all tools enabled", tool.Name)
+		}
+		if _, ok := m.GetToolInstance(tool.Name); !ok {
+			t.Errorf("tool %s is not served by the proxy after the update", tool.Name)
+		}
+	}
+
+	if _, err := m.InvokeTool(context.Background(), "up__echo", map[string]any{"text": "hi"}); err != nil {
+		t.Fatalf("failed to call tool after the update: %v", err)
+	}
+	if got := moved.toolCalls["echo"].Load(); got != 1 {
+		t.Errorf("new upstream received %d calls, want 1", got)
+	}
+
+	// changing only the description doesn't reconnect, but is an update all the same
+	describe, err := model.NewStreamableHTTPServer("up", "described", moved.URL+"/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server model: %v", err)
+	}
+	if err := m.UpdateMcpServer(context.Background(), describe); err != nil {
+		t.Fatalf("UpdateMcpServer() error = %v", err)
+	}
+	if got := events.take(); !slices.Equal(got, []types.EventType{types.EventServerUpdated}) {
+		t.Errorf("events = %v after a description change, want only %s", got, types.EventServerUpdated)
+	}
+}
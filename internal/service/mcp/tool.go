@@ -4,12 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ToolDeletionCallback is a function type that can be registered to be called
@@ -22,6 +29,32 @@ type ToolDeletionCallback func(toolNames ...string)
 // The callback receives the name of the added tool as argument.
 type ToolAdditionCallback func(toolName string) error
 
+// ToolCall describes a completed call to an upstream tool.
+type ToolCall struct {
+	// Server is the name of the MCP server that provides the tool
+	Server string
+	// Tool is the canonical name of the tool (ie, including the server name prefix)
+	Tool string
+	// Client is the name of the MCP client that made the call
+	Client string
+
+	Arguments any
+	// Result is the result returned by the tool. It is nil if the call failed.
+	Result *mcp.CallToolResult
+	// Err is the error that caused the call to fail, if any
+	Err error
+	// Outcome is the outcome of the call as reported in metrics (eg- "success", "tool_error")
+	Outcome string
+
+	Start    time.Time
+	Duration time.Duration
+}
+
+// ToolCallCallback is a function type that can be registered to be called
+// after every call to an upstream tool, whether it succeeded or not.
+// Callbacks are invoked synchronously, so they must not block.
+type ToolCallCallback func(call *ToolCall)
+
 // ListTools returns all tools registered in the registry.
 func (m *MCPService) ListTools() ([]model.Tool, error) {
 	var tools []model.Tool
@@ -107,21 +140,20 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 		)
 	}
 
-	mcpClient, err := newMcpServerSession(ctx, serverModel)
-	if err != nil {
-		return nil, err
-	}
-	defer mcpClient.Close()
-
 	callToolReq := mcp.CallToolRequest{}
 	callToolReq.Params.Name = toolName
 	callToolReq.Params.Arguments = args
 
-	callToolResp, err := mcpClient.CallTool(ctx, callToolReq)
+	callToolResp, err := m.callUpstreamTool(ctx, serverModel, callToolReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tool %s on MCP server %s: %w", toolName, serverName, err)
 	}
 
+	return ToolInvokeResultOf(callToolResp), nil
+}
+
+// ToolInvokeResultOf converts the result of a tool call into the form returned by the HTTP API.
+func ToolInvokeResultOf(callToolResp *mcp.CallToolResult) *types.ToolInvokeResult {
 	// NOTE: callToolResp.Content is a list of Content objects.
 	// If the tool returns a list as its result, it gets converted to a list of Content objects.
 	// But if the tool returns any other type of object (string, map, number, etc), then it is
@@ -147,14 +179,161 @@ func (m *MCPService) InvokeTool(ctx context.Context, name string, args map[strin
 		contentList = append(contentList, m)
 	}
 
-	result := &types.ToolInvokeResult{
+	return &types.ToolInvokeResult{
 		Meta:    callToolResp.Meta,
 		IsError: callToolResp.IsError,
 		Content: contentList,
 	}
+}
+
+// callUpstreamTool creates a new session with the upstream MCP server, calls the tool on it and returns the result.
+// The request must contain the tool's name as known to the upstream server, ie, without the server name prefix.
+// Both the MCP proxy and the HTTP API route their tool calls through this method so that
+// every call is instrumented the same way.
+func (m *MCPService) callUpstreamTool(
+	ctx context.Context, s *model.McpServer, request mcp.CallToolRequest,
+) (result *mcp.CallToolResult, err error) {
+	start := time.Now()
+	outcome := metrics.OutcomeError
+	caller := callerName(ctx)
+
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		"mcp.tool_call",
+		trace.WithAttributes(
+			attribute.String("mcp.server.name", s.Name),
+			attribute.String("mcp.tool.name", request.Params.Name),
+			attribute.String("mcp.client.name", caller),
+		),
+	)
+	logger := slog.With(logging.KeyServer, s.Name, logging.KeyTool, request.Params.Name, logging.KeyClient, caller)
+	defer func() {
+		d := time.Since(start)
+		span.SetAttributes(attribute.String("mcp.tool_call.outcome", outcome))
+		span.End()
+		metrics.ObserveToolCall(s.Name, request.Params.Name, caller, outcome, d)
+		logger.Debug("tool call completed", "outcome", outcome, "duration", d)
+
+		m.notifyToolCall(&ToolCall{
+			Server:    s.Name,
+			Tool:      mergeServerToolNames(s.Name, request.Params.Name),
+			Client:    caller,
+			Arguments: request.Params.Arguments,
+			Result:    result,
+			Err:       err,
+			Outcome:   outcome,
+			Start:     start,
+			Duration:  d,
+		})
+	}()
+
+	policy := m.callPolicyFor(s)
+
+	// serve the result from the cache if caching is enabled for this tool
+	canonicalName := mergeServerToolNames(s.Name, request.Params.Name)
+	cacheKey, cacheable := m.resultCache.key(canonicalName, request.Params.Arguments)
+	if cacheable {
+		if cached, ok := m.resultCache.get(canonicalName, cacheKey); ok {
+			outcome = metrics.OutcomeSuccess
+			span.SetAttributes(attribute.Bool("mcp.tool_call.cache_hit", true))
+			return cached, nil
+		}
+	}
+
+	// wait for a free slot if the server is already serving its maximum number of concurrent calls.
+	// The slot is held across retries so that retries don't jump the queue.
+	queueStart := time.Now()
+	release, err := m.concurrency.acquire(ctx, s.Name, policy.MaxConcurrency, policy.MaxQueue, policy.QueueTimeout)
+	if err != nil {
+		outcome = metrics.OutcomeRejected
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "tool call rejected")
+		logger.Warn("tool call rejected, MCP server is busy", logging.KeyError, err)
+		return nil, err
+	}
+	defer release()
+	if policy.MaxConcurrency > 0 {
+		span.SetAttributes(attribute.Int64("mcp.tool_call.queue_wait_ms", time.Since(queueStart).Milliseconds()))
+	}
+
+	attempts := 0
+	for {
+		generation, retryAfter, ok := m.breakers.allow(s.Name)
+		if !ok {
+			if attempts > 0 {
+				// the circuit opened while retrying, the call fails with the error of the last attempt
+				logger.Warn("not retrying upstream tool call, circuit breaker is open", "attempt", attempts)
+				break
+			}
+			// fail fast instead of waiting for an upstream server that is known to be failing
+			err = &CircuitOpenError{Server: s.Name, RetryAfter: retryAfter}
+			outcome = metrics.OutcomeRejected
+			break
+		}
+		attempts++
+		var retryable bool
+		result, retryable, err = m.attemptUpstreamToolCall(ctx, s, request, policy)
+		m.breakers.record(s.Name, generation, breakerResultOf(ctx, err))
+		if err == nil || !retryable || attempts > policy.MaxRetries {
+			break
+		}
+		delay := policy.retryDelay(attempts)
+		logger.Warn(
+			"upstream tool call failed, retrying",
+			"attempt", attempts, "retry_in", delay, logging.KeyError, err,
+		)
+		if !sleepCtx(ctx, delay) {
+			break
+		}
+	}
+	span.SetAttributes(attribute.Int("mcp.tool_call.attempts", attempts))
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "upstream tool call failed")
+		logger.Error("upstream tool call failed", "attempts", attempts, logging.KeyError, err)
+		return nil, err
+	}
+
+	outcome = metrics.OutcomeSuccess
+	if result.IsError {
+		outcome = metrics.OutcomeToolError
+	} else if cacheable {
+		// errors are never cached, the next call might succeed
+		m.resultCache.put(canonicalName, cacheKey, result)
+	}
 	return result, nil
 }
 
+// attemptUpstreamToolCall makes a single attempt to call a tool on the upstream MCP server.
+// A new session is created for the attempt and closed before returning.
+// The whole attempt, including the session setup, must finish within the policy's timeout.
+//
+// If the attempt failed, it also reports whether it may be retried. A failure to connect to the server is retried,
+// because the tool hasn't been called yet. A call that timed out is only retried if the policy allows it,
+// and an error reported by the server or the tool is never retried.
+func (m *MCPService) attemptUpstreamToolCall(
+	ctx context.Context, s *model.McpServer, request mcp.CallToolRequest, policy CallPolicy,
+) (result *mcp.CallToolResult, retryable bool, err error) {
+	attemptCtx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+
+	mcpClient, err := m.newMcpServerSession(attemptCtx, s)
+	if err != nil {
+		return nil, true, wrapCallTimeout(ctx, attemptCtx, policy.Timeout, err)
+	}
+	defer mcpClient.Close()
+
+	result, err = callTool(attemptCtx, s, mcpClient, request)
+	if err != nil {
+		if timedOut(ctx, attemptCtx) {
+			return nil, policy.RetryOnTimeout, wrapCallTimeout(ctx, attemptCtx, policy.Timeout, err)
+		}
+		return nil, !isServerError(err), err
+	}
+	return result, false, nil
+}
+
 // SetToolDeletionCallback registers a callback function to be called
 // whenever one or more tools are deleted (deregistered) or disabled.
 // The callback receives the names of the deleted tools as arguments.
@@ -169,6 +348,24 @@ func (m *MCPService) SetToolAdditionCallback(callback ToolAdditionCallback) {
 	m.toolAdditionCallback = callback
 }
 
+// AddToolCallCallback registers a callback function to be called after every call to an upstream tool.
+// Unlike the other callbacks, any number of tool call callbacks can be registered.
+func (m *MCPService) AddToolCallCallback(callback ToolCallCallback) {
+	m.toolCallMu.Lock()
+	defer m.toolCallMu.Unlock()
+	m.toolCallCallbacks = append(m.toolCallCallbacks, callback)
+}
+
+// notifyToolCall calls all registered tool call callbacks with the given call.
+func (m *MCPService) notifyToolCall(call *ToolCall) {
+	m.toolCallMu.RLock()
+	callbacks := m.toolCallCallbacks
+	m.toolCallMu.RUnlock()
+	for _, cb := range callbacks {
+		cb(call)
+	}
+}
+
 // EnableTools enables one or more tools.
 // If the entity is a tool name, only that tool is enabled.
 // If the entity is a server name, all tools of that server are enabled.
@@ -213,6 +410,14 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		if err := m.db.Save(&tool).Error; err != nil {
 			return nil, fmt.Errorf("failed to set tool %s enabled=%t: %w", entity, enabled, err)
 		}
+		m.publishToolsEnabled(enabled, entity)
+		m.recordChange()
+
+		if enabled && m.serverToolsSuspended(s.Name) {
+			// the server is currently unhealthy, so the tool will only be added to
+			// the MCP proxy server once the server recovers
+			return []string{entity}, nil
+		}
 
 		if enabled {
 			// if the tool was enabled, add it back to the MCP proxy server
@@ -252,6 +457,8 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		return nil, fmt.Errorf("failed to get tools for server %s: %w", entity, err)
 	}
 
+	suspended := m.serverToolsSuspended(s.Name)
+
 	var changedToolNames []string
 	for i := range tools {
 		if tools[i].Enabled == enabled {
@@ -263,6 +470,12 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		}
 		canonicalToolName := mergeServerToolNames(s.Name, tools[i].Name)
 
+		if enabled && suspended {
+			// the tool will be added to the MCP proxy server once the unhealthy server recovers
+			changedToolNames = append(changedToolNames, canonicalToolName)
+			continue
+		}
+
 		if enabled {
 			mcpTool, err := convertToolModelToMcpObject(&tools[i])
 			if err != nil {
@@ -283,6 +496,10 @@ func (m *MCPService) setToolsEnabled(entity string, enabled bool) ([]string, err
 		changedToolNames = append(changedToolNames, canonicalToolName)
 	}
 
+	if len(changedToolNames) > 0 {
+		m.publishToolsEnabled(enabled, changedToolNames...)
+		m.recordChange()
+	}
 	return changedToolNames, nil
 }
 
@@ -309,7 +526,10 @@ func (m *MCPService) registerServerTools(ctx context.Context, s *model.McpServer
 		if err := m.db.Create(t).Error; err != nil {
 			// If registration of a tool fails, we should not fail the entire server registration.
 			// Instead, continue with the next tool.
-			log.Printf("[ERROR] failed to register tool %s in DB: %v", canonicalToolName, err)
+			slog.Error(
+				"failed to register tool in DB",
+				logging.KeyServer, s.Name, logging.KeyTool, canonicalToolName, logging.KeyError, err,
+			)
 		} else {
 			// Set tool name to include the server name prefix to make it recognizable by MCPJungle
 			// then add the tool to the MCP proxy server
@@ -377,6 +597,9 @@ func (m *MCPService) deleteToolInstances(toolNames ...string) {
 // notifyToolDeletion calls all registered tool deletion callbacks with the given tool names.
 func (m *MCPService) notifyToolDeletion(toolNames ...string) {
 	m.toolDeletionCallback(toolNames...)
+	if len(toolNames) > 0 {
+		m.publishEvent(types.EventToolRemoved, map[string]any{"tools": toolNames})
+	}
 }
 
 // notifyToolAddition calls all registered tool addition callbacks with the given tool names.
@@ -385,6 +608,7 @@ func (m *MCPService) notifyToolAddition(toolName string) {
 	if err := m.toolAdditionCallback(toolName); err != nil {
 		// log the issue, but do not fail the entire operation
 		// as the tool has already been added successfully
-		log.Printf("[ERROR] tool addition callback failed for tool %s: %v", toolName, err)
+		slog.Error("tool addition callback failed", logging.KeyTool, toolName, logging.KeyError, err)
 	}
+	m.publishEvent(types.EventToolAdded, map[string]any{"tool": toolName})
 }
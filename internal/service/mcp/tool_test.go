@@ -0,0 +1,136 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func TestToolCallRetries(t *testing.T) {
+	retries, yes := 2, true
+	tests := []struct {
+		name     string
+		tool     string
+		down     bool
+		settings model.ServerSettings
+		// wantCalls is the number of times the tool is called, or the number of messages posted if the server is down
+		wantCalls int32
+	}{
+		{
+			name:      "error reported by the server is not retried",
+			tool:      "fail",
+			settings:  model.ServerSettings{MaxRetries: &retries, RetryBackoff: "1ms"},
+			wantCalls: 1,
+		},
+		{
+			name:      "connection failure is retried",
+			tool:      "echo",
+			down:      true,
+			settings:  model.ServerSettings{MaxRetries: &retries, RetryBackoff: "1ms"},
+			wantCalls: 3,
+		},
+		{
+			name:      "timeout is not retried by default",
+			tool:      "slow",
+			settings:  model.ServerSettings{CallTimeout: "200ms", MaxRetries: &retries, RetryBackoff: "1ms"},
+			wantCalls: 1,
+		},
+		{
+			name: "timeout is retried if the server opted in",
+			tool: "slow",
+			settings: model.ServerSettings{
+				CallTimeout: "200ms", MaxRetries: &retries, RetryBackoff: "1ms", RetryOnTimeout: &yes,
+			},
+			wantCalls: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestService(t)
+			upstream := newTestUpstream(t)
+			upstream.register(t, m, "up", &tt.settings)
+			upstream.requests.Store(0)
+			upstream.down.Store(tt.down)
+
+			if _, err := m.InvokeTool(context.Background(), "up__"+tt.tool, nil); err == nil {
+				t.Fatal("InvokeTool() error = nil, want error")
+			}
+			got := upstream.toolCalls[tt.tool].Load()
+			if tt.down {
+				got = upstream.requests.Load()
+			}
+			if got != tt.wantCalls {
+				t.Errorf("upstream was called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestToolCallOutcomeWhenCircuitOpensWhileRetrying(t *testing.T) {
+	m := newTestService(t)
+	if err := m.SetBreakerConfig(BreakerConfig{
+		FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, OpenDuration: time.Minute,
+	}); err != nil {
+		t.Fatalf("failed to configure circuit breakers: %v", err)
+	}
+	var outcomes []string
+	m.AddToolCallCallback(func(c *ToolCall) { outcomes = append(outcomes, c.Outcome) })
+
+	retries := 5
+	upstream := newTestUpstream(t)
+	upstream.register(t, m, "up", &model.ServerSettings{MaxRetries: &retries, RetryBackoff: "1ms"})
+	upstream.requests.Store(0)
+	upstream.down.Store(true)
+
+	// the upstream was called before the circuit opened, so the call failed rather than being rejected
+	_, err := m.InvokeTool(context.Background(), "up__echo", nil)
+	var circuitErr *CircuitOpenError
+	if err == nil || errors.As(err, &circuitErr) {
+		t.Fatalf("InvokeTool() error = %v, want the error of the last attempt", err)
+	}
+	if got := upstream.requests.Load(); got != 2 {
+		t.Errorf("upstream was called %d times, want 2", got)
+	}
+
+	// the next call doesn't reach the upstream at all
+	if _, err := m.InvokeTool(context.Background(), "up__echo", nil); !errors.As(err, &circuitErr) {
+		t.Fatalf("InvokeTool() error = %v, want CircuitOpenError", err)
+	}
+
+	want := []string{metrics.OutcomeError, metrics.OutcomeRejected}
+	if len(outcomes) != len(want) || outcomes[0] != want[0] || outcomes[1] != want[1] {
+		t.Errorf("outcomes = %v, want %v", outcomes, want)
+	}
+}
+
+func TestServerErrorsDontOpenCircuit(t *testing.T) {
+	m := newTestService(t)
+	if err := m.SetBreakerConfig(BreakerConfig{
+		FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, OpenDuration: time.Minute,
+	}); err != nil {
+		t.Fatalf("failed to configure circuit breakers: %v", err)
+	}
+	upstream := newTestUpstream(t)
+	upstream.register(t, m, "up", nil)
+
+	// eg- a client that keeps sending invalid arguments must not make the server unavailable to others
+	for range 5 {
+		if _, err := m.InvokeTool(context.Background(), "up__fail", nil); err == nil {
+			t.Fatal("InvokeTool() error = nil, want error")
+		}
+	}
+	if got := upstream.toolCalls["fail"].Load(); got != 5 {
+		t.Errorf("upstream was called %d times, want 5", got)
+	}
+	if got := m.breakers.state("up"); got != types.CircuitClosed {
+		t.Errorf("circuit is %s after server errors, want %s", got, types.CircuitClosed)
+	}
+	if _, err := m.InvokeTool(context.Background(), "up__echo", map[string]any{"text": "hi"}); err != nil {
+		t.Errorf("InvokeTool() error = %v, want the server to stay available", err)
+	}
+}
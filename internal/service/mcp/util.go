@@ -1,12 +1,12 @@
 package mcp
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/url"
 	"os"
@@ -18,17 +18,31 @@ import (
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/client/transport"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
 	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
 	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // serverInitRequestTimeout is the timeout (in seconds) for the initialization request to the MCP server
 const serverInitRequestTimeout = 10
 
+// stdioServerMaxStderrLineSize is the maximum size (in bytes) of a single line of stderr output
+// captured from a stdio MCP server.
+const stdioServerMaxStderrLineSize = 1024 * 1024
+
 // serverToolNameSep is the separator used to combine server name and tool name.
 // This combination produces the canonical name that uniquely identifies a tool across MCPJungle.
 const serverToolNameSep = "__"
 
+// anonymousCaller is the name used to identify the caller of a tool when no authenticated MCP client is present,
+// eg- in development mode or when a tool is invoked via the HTTP API.
+const anonymousCaller = "anonymous"
+
 // Only allow letters, numbers, hyphens, and underscores
 var validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
 
@@ -57,6 +71,30 @@ func validateServerName(name string) error {
 	return nil
 }
 
+// invalidServerNameChars matches runs of characters that are not allowed in server names
+var invalidServerNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// consecutiveUnderscores matches runs of underscores that would be mistaken for the server-tool separator
+var consecutiveUnderscores = regexp.MustCompile(`_{2,}`)
+
+// SanitizeServerName converts an arbitrary name, eg- from another MCP client's configuration, into a valid server name.
+// Disallowed characters are replaced with hyphens (eg- "@acme/files server" becomes "acme-files-server"),
+// consecutive underscores are collapsed into one and leading & trailing separators are removed.
+// It returns an error if no valid name can be derived.
+func SanitizeServerName(name string) (string, error) {
+	s := invalidServerNameChars.ReplaceAllString(name, "-")
+	s = consecutiveUnderscores.ReplaceAllString(s, "_")
+	s = strings.TrimLeft(s, "-")
+	s = strings.TrimRight(s, "-_")
+	if s == "" {
+		return "", fmt.Errorf("invalid server name: no valid server name can be derived from '%s'", name)
+	}
+	if err := validateServerName(s); err != nil {
+		return "", err
+	}
+	return s, nil
+}
+
 // mergeServerToolNames combines the server name and tool name into a single tool name unique across the registry.
 func mergeServerToolNames(s, t string) string {
 	return s + serverToolNameSep + t
@@ -67,6 +105,21 @@ func splitServerToolName(name string) (string, string, bool) {
 	return strings.Cut(name, serverToolNameSep)
 }
 
+// ServerOfTool returns the name of the MCP server that provides the tool with the given unique name.
+func ServerOfTool(name string) string {
+	s, _, _ := splitServerToolName(name)
+	return s
+}
+
+// callerName returns the name of the MCP client that made the current request.
+// If the request was not made by an authenticated MCP client, anonymousCaller is returned.
+func callerName(ctx context.Context) string {
+	if c, ok := ctx.Value("client").(*model.McpClient); ok && c != nil {
+		return c.Name
+	}
+	return anonymousCaller
+}
+
 // isLoopbackURL returns true if rawURL resolves to a loopback address.
 // It assumes that rawURL is a valid URL.
 func isLoopbackURL(rawURL string) bool {
@@ -126,10 +179,14 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 		opts = append(opts, o)
 	}
 
-	c, err := client.NewStreamableHttpClient(conf.URL, opts...)
+	// propagate the trace context of every request to the upstream server as W3C trace context headers
+	opts = append(opts, transport.WithHTTPHeaderFunc(tracing.InjectTraceContext))
+
+	t, err := transport.NewStreamableHTTP(conf.URL, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create streamable HTTP client for MCP server: %w", err)
 	}
+	c := client.NewClient(&serverErrorTransport{Interface: t})
 
 	initRequest := mcp.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
@@ -142,7 +199,9 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
 	defer cancel()
 
+	start := time.Now()
 	_, err = c.Initialize(initCtx, initRequest)
+	metrics.ObserveUpstreamSessionInit(s.Name, string(s.Transport), err, time.Since(start))
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("initialization request to MCP server timed out after %d seconds", serverInitRequestTimeout)
@@ -154,40 +213,36 @@ func createHTTPMcpServerConn(ctx context.Context, s *model.McpServer) (*client.C
 				conf.URL,
 			)
 		}
-		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", unwrapServerError(err))
 	}
 
 	return c, nil
 }
 
-// captureStdioServerStderr captures the stderr output of a stdio MCP server in the background
-// and writes it to mcpjungle server logs.
+// captureStdioServerStderr captures the stderr output of a stdio MCP server in the background.
+// Every line is written to mcpjungle server logs and retained in the server's log buffer.
 // This is useful for troubleshooting and visibility into the stdio server's behaviour.
-func captureStdioServerStderr(name string, c *client.Client) {
-	stdioTransport := c.GetTransport().(*transport.Stdio)
+func captureStdioServerStderr(name string, stdioTransport *transport.Stdio, logs *serverLogs) {
+	logger := slog.With(logging.KeyServer, name)
 
 	go func() {
-		buf := make([]byte, 4096) // 4KB buffer for reading stderr
-		for {
-			n, err := stdioTransport.Stderr().Read(buf)
-			if err != nil {
-				if err == io.EOF || errors.Is(err, os.ErrClosed) {
-					log.Printf("['%s' MCP Server] [DEBUG] server process has exited gracefully", name)
-				} else {
-					log.Printf("['%s' MCP STDERR] Error reading stderr: %v", name, err)
-				}
-				log.Printf("['%s' MCP server] [DEBUG] exiting goroutine", name)
-				break
-			}
-			if n > 0 {
-				log.Printf("['%s' MCP STDERR] %s", name, string(buf[:n]))
-			}
+		scanner := bufio.NewScanner(stdioTransport.Stderr())
+		scanner.Buffer(make([]byte, 0, 4096), stdioServerMaxStderrLineSize)
+		for scanner.Scan() {
+			line := scanner.Text()
+			logs.append(name, line)
+			logger.Info("mcp server stderr", "line", line)
+		}
+		if err := scanner.Err(); err != nil && !errors.Is(err, os.ErrClosed) {
+			logger.Error("failed to read mcp server stderr", logging.KeyError, err)
 		}
+		logger.Debug("mcp server process has exited, stopped capturing stderr")
 	}()
 }
 
 // runStdioServer runs a stdio MCP server and returns the client.
-func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// Its stderr output is captured in logs.
+func runStdioServer(ctx context.Context, s *model.McpServer, logs *serverLogs) (*client.Client, error) {
 	conf, err := s.GetStdioConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get stdio config for MCP server %s: %w", s.Name, err)
@@ -201,14 +256,16 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 		}
 	}
 
-	c, err := client.NewStdioMCPClient(conf.Command, envVars, conf.Args...)
-	if err != nil {
+	stdioTransport := transport.NewStdio(conf.Command, envVars, conf.Args...)
+	if err := stdioTransport.Start(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to create stdio client for MCP server: %w", err)
 	}
+	c := client.NewClient(&serverErrorTransport{Interface: stdioTransport})
+	metrics.IncStdioProcessStarts(s.Name)
 
 	// currently, we only capture the stderr output in the mcpjungle server logs.
 	// TODO: Propagate the stderr output to the client as well to provide them quicker feedback on errors.
-	captureStdioServerStderr(s.Name, c)
+	captureStdioServerStderr(s.Name, stdioTransport, logs)
 
 	initRequest := mcp.InitializeRequest{}
 	initRequest.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
@@ -221,25 +278,40 @@ func runStdioServer(ctx context.Context, s *model.McpServer) (*client.Client, er
 	initCtx, cancel := context.WithTimeout(ctx, serverInitRequestTimeout*time.Second)
 	defer cancel()
 
+	start := time.Now()
 	_, err = c.Initialize(initCtx, initRequest)
+	metrics.ObserveUpstreamSessionInit(s.Name, string(s.Transport), err, time.Since(start))
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf(
 				"initialization request to MCP server timed out after %d seconds,"+
-					" check mcpungle server logs for any errors from this MCP server",
-				serverInitRequestTimeout,
+					" check the logs of this MCP server for any errors (mcpjungle logs %s)",
+				serverInitRequestTimeout, s.Name,
 			)
 		}
-		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", err)
+		return nil, fmt.Errorf("failed to initialize connection with MCP server: %w", unwrapServerError(err))
 	}
 
 	return c, nil
 }
 
-func newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+// newMcpServerSession creates a new, initialized session with the given upstream MCP server.
+func (m *MCPService) newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Client, error) {
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		"mcp.upstream.session",
+		trace.WithAttributes(
+			attribute.String("mcp.server.name", s.Name),
+			attribute.String("mcp.server.transport", string(s.Transport)),
+		),
+	)
+	defer span.End()
+
 	if s.Transport == types.TransportStreamableHTTP {
 		mcpClient, err := createHTTPMcpServerConn(ctx, s)
 		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to connect to MCP server")
 			return nil, fmt.Errorf(
 				"failed to create connection to streamable http MCP server %s: %w", s.Name, err,
 			)
@@ -251,9 +323,102 @@ func newMcpServerSession(ctx context.Context, s *model.McpServer) (*client.Clien
 	// This is especially a problem for the MCP proxy server, which is expected to call tools frequently.
 	// This causes a serious performance hit, but is easy to implement so it is used for now.
 	// TODO: Think of a better solution, ie, re-use connections to stdio MCP servers.
-	mcpClient, err := runStdioServer(ctx, s)
+	mcpClient, err := runStdioServer(ctx, s, m.serverLogs)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to run MCP server")
 		return nil, fmt.Errorf("failed to run stdio MCP server %s: %w", s.Name, err)
 	}
 	return mcpClient, nil
 }
+
+// callTool calls a tool on the upstream MCP server over an existing session.
+func callTool(
+	ctx context.Context, s *model.McpServer, c *client.Client, request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	ctx, span := tracing.Tracer().Start(
+		ctx,
+		"mcp.upstream.call_tool",
+		trace.WithAttributes(
+			attribute.String("mcp.server.name", s.Name),
+			attribute.String("mcp.tool.name", request.Params.Name),
+		),
+	)
+	defer span.End()
+
+	if s.Transport == types.TransportStdio {
+		// stdio has no headers, so the trace context is propagated via the request's _meta field instead
+		request.Params.Meta = withTraceContext(ctx, request.Params.Meta)
+	}
+
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		err = unwrapServerError(err)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "tool call failed")
+		return nil, err
+	}
+	if result.IsError {
+		span.SetStatus(codes.Error, "tool returned an error")
+	}
+	return result, nil
+}
+
+// withTraceContext returns a copy of the request metadata with the W3C trace context of ctx added to it.
+// The original metadata is not modified because it may be shared with the caller.
+func withTraceContext(ctx context.Context, meta *mcp.Meta) *mcp.Meta {
+	tc := tracing.InjectTraceContext(ctx)
+	if len(tc) == 0 {
+		return meta
+	}
+
+	m := &mcp.Meta{AdditionalFields: make(map[string]any, len(tc))}
+	if meta != nil {
+		m.ProgressToken = meta.ProgressToken
+		for k, v := range meta.AdditionalFields {
+			m.AdditionalFields[k] = v
+		}
+	}
+	for k, v := range tc {
+		m.AdditionalFields[k] = v
+	}
+	return m
+}
+
+// serverError is the error response of an upstream MCP server to a JSON-RPC request,
+// ie, the server received the request and refused or failed to handle it.
+type serverError struct {
+	Code    int
+	Message string
+}
+
+func (e *serverError) Error() string {
+	return e.Message
+}
+
+// serverErrorTransport returns the JSON-RPC error responses of the upstream MCP server as *serverError.
+// The mcp-go client turns error responses into plain errors that can't be told apart from its own failures,
+// eg- a closed transport, but it wraps the errors returned by the transport, so they can be recognized.
+type serverErrorTransport struct {
+	transport.Interface
+}
+
+func (t *serverErrorTransport) SendRequest(
+	ctx context.Context, request transport.JSONRPCRequest,
+) (*transport.JSONRPCResponse, error) {
+	resp, err := t.Interface.SendRequest(ctx, request)
+	if err == nil && resp.Error != nil {
+		return nil, &serverError{Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+	return resp, err
+}
+
+// unwrapServerError returns the error response of the upstream MCP server if err contains one.
+// This drops the "transport error" prefix that the mcp-go client adds to it, which would be misleading.
+func unwrapServerError(err error) error {
+	var se *serverError
+	if errors.As(err, &se) {
+		return se
+	}
+	return err
+}
@@ -1,8 +1,14 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 func TestValidateServerName(t *testing.T) {
@@ -35,6 +41,40 @@ func TestValidateServerName(t *testing.T) {
 	}
 }
 
+func TestSanitizeServerName(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"github", "github", false},
+		{"my server", "my-server", false},
+		{"@acme/files server", "acme-files-server", false},
+		{"api.example.com", "api-example-com", false},
+		{"aws__ec2", "aws_ec2", false},
+		{"server_", "server", false},
+		{"_server", "_server", false},
+		{"@@@", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := SanitizeServerName(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SanitizeServerName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("SanitizeServerName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if err == nil {
+				if err := validateServerName(got); err != nil {
+					t.Errorf("SanitizeServerName(%q) returned invalid name %q: %v", tt.input, got, err)
+				}
+			}
+		})
+	}
+}
+
 func TestMergeServerToolNames(t *testing.T) {
 	tests := []struct {
 		server string
@@ -128,4 +168,42 @@ func TestIsLoopbackURL(t *testing.T) {
 	}
 }
 
+func TestWithTraceContext(t *testing.T) {
+	if _, err := tracing.Init(context.Background(), tracing.ExporterNone); err != nil {
+		t.Fatalf("failed to initialize tracing: %v", err)
+	}
+
+	t.Run("no active span", func(t *testing.T) {
+		meta := &mcp.Meta{AdditionalFields: map[string]any{"foo": "bar"}}
+		got := withTraceContext(context.Background(), meta)
+		if got != meta {
+			t.Errorf("withTraceContext() without trace context should return the original meta")
+		}
+	})
+
+	t.Run("active span", func(t *testing.T) {
+		tp := sdktrace.NewTracerProvider()
+		defer func() { _ = tp.Shutdown(context.Background()) }()
+		ctx, span := tp.Tracer("test").Start(context.Background(), "test")
+		defer span.End()
+
+		meta := &mcp.Meta{ProgressToken: "token", AdditionalFields: map[string]any{"foo": "bar"}}
+		got := withTraceContext(ctx, meta)
+
+		traceparent, ok := got.AdditionalFields["traceparent"].(string)
+		if !ok {
+			t.Fatalf("withTraceContext() did not add traceparent, got %v", got.AdditionalFields)
+		}
+		if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+			t.Errorf("traceparent %q does not contain trace ID %s", traceparent, span.SpanContext().TraceID())
+		}
+		if got.AdditionalFields["foo"] != "bar" || got.ProgressToken != "token" {
+			t.Errorf("withTraceContext() did not preserve existing meta fields, got %+v", got)
+		}
+		if _, ok := meta.AdditionalFields["traceparent"]; ok {
+			t.Errorf("withTraceContext() must not modify the original meta")
+		}
+	})
+}
+
 // todo: add tests for convertToolModelToMcpObject()
@@ -42,6 +42,22 @@ func (m *McpClientService) CreateClient(client model.McpClient) (*model.McpClien
 	return &client, nil
 }
 
+// UpdateClient changes the description and allow list of an existing MCP client.
+// The client keeps its access token.
+func (m *McpClientService) UpdateClient(client model.McpClient) error {
+	var cur model.McpClient
+	if err := m.db.Where("name = ?", client.Name).First(&cur).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("client not found")
+		}
+		return err
+	}
+	return m.db.Model(&cur).Select("Description", "AllowList").Updates(&model.McpClient{
+		Description: client.Description,
+		AllowList:   client.AllowList,
+	}).Error
+}
+
 // GetClientByToken retrieves an MCP client by its access token from the database.
 // It returns an error if no such client is found.
 func (m *McpClientService) GetClientByToken(token string) (*model.McpClient, error) {
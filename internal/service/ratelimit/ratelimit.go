@@ -0,0 +1,369 @@
+// Package ratelimit provides rate limiting and quota enforcement for tool calls made through the MCP proxy.
+package ratelimit
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrRateLimitNotFound is returned when a rate limit does not exist.
+var ErrRateLimitNotFound = errors.New("rate limit not found")
+
+// quotaPeriod is a period of time over which calls are counted against a quota.
+type quotaPeriod struct {
+	limit string
+	// quota returns the quota of the given rate limit for this period
+	quota func(r *model.RateLimit) int64
+	// key identifies the period that t falls in
+	key func(t time.Time) string
+	// end returns the start of the period following the one that t falls in
+	end func(t time.Time) time.Time
+}
+
+// quotaPeriods are all the periods quotas can be configured for.
+// All periods are in UTC.
+var quotaPeriods = []quotaPeriod{
+	{
+		limit: mcp.LimitDailyQuota,
+		quota: func(r *model.RateLimit) int64 { return r.DailyQuota },
+		key:   func(t time.Time) string { return t.Format("2006-01-02") },
+		end: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+		},
+	},
+	{
+		limit: mcp.LimitMonthlyQuota,
+		quota: func(r *model.RateLimit) int64 { return r.MonthlyQuota },
+		key:   func(t time.Time) string { return t.Format("2006-01") },
+		end: func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+		},
+	},
+}
+
+// bucketKey identifies the token bucket of a rate limit.
+type bucketKey struct {
+	scope  types.RateLimitScope
+	target string
+}
+
+// tokenBucket holds the tokens available for calls under a single rate limit.
+type tokenBucket struct {
+	// mu serializes the calls made under this rate limit, calls under other limits don't wait for it
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// RateLimitService manages rate limits and enforces them on tool calls.
+// Rate limits are enforced with in-memory token buckets, while quota usage is persisted in the database.
+// The rate limits themselves are kept in memory, so that calls only need the database to count their quota usage.
+type RateLimitService struct {
+	db *gorm.DB
+
+	// limits contains all rate limits by their scope and target
+	limits  map[bucketKey]model.RateLimit
+	buckets map[bucketKey]*tokenBucket
+	// mu guards the limits and buckets maps. It is never held while the database is accessed.
+	mu sync.RWMutex
+
+	now func() time.Time
+}
+
+// NewRateLimitService creates a RateLimitService and loads all rate limits from the database.
+func NewRateLimitService(db *gorm.DB) (*RateLimitService, error) {
+	s := &RateLimitService{
+		db:      db,
+		limits:  make(map[bucketKey]model.RateLimit),
+		buckets: make(map[bucketKey]*tokenBucket),
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload reads all rate limits from the database again, eg- after another mcpjungle server sharing it changed them.
+// The token bucket of a rate limit is kept unless its rate or burst has changed.
+func (s *RateLimitService) Reload() error {
+	limits, err := s.ListRateLimits()
+	if err != nil {
+		return fmt.Errorf("failed to load rate limits: %w", err)
+	}
+	byKey := make(map[bucketKey]model.RateLimit, len(limits))
+	for _, r := range limits {
+		byKey[bucketKey{r.Scope, r.Target}] = r
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.buckets {
+		old, cur := s.limits[key], byKey[key]
+		if old.RequestsPerMinute != cur.RequestsPerMinute || old.Burst != cur.Burst {
+			delete(s.buckets, key)
+		}
+	}
+	s.limits = byKey
+	return nil
+}
+
+// SetRateLimit creates a rate limit or replaces the existing one for the same scope and target.
+func (s *RateLimitService) SetRateLimit(r *model.RateLimit) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	if r.RequestsPerMinute > 0 && r.Burst == 0 {
+		r.Burst = r.RequestsPerMinute
+	}
+
+	var existing model.RateLimit
+	err := s.db.Where("scope = ? AND target = ?", r.Scope, r.Target).First(&existing).Error
+	switch {
+	case err == nil:
+		r.ID = existing.ID
+		r.CreatedAt = existing.CreatedAt
+		if err := s.db.Save(r).Error; err != nil {
+			return fmt.Errorf("failed to update rate limit: %w", err)
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := s.db.Create(r).Error; err != nil {
+			return fmt.Errorf("failed to create rate limit: %w", err)
+		}
+	default:
+		return fmt.Errorf("failed to get rate limit: %w", err)
+	}
+
+	// start over with a full bucket under the new limit
+	key := bucketKey{r.Scope, r.Target}
+	s.mu.Lock()
+	s.limits[key] = *r
+	delete(s.buckets, key)
+	s.mu.Unlock()
+
+	s.recordChange()
+	return nil
+}
+
+// ListRateLimits returns all rate limits.
+func (s *RateLimitService) ListRateLimits() ([]model.RateLimit, error) {
+	var limits []model.RateLimit
+	if err := s.db.Order("scope, target").Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// DeleteRateLimit removes the rate limit of the given scope and target.
+// The recorded quota usage is kept, so re-creating the limit within the same period does not reset it.
+func (s *RateLimitService) DeleteRateLimit(scope types.RateLimitScope, target string) error {
+	result := s.db.Unscoped().Where("scope = ? AND target = ?", scope, target).Delete(&model.RateLimit{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete rate limit: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrRateLimitNotFound
+	}
+
+	key := bucketKey{scope, target}
+	s.mu.Lock()
+	delete(s.limits, key)
+	delete(s.buckets, key)
+	s.mu.Unlock()
+
+	s.recordChange()
+	return nil
+}
+
+// recordChange notifies the other mcpjungle servers that share the database that the rate limits have changed.
+// Failing to do so doesn't fail the change, which has already been made.
+func (s *RateLimitService) recordChange() {
+	if err := changes.Record(s.db, model.RegistryScopeRateLimits); err != nil {
+		slog.Error("other servers may not see the change to the rate limits until they restart", logging.KeyError, err)
+	}
+}
+
+// GetUsage returns the number of calls made under the given rate limit in the current day and month.
+func (s *RateLimitService) GetUsage(r *model.RateLimit) (daily, monthly int64, err error) {
+	now := s.now()
+	if daily, err = s.usage(r, quotaPeriods[0], now); err != nil {
+		return 0, 0, err
+	}
+	if monthly, err = s.usage(r, quotaPeriods[1], now); err != nil {
+		return 0, 0, err
+	}
+	return daily, monthly, nil
+}
+
+// AllowToolCall implements mcp.CallLimiter.
+// The call is checked against the rate limits of the client, the server and the tool.
+// It is only counted against them if none of them is exceeded.
+func (s *RateLimitService) AllowToolCall(client, server, tool string) error {
+	limits := s.matchingRateLimits(client, server, tool)
+	if len(limits) == 0 {
+		return nil
+	}
+	now := s.now()
+
+	taken, err := s.takeTokens(limits, now)
+	if err != nil {
+		return err
+	}
+	if err := s.countUsage(limits, now); err != nil {
+		// the call is rejected, so it doesn't use up any rate
+		s.returnTokens(limits, taken)
+		return err
+	}
+	return nil
+}
+
+// matchingRateLimits returns the rate limits that apply to a call to the given tool by the given client.
+// Targets are never empty, so calls made without an MCP client (development mode) never match a client limit.
+// The limits are always returned in the same order, so that their quota usage rows are always locked in the same order.
+func (s *RateLimitService) matchingRateLimits(client, server, tool string) []model.RateLimit {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var limits []model.RateLimit
+	for _, key := range []bucketKey{
+		{types.RateLimitScopeClient, client},
+		{types.RateLimitScopeServer, server},
+		{types.RateLimitScopeTool, tool},
+	} {
+		if r, ok := s.limits[key]; ok {
+			limits = append(limits, r)
+		}
+	}
+	return limits
+}
+
+// takeTokens takes a token from the bucket of every given limit that has a rate.
+// If any bucket is empty, no token is taken from any of them and a *mcp.RateLimitError is returned.
+// It returns the buckets tokens were taken from.
+func (s *RateLimitService) takeTokens(limits []model.RateLimit, now time.Time) ([]*tokenBucket, error) {
+	taken := make([]*tokenBucket, 0, len(limits))
+	for i := range limits {
+		r := &limits[i]
+		if r.RequestsPerMinute == 0 {
+			taken = append(taken, nil)
+			continue
+		}
+		b := s.bucket(r, now)
+		b.mu.Lock()
+		perSecond := float64(r.RequestsPerMinute) / 60
+		b.tokens = min(float64(r.Burst), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+		b.last = now
+		if b.tokens < 1 {
+			retryAfter := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+			b.mu.Unlock()
+			s.returnTokens(limits, taken)
+			return nil, &mcp.RateLimitError{Scope: r.Scope, Target: r.Target, Limit: mcp.LimitRate, RetryAfter: retryAfter}
+		}
+		b.tokens--
+		b.mu.Unlock()
+		taken = append(taken, b)
+	}
+	return taken, nil
+}
+
+// returnTokens puts back the tokens taken by takeTokens.
+func (s *RateLimitService) returnTokens(limits []model.RateLimit, taken []*tokenBucket) {
+	for i, b := range taken {
+		if b == nil {
+			continue
+		}
+		b.mu.Lock()
+		b.tokens = min(float64(limits[i].Burst), b.tokens+1)
+		b.mu.Unlock()
+	}
+}
+
+// bucket returns the token bucket of the given rate limit, creating a full one if it doesn't exist yet.
+func (s *RateLimitService) bucket(r *model.RateLimit, now time.Time) *tokenBucket {
+	key := bucketKey{r.Scope, r.Target}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(r.Burst), last: now}
+		s.buckets[key] = b
+	}
+	return b
+}
+
+// countUsage counts a call against the given rate limits in all periods.
+// Usage is counted in all periods, even those without a quota, so that it can be reported.
+// If any quota is exhausted, the call isn't counted at all and a *mcp.RateLimitError is returned.
+func (s *RateLimitService) countUsage(limits []model.RateLimit, now time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range limits {
+			r := &limits[i]
+			for _, p := range quotaPeriods {
+				counted, err := incrementUsage(tx, r, p, now)
+				if err != nil {
+					return err
+				}
+				if !counted {
+					return &mcp.RateLimitError{Scope: r.Scope, Target: r.Target, Limit: p.limit, RetryAfter: p.end(now).Sub(now)}
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// usage returns the number of calls made under the given rate limit in the period that now falls in.
+func (s *RateLimitService) usage(r *model.RateLimit, p quotaPeriod, now time.Time) (int64, error) {
+	var u model.QuotaUsage
+	err := s.db.Where("scope = ? AND target = ? AND period = ?", r.Scope, r.Target, p.key(now)).First(&u).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get quota usage: %w", err)
+	}
+	return u.Calls, nil
+}
+
+// incrementUsage counts a call against the given rate limit in the period that now falls in,
+// unless the period's quota is exhausted. It returns false if the quota is exhausted.
+// The check and the increment are a single statement, so that concurrent calls, also on other servers,
+// can never exceed the quota.
+func incrementUsage(tx *gorm.DB, r *model.RateLimit, p quotaPeriod, now time.Time) (bool, error) {
+	quota := p.quota(r)
+	// the first call in a period inserts its row, which may race with the same call on another server
+	for range 2 {
+		q := tx.Model(&model.QuotaUsage{}).Where("scope = ? AND target = ? AND period = ?", r.Scope, r.Target, p.key(now))
+		if quota > 0 {
+			q = q.Where("calls < ?", quota)
+		}
+		result := q.Updates(map[string]any{"calls": gorm.Expr("calls + 1"), "updated_at": now})
+		if result.Error != nil {
+			return false, fmt.Errorf("failed to record quota usage: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+
+		// either no call has been made in this period yet or the quota is exhausted
+		u := model.QuotaUsage{Scope: r.Scope, Target: r.Target, Period: p.key(now), Calls: 1, UpdatedAt: now}
+		result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&u)
+		if result.Error != nil {
+			return false, fmt.Errorf("failed to record quota usage: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
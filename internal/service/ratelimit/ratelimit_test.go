@@ -0,0 +1,273 @@
+package ratelimit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func newTestService(t *testing.T) (*RateLimitService, *time.Time) {
+	t.Helper()
+	db := dbtest.New(t)
+	s, err := NewRateLimitService(db)
+	if err != nil {
+		t.Fatalf("NewRateLimitService() error = %v", err)
+	}
+	now := time.Date(2025, 1, 31, 23, 59, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	return s, &now
+}
+
+// wantRateLimitError fails the test unless err is a rate limit error for the given limit.
+func wantRateLimitError(t *testing.T, err error, limit string) *mcp.RateLimitError {
+	t.Helper()
+	var rateLimitErr *mcp.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("AllowToolCall() error = %v, want a rate limit error", err)
+	}
+	if rateLimitErr.Limit != limit {
+		t.Fatalf("AllowToolCall() exceeded limit = %s, want %s", rateLimitErr.Limit, limit)
+	}
+	return rateLimitErr
+}
+
+func TestTokenBucket(t *testing.T) {
+	s, now := newTestService(t)
+	err := s.SetRateLimit(&model.RateLimit{
+		Scope: types.RateLimitScopeServer, Target: "search", RequestsPerMinute: 60, Burst: 2,
+	})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.AllowToolCall("", "search", "search__query"); err != nil {
+			t.Fatalf("AllowToolCall() #%d error = %v", i+1, err)
+		}
+	}
+	e := wantRateLimitError(t, s.AllowToolCall("", "search", "search__query"), mcp.LimitRate)
+	if e.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %s, want 1s", e.RetryAfter)
+	}
+
+	// other servers are not affected
+	if err := s.AllowToolCall("", "other", "other__query"); err != nil {
+		t.Fatalf("AllowToolCall() on another server error = %v", err)
+	}
+
+	*now = now.Add(time.Second)
+	if err := s.AllowToolCall("", "search", "search__query"); err != nil {
+		t.Fatalf("AllowToolCall() after refill error = %v", err)
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	s, now := newTestService(t)
+	err := s.SetRateLimit(&model.RateLimit{Scope: types.RateLimitScopeClient, Target: "agent", DailyQuota: 2})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	err = s.SetRateLimit(&model.RateLimit{Scope: types.RateLimitScopeTool, Target: "search__query", MonthlyQuota: 3})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.AllowToolCall("agent", "search", "search__query"); err != nil {
+			t.Fatalf("AllowToolCall() #%d error = %v", i+1, err)
+		}
+	}
+	e := wantRateLimitError(t, s.AllowToolCall("agent", "search", "search__query"), mcp.LimitDailyQuota)
+	if e.RetryAfter != time.Minute {
+		t.Errorf("RetryAfter = %s, want 1m", e.RetryAfter)
+	}
+
+	// the rejected call is not counted against the tool's quota
+	if err := s.AllowToolCall("other-agent", "search", "search__query"); err != nil {
+		t.Fatalf("AllowToolCall() by another client error = %v", err)
+	}
+	wantRateLimitError(t, s.AllowToolCall("other-agent", "search", "search__query"), mcp.LimitMonthlyQuota)
+
+	r := &model.RateLimit{Scope: types.RateLimitScopeClient, Target: "agent"}
+	daily, monthly, err := s.GetUsage(r)
+	if err != nil {
+		t.Fatalf("GetUsage() error = %v", err)
+	}
+	if daily != 2 || monthly != 2 {
+		t.Errorf("GetUsage() = %d, %d, want 2, 2", daily, monthly)
+	}
+
+	// the next day starts a new month, so both quotas are reset
+	*now = now.Add(time.Minute)
+	if err := s.AllowToolCall("agent", "search", "search__query"); err != nil {
+		t.Fatalf("AllowToolCall() in the next period error = %v", err)
+	}
+}
+
+func TestQuotaUsageIsPersisted(t *testing.T) {
+	s, now := newTestService(t)
+	err := s.SetRateLimit(&model.RateLimit{Scope: types.RateLimitScopeServer, Target: "search", DailyQuota: 1})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	if err := s.AllowToolCall("", "search", "search__query"); err != nil {
+		t.Fatalf("AllowToolCall() error = %v", err)
+	}
+
+	// a new service on the same database simulates a restart
+	restarted, err := NewRateLimitService(s.db)
+	if err != nil {
+		t.Fatalf("NewRateLimitService() error = %v", err)
+	}
+	restarted.now = func() time.Time { return *now }
+	wantRateLimitError(t, restarted.AllowToolCall("", "search", "search__query"), mcp.LimitDailyQuota)
+}
+
+func TestSetRateLimitValidation(t *testing.T) {
+	s, _ := newTestService(t)
+	invalid := []*model.RateLimit{
+		{Scope: "group", Target: "g", DailyQuota: 1},
+		{Scope: types.RateLimitScopeClient, Target: "", DailyQuota: 1},
+		{Scope: types.RateLimitScopeClient, Target: "agent"},
+		{Scope: types.RateLimitScopeClient, Target: "agent", Burst: 5, DailyQuota: 1},
+		{Scope: types.RateLimitScopeClient, Target: "agent", RequestsPerMinute: -1},
+	}
+	for _, r := range invalid {
+		if err := s.SetRateLimit(r); err == nil {
+			t.Errorf("SetRateLimit(%+v) error = nil, want error", r)
+		}
+	}
+
+	r := &model.RateLimit{Scope: types.RateLimitScopeClient, Target: "agent", RequestsPerMinute: 30}
+	if err := s.SetRateLimit(r); err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	if r.Burst != 30 {
+		t.Errorf("Burst = %d, want it to default to requests per minute", r.Burst)
+	}
+
+	// setting a limit again replaces it
+	r = &model.RateLimit{Scope: types.RateLimitScopeClient, Target: "agent", DailyQuota: 10}
+	if err := s.SetRateLimit(r); err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	limits, err := s.ListRateLimits()
+	if err != nil {
+		t.Fatalf("ListRateLimits() error = %v", err)
+	}
+	if len(limits) != 1 || limits[0].RequestsPerMinute != 0 || limits[0].DailyQuota != 10 {
+		t.Errorf("ListRateLimits() = %+v, want only the replaced limit", limits)
+	}
+}
+
+func TestRateLimitChangesPropagateBetweenReplicas(t *testing.T) {
+	a, _ := newTestService(t)
+	watcher, err := changes.NewWatcher(a.db)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	b, err := NewRateLimitService(a.db)
+	if err != nil {
+		t.Fatalf("NewRateLimitService() error = %v", err)
+	}
+	watcher.OnChange(model.RegistryScopeRateLimits, b.Reload)
+
+	err = a.SetRateLimit(&model.RateLimit{Scope: types.RateLimitScopeTool, Target: "search__query", RequestsPerMinute: 1})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	// the other replica only enforces the limit once it has seen the change
+	for i := 0; i < 3; i++ {
+		if err := b.AllowToolCall("", "search", "search__query"); err != nil {
+			t.Fatalf("AllowToolCall() before the change was seen error = %v", err)
+		}
+	}
+	if err := watcher.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if err := b.AllowToolCall("", "search", "search__query"); err != nil {
+		t.Fatalf("AllowToolCall() error = %v", err)
+	}
+	wantRateLimitError(t, b.AllowToolCall("", "search", "search__query"), mcp.LimitRate)
+
+	if err := a.DeleteRateLimit(types.RateLimitScopeTool, "search__query"); err != nil {
+		t.Fatalf("DeleteRateLimit() error = %v", err)
+	}
+	if err := a.AllowToolCall("", "search", "search__query"); err != nil {
+		t.Errorf("AllowToolCall() after the limit was deleted error = %v", err)
+	}
+	if err := watcher.Poll(); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if err := b.AllowToolCall("", "search", "search__query"); err != nil {
+		t.Errorf("AllowToolCall() on the other replica after the limit was deleted error = %v", err)
+	}
+}
+
+func TestConcurrentCallsNeverExceedQuota(t *testing.T) {
+	a, _ := newTestService(t)
+	if a.db.Dialector.Name() == "sqlite" {
+		// an in-memory SQLite database can't be written to by several connections at once
+		sqlDB, err := a.db.DB()
+		if err != nil {
+			t.Fatalf("failed to get connection pool: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	// replicas sharing the database count against the same quota
+	b, err := NewRateLimitService(a.db)
+	if err != nil {
+		t.Fatalf("NewRateLimitService() error = %v", err)
+	}
+	b.now = a.now
+
+	const quota, calls = 10, 40
+	err = a.SetRateLimit(&model.RateLimit{Scope: types.RateLimitScopeServer, Target: "search", DailyQuota: quota})
+	if err != nil {
+		t.Fatalf("SetRateLimit() error = %v", err)
+	}
+	if err := b.Reload(); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	var allowed atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		s := a
+		if i%2 == 1 {
+			s = b
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.AllowToolCall("", "search", "search__query")
+			var rateLimitErr *mcp.RateLimitError
+			switch {
+			case err == nil:
+				allowed.Add(1)
+			case !errors.As(err, &rateLimitErr):
+				t.Errorf("AllowToolCall() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := allowed.Load(); got != quota {
+		t.Errorf("%d calls were allowed, want %d", got, quota)
+	}
+	daily, _, err := a.GetUsage(&model.RateLimit{Scope: types.RateLimitScopeServer, Target: "search"})
+	if err != nil {
+		t.Fatalf("GetUsage() error = %v", err)
+	}
+	if daily != quota {
+		t.Errorf("GetUsage() daily = %d, want %d", daily, quota)
+	}
+}
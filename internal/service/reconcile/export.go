@@ -0,0 +1,107 @@
+package reconcile
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ExportOptions control what is included in an exported manifest.
+type ExportOptions struct {
+	// OmitSecrets replaces the bearer tokens of servers and the values of their environment variables
+	// with types.KeepCurrentSecret
+	OmitSecrets bool
+	// ManageAccess includes MCP clients and users, which only exist in production mode
+	ManageAccess bool
+}
+
+// Export returns a manifest that declares the current state of the registry.
+// Applying it to an empty registry recreates the servers, tool states, tool groups, MCP clients and users.
+// Access tokens can't be exported, so MCP clients and users get new ones when the manifest is applied.
+func (s *ReconcileService) Export(opts ExportOptions) (*types.Manifest, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m := &types.Manifest{}
+
+	servers, err := s.mcpService.ListMcpServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
+	}
+	for i := range servers {
+		input, err := servers[i].ToInput()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read configuration of MCP server %s: %w", servers[i].Name, err)
+		}
+		if opts.OmitSecrets {
+			if input.BearerToken != "" {
+				input.BearerToken = types.KeepCurrentSecret
+			}
+			for k := range input.Env {
+				input.Env[k] = types.KeepCurrentSecret
+			}
+		}
+		m.Servers = append(m.Servers, types.ManifestServer(*input))
+
+		tools, err := s.mcpService.ListToolsByServer(servers[i].Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tools of MCP server %s: %w", servers[i].Name, err)
+		}
+		for _, t := range tools {
+			if !t.Enabled {
+				m.DisabledTools = append(m.DisabledTools, t.Name)
+			}
+		}
+	}
+	sort.Slice(m.Servers, func(i, j int) bool { return m.Servers[i].Name < m.Servers[j].Name })
+	sort.Strings(m.DisabledTools)
+
+	groups, err := s.toolGroupService.ListToolGroups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tool groups: %w", err)
+	}
+	for i := range groups {
+		tools, err := groups[i].GetTools()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tools of group %s: %w", groups[i].Name, err)
+		}
+		m.ToolGroups = append(m.ToolGroups, types.ToolGroup{
+			Name:          groups[i].Name,
+			Description:   groups[i].Description,
+			IncludedTools: tools,
+		})
+	}
+	sort.Slice(m.ToolGroups, func(i, j int) bool { return m.ToolGroups[i].Name < m.ToolGroups[j].Name })
+
+	if !opts.ManageAccess {
+		return m, nil
+	}
+
+	clients, err := s.mcpClientService.ListClients()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP clients: %w", err)
+	}
+	for _, c := range clients {
+		allowList, err := allowListOf(c)
+		if err != nil {
+			return nil, err
+		}
+		m.Clients = append(m.Clients, types.McpClient{Name: c.Name, Description: c.Description, AllowList: allowList})
+	}
+	sort.Slice(m.Clients, func(i, j int) bool { return m.Clients[i].Name < m.Clients[j].Name })
+
+	users, err := s.userService.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		// admin users can't be declared in a manifest
+		if u.Role == types.UserRoleAdmin {
+			continue
+		}
+		m.Users = append(m.Users, types.User{Username: u.Username, Role: string(u.Role)})
+	}
+	sort.Slice(m.Users, func(i, j int) bool { return m.Users[i].Username < m.Users[j].Username })
+	return m, nil
+}
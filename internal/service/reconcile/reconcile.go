@@ -0,0 +1,612 @@
+// Package reconcile makes the registry match a declarative manifest,
+// so that servers, tools, tool groups, MCP clients and users can be managed as code.
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+// ErrInvalidManifest is returned when a manifest can't be applied because it is malformed.
+var ErrInvalidManifest = errors.New("invalid manifest")
+
+// Options control how a manifest is applied.
+type Options struct {
+	// DryRun only computes the changes needed to make the registry match the manifest, without applying them
+	DryRun bool
+	// Prune deletes the servers, tool groups, MCP clients and users that are not declared in the manifest
+	Prune bool
+	// ManageAccess enables managing MCP clients and users, which only exist in production mode
+	ManageAccess bool
+}
+
+// ReconcileService computes and applies the changes needed to make the registry match a manifest.
+type ReconcileService struct {
+	mcpService       *mcp.MCPService
+	toolGroupService *toolgroup.ToolGroupService
+	mcpClientService *mcpclient.McpClientService
+	userService      *user.UserService
+
+	// mu ensures that a single manifest is applied at a time
+	mu sync.Mutex
+}
+
+func NewReconcileService(
+	mcpService *mcp.MCPService,
+	toolGroupService *toolgroup.ToolGroupService,
+	mcpClientService *mcpclient.McpClientService,
+	userService *user.UserService,
+) *ReconcileService {
+	return &ReconcileService{
+		mcpService:       mcpService,
+		toolGroupService: toolGroupService,
+		mcpClientService: mcpClientService,
+		userService:      userService,
+	}
+}
+
+// change is a single change to the registry along with the function that applies it.
+type change struct {
+	types.ReconcileChange
+	apply func(ctx context.Context, c *types.ReconcileChange) error
+}
+
+// plan accumulates the changes needed to make the registry match a manifest, in the order they must be applied.
+type plan struct {
+	changes []*change
+	// deletions are applied after all other changes,
+	// so that nothing still refers to a deleted object while the manifest is being applied
+	deletions []*change
+}
+
+func (p *plan) add(action types.ReconcileAction, kind types.ReconcileObjectKind, name, details string,
+	apply func(ctx context.Context, c *types.ReconcileChange) error,
+) {
+	c := &change{
+		ReconcileChange: types.ReconcileChange{Action: action, Kind: kind, Name: name, Details: details},
+		apply:           apply,
+	}
+	if action == types.ReconcileDelete {
+		p.deletions = append(p.deletions, c)
+	} else {
+		p.changes = append(p.changes, c)
+	}
+}
+
+// Reconcile makes the registry match the manifest and returns the changes that were made.
+// Changes are applied on a best-effort basis: if a change fails, its error is recorded in the result
+// and the remaining changes are still applied.
+// An error is only returned if the manifest is invalid or the current state of the registry can't be read.
+func (s *ReconcileService) Reconcile(
+	ctx context.Context, m *types.Manifest, opts Options,
+) (*types.ReconcileResult, error) {
+	if err := validate(m, opts); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := &plan{}
+	reregistered, err := s.planServers(p, m, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.planTools(p, m, reregistered); err != nil {
+		return nil, err
+	}
+	if err := s.planToolGroups(p, m, opts); err != nil {
+		return nil, err
+	}
+	if opts.ManageAccess {
+		if err := s.planClients(p, m, opts); err != nil {
+			return nil, err
+		}
+		if err := s.planUsers(p, m, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &types.ReconcileResult{DryRun: opts.DryRun, Changes: []types.ReconcileChange{}}
+	for _, c := range append(p.changes, p.deletions...) {
+		if !opts.DryRun {
+			if err := c.apply(ctx, &c.ReconcileChange); err != nil {
+				c.Error = err.Error()
+			}
+		}
+		result.Changes = append(result.Changes, c.ReconcileChange)
+	}
+	return result, nil
+}
+
+// validate checks that a manifest is well-formed before any change is planned.
+func validate(m *types.Manifest, opts Options) error {
+	servers := make(map[string]bool, len(m.Servers))
+	for i := range m.Servers {
+		name := m.Servers[i].Name
+		if name == "" {
+			return errors.New("every server must have a name")
+		}
+		if servers[name] {
+			return fmt.Errorf("server %s is declared more than once", name)
+		}
+		servers[name] = true
+		in := types.RegisterServerInput(m.Servers[i])
+		if _, err := model.NewMcpServer(&in); err != nil {
+			return fmt.Errorf("server %s: %w", name, err)
+		}
+	}
+
+	tools := make(map[string]bool, len(m.DisabledTools))
+	for _, t := range m.DisabledTools {
+		if tools[t] {
+			return fmt.Errorf("disabled tool %s is listed more than once", t)
+		}
+		tools[t] = true
+		if s := mcp.ServerOfTool(t); s == t || !servers[s] {
+			return fmt.Errorf("disabled tool %s does not belong to any server declared in the manifest", t)
+		}
+	}
+
+	groups := make(map[string]bool, len(m.ToolGroups))
+	for _, g := range m.ToolGroups {
+		if !toolgroup.ValidGroupName.MatchString(g.Name) {
+			return fmt.Errorf("invalid tool group name '%s'", g.Name)
+		}
+		if groups[g.Name] {
+			return fmt.Errorf("tool group %s is declared more than once", g.Name)
+		}
+		groups[g.Name] = true
+		if len(g.IncludedTools) == 0 {
+			return fmt.Errorf("tool group %s must contain at least one tool", g.Name)
+		}
+	}
+
+	if !opts.ManageAccess && (len(m.Clients) > 0 || len(m.Users) > 0) {
+		return errors.New("MCP clients and users can only be declared when mcpjungle runs in production mode")
+	}
+	clients := make(map[string]bool, len(m.Clients))
+	for _, c := range m.Clients {
+		if c.Name == "" {
+			return errors.New("every MCP client must have a name")
+		}
+		if clients[c.Name] {
+			return fmt.Errorf("MCP client %s is declared more than once", c.Name)
+		}
+		clients[c.Name] = true
+	}
+	users := make(map[string]bool, len(m.Users))
+	for _, u := range m.Users {
+		if u.Username == "" {
+			return errors.New("every user must have a username")
+		}
+		if users[u.Username] {
+			return fmt.Errorf("user %s is declared more than once", u.Username)
+		}
+		users[u.Username] = true
+		if u.Role != "" && u.Role != string(types.UserRoleUser) {
+			return fmt.Errorf("user %s: only users with the '%s' role can be declared", u.Username, types.UserRoleUser)
+		}
+	}
+	return nil
+}
+
+// planServers plans the registration, update and deletion of MCP servers.
+// It returns the servers that will be registered (again), whose tools will all be enabled afterwards.
+func (s *ReconcileService) planServers(p *plan, m *types.Manifest, opts Options) (map[string]bool, error) {
+	records, err := s.mcpService.ListMcpServers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MCP servers: %w", err)
+	}
+	current := make(map[string]*model.McpServer, len(records))
+	for i := range records {
+		current[records[i].Name] = &records[i]
+	}
+
+	reregistered := make(map[string]bool)
+	for i := range m.Servers {
+		in := types.RegisterServerInput(m.Servers[i])
+		cur, ok := current[in.Name]
+		if err := keepCurrentSecrets(&in, cur); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidManifest, err)
+		}
+		// the manifest is validated, so this can't fail
+		want, _ := model.NewMcpServer(&in)
+		if !ok {
+			reregistered[want.Name] = true
+			p.add(types.ReconcileCreate, types.ReconcileKindServer, want.Name, "",
+				func(ctx context.Context, _ *types.ReconcileChange) error {
+					return s.mcpService.RegisterMcpServer(ctx, want)
+				},
+			)
+			continue
+		}
+
+		changed, reconnect, err := diffServer(cur, want)
+		if err != nil {
+			return nil, err
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		if reconnect {
+			reregistered[want.Name] = true
+		}
+		p.add(types.ReconcileUpdate, types.ReconcileKindServer, want.Name, strings.Join(changed, ", "),
+			func(ctx context.Context, _ *types.ReconcileChange) error {
+				return s.mcpService.UpdateMcpServer(ctx, want)
+			},
+		)
+	}
+
+	if opts.Prune {
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			if slices.ContainsFunc(m.Servers, func(in types.ManifestServer) bool { return in.Name == name }) {
+				continue
+			}
+			p.add(types.ReconcileDelete, types.ReconcileKindServer, name, "",
+				func(context.Context, *types.ReconcileChange) error {
+					return s.mcpService.DeregisterMcpServer(name)
+				},
+			)
+		}
+	}
+	return reregistered, nil
+}
+
+// keepCurrentSecrets replaces the secrets of a declared server that are types.KeepCurrentSecret
+// with the secrets of the registered server cur, which is nil if the server isn't registered.
+// It fails if a secret to keep doesn't exist.
+func keepCurrentSecrets(in *types.RegisterServerInput, cur *model.McpServer) error {
+	omitted := in.BearerToken == types.KeepCurrentSecret ||
+		slices.Contains(slices.Collect(maps.Values(in.Env)), types.KeepCurrentSecret)
+	if !omitted {
+		return nil
+	}
+	if cur == nil {
+		return fmt.Errorf("server %s: secrets can only be kept for registered servers, fill them in to register it", in.Name)
+	}
+	curInput, err := cur.ToInput()
+	if err != nil {
+		return fmt.Errorf("failed to read configuration of MCP server %s: %w", cur.Name, err)
+	}
+	if in.BearerToken == types.KeepCurrentSecret {
+		in.BearerToken = curInput.BearerToken
+	}
+	// the manifest is left untouched
+	in.Env = maps.Clone(in.Env)
+	for k, v := range in.Env {
+		if v != types.KeepCurrentSecret {
+			continue
+		}
+		current, ok := curInput.Env[k]
+		if !ok {
+			return fmt.Errorf("server %s: environment variable %s has no current value to keep", in.Name, k)
+		}
+		in.Env[k] = current
+	}
+	return nil
+}
+
+// diffServer returns the names of the configuration fields that differ between a registered server and
+// its declaration, and whether the server must be registered again for the change to take effect.
+func diffServer(cur, want *model.McpServer) ([]string, bool, error) {
+	a, err := cur.ToInput()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read configuration of MCP server %s: %w", cur.Name, err)
+	}
+	b, err := want.ToInput()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read configuration of MCP server %s: %w", want.Name, err)
+	}
+
+	var changed []string
+	check := func(field string, equal bool) {
+		if !equal {
+			changed = append(changed, field)
+		}
+	}
+	check("transport", a.Transport == b.Transport)
+	check("url", a.URL == b.URL)
+	check("bearer_token", a.BearerToken == b.BearerToken)
+	check("command", a.Command == b.Command)
+	check("args", slices.Equal(a.Args, b.Args))
+	check("env", maps.Equal(a.Env, b.Env))
+	reconnect := len(changed) > 0
+
+	check("description", a.Description == b.Description)
+	check("call_timeout", a.CallTimeout == b.CallTimeout)
+	check("max_retries", ptrEqual(a.MaxRetries, b.MaxRetries))
+	check("retry_backoff", a.RetryBackoff == b.RetryBackoff)
+	check("retry_on_timeout", ptrEqual(a.RetryOnTimeout, b.RetryOnTimeout))
+	check("max_concurrency", ptrEqual(a.MaxConcurrency, b.MaxConcurrency))
+	check("max_queue", ptrEqual(a.MaxQueue, b.MaxQueue))
+	check("queue_timeout", a.QueueTimeout == b.QueueTimeout)
+	return changed, reconnect, nil
+}
+
+func ptrEqual[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// planTools plans enabling and disabling the tools of the servers declared in the manifest.
+func (s *ReconcileService) planTools(p *plan, m *types.Manifest, reregistered map[string]bool) error {
+	disabled := make(map[string]bool, len(m.DisabledTools))
+	for _, t := range m.DisabledTools {
+		disabled[t] = true
+	}
+	setEnabled := func(enabled bool) func(context.Context, *types.ReconcileChange) error {
+		return func(_ context.Context, c *types.ReconcileChange) error {
+			var err error
+			if enabled {
+				_, err = s.mcpService.EnableTools(c.Name)
+			} else {
+				_, err = s.mcpService.DisableTools(c.Name)
+			}
+			return err
+		}
+	}
+
+	for _, in := range m.Servers {
+		if reregistered[in.Name] {
+			// all tools of a newly registered server are enabled, but they aren't known until it is registered
+			for _, t := range m.DisabledTools {
+				if mcp.ServerOfTool(t) == in.Name {
+					p.add(types.ReconcileDisable, types.ReconcileKindTool, t, "", setEnabled(false))
+				}
+			}
+			continue
+		}
+
+		tools, err := s.mcpService.ListToolsByServer(in.Name)
+		if err != nil {
+			return fmt.Errorf("failed to list tools of MCP server %s: %w", in.Name, err)
+		}
+		known := make(map[string]bool, len(tools))
+		for _, t := range tools {
+			known[t.Name] = true
+			switch {
+			case t.Enabled && disabled[t.Name]:
+				p.add(types.ReconcileDisable, types.ReconcileKindTool, t.Name, "", setEnabled(false))
+			case !t.Enabled && !disabled[t.Name]:
+				p.add(types.ReconcileEnable, types.ReconcileKindTool, t.Name, "", setEnabled(true))
+			}
+		}
+		for _, t := range m.DisabledTools {
+			if mcp.ServerOfTool(t) == in.Name && !known[t] {
+				// the tool doesn't exist, so disabling it fails with a clear error when applied
+				p.add(types.ReconcileDisable, types.ReconcileKindTool, t, "", setEnabled(false))
+			}
+		}
+	}
+	return nil
+}
+
+// planToolGroups plans the creation, update and deletion of tool groups.
+func (s *ReconcileService) planToolGroups(p *plan, m *types.Manifest, opts Options) error {
+	records, err := s.toolGroupService.ListToolGroups()
+	if err != nil {
+		return fmt.Errorf("failed to list tool groups: %w", err)
+	}
+	current := make(map[string]*model.ToolGroup, len(records))
+	for i := range records {
+		current[records[i].Name] = &records[i]
+	}
+
+	for _, g := range m.ToolGroups {
+		tools, err := json.Marshal(g.IncludedTools)
+		if err != nil {
+			return fmt.Errorf("failed to serialize tools of group %s: %w", g.Name, err)
+		}
+		want := &model.ToolGroup{Name: g.Name, Description: g.Description, IncludedTools: tools}
+
+		cur, ok := current[g.Name]
+		if !ok {
+			p.add(types.ReconcileCreate, types.ReconcileKindToolGroup, g.Name, "",
+				func(context.Context, *types.ReconcileChange) error {
+					return s.toolGroupService.CreateToolGroup(want)
+				},
+			)
+			continue
+		}
+
+		curTools, err := cur.GetTools()
+		if err != nil {
+			return fmt.Errorf("failed to get tools of group %s: %w", g.Name, err)
+		}
+		var changed []string
+		if cur.Description != g.Description {
+			changed = append(changed, "description")
+		}
+		if d := diffNames(curTools, g.IncludedTools); d != "" {
+			changed = append(changed, "included_tools "+d)
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		p.add(types.ReconcileUpdate, types.ReconcileKindToolGroup, g.Name, strings.Join(changed, ", "),
+			func(context.Context, *types.ReconcileChange) error {
+				return s.toolGroupService.UpdateToolGroup(want)
+			},
+		)
+	}
+
+	if opts.Prune {
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			if slices.ContainsFunc(m.ToolGroups, func(g types.ToolGroup) bool { return g.Name == name }) {
+				continue
+			}
+			p.add(types.ReconcileDelete, types.ReconcileKindToolGroup, name, "",
+				func(context.Context, *types.ReconcileChange) error {
+					return s.toolGroupService.DeleteToolGroup(name)
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// planClients plans the creation, update and deletion of MCP clients.
+func (s *ReconcileService) planClients(p *plan, m *types.Manifest, opts Options) error {
+	records, err := s.mcpClientService.ListClients()
+	if err != nil {
+		return fmt.Errorf("failed to list MCP clients: %w", err)
+	}
+	current := make(map[string]*model.McpClient, len(records))
+	for _, c := range records {
+		current[c.Name] = c
+	}
+
+	for _, c := range m.Clients {
+		allowList := c.AllowList
+		if allowList == nil {
+			allowList = []string{}
+		}
+		allowListJSON, err := json.Marshal(allowList)
+		if err != nil {
+			return fmt.Errorf("failed to serialize allow list of MCP client %s: %w", c.Name, err)
+		}
+		want := model.McpClient{Name: c.Name, Description: c.Description, AllowList: allowListJSON}
+
+		cur, ok := current[c.Name]
+		if !ok {
+			p.add(types.ReconcileCreate, types.ReconcileKindClient, c.Name, "",
+				func(_ context.Context, rc *types.ReconcileChange) error {
+					created, err := s.mcpClientService.CreateClient(want)
+					if err != nil {
+						return err
+					}
+					rc.AccessToken = created.AccessToken
+					return nil
+				},
+			)
+			continue
+		}
+
+		curAllowList, err := allowListOf(cur)
+		if err != nil {
+			return err
+		}
+		var changed []string
+		if cur.Description != c.Description {
+			changed = append(changed, "description")
+		}
+		if d := diffNames(curAllowList, allowList); d != "" {
+			changed = append(changed, "allow_list "+d)
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		p.add(types.ReconcileUpdate, types.ReconcileKindClient, c.Name, strings.Join(changed, ", "),
+			func(context.Context, *types.ReconcileChange) error {
+				return s.mcpClientService.UpdateClient(want)
+			},
+		)
+	}
+
+	if opts.Prune {
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			if slices.ContainsFunc(m.Clients, func(c types.McpClient) bool { return c.Name == name }) {
+				continue
+			}
+			p.add(types.ReconcileDelete, types.ReconcileKindClient, name, "",
+				func(context.Context, *types.ReconcileChange) error {
+					return s.mcpClientService.DeleteClient(name)
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// allowListOf returns the names of the MCP servers that an MCP client may access.
+func allowListOf(c *model.McpClient) ([]string, error) {
+	var allowList []string
+	if len(c.AllowList) > 0 {
+		if err := json.Unmarshal(c.AllowList, &allowList); err != nil {
+			return nil, fmt.Errorf("failed to read allow list of MCP client %s: %w", c.Name, err)
+		}
+	}
+	return allowList, nil
+}
+
+// planUsers plans the creation and deletion of users. Admin users are never changed.
+func (s *ReconcileService) planUsers(p *plan, m *types.Manifest, opts Options) error {
+	records, err := s.userService.ListUsers()
+	if err != nil {
+		return err
+	}
+	current := make(map[string]*model.User, len(records))
+	for i := range records {
+		current[records[i].Username] = &records[i]
+	}
+
+	for _, u := range m.Users {
+		if _, ok := current[u.Username]; ok {
+			continue
+		}
+		p.add(types.ReconcileCreate, types.ReconcileKindUser, u.Username, "",
+			func(_ context.Context, rc *types.ReconcileChange) error {
+				created, err := s.userService.CreateUser(rc.Name)
+				if err != nil {
+					return err
+				}
+				rc.AccessToken = created.AccessToken
+				return nil
+			},
+		)
+	}
+
+	if opts.Prune {
+		for _, name := range slices.Sorted(maps.Keys(current)) {
+			if current[name].Role == types.UserRoleAdmin ||
+				slices.ContainsFunc(m.Users, func(u types.User) bool { return u.Username == name }) {
+				continue
+			}
+			p.add(types.ReconcileDelete, types.ReconcileKindUser, name, "",
+				func(context.Context, *types.ReconcileChange) error {
+					return s.userService.DeleteUser(name)
+				},
+			)
+		}
+	}
+	return nil
+}
+
+// diffNames describes the names added to and removed from a list, eg- "(+a, -b)".
+// It returns an empty string if both lists contain the same names, regardless of their order.
+func diffNames(cur, want []string) string {
+	var diff []string
+	for _, n := range want {
+		if !slices.Contains(cur, n) {
+			diff = append(diff, "+"+n)
+		}
+	}
+	for _, n := range cur {
+		if !slices.Contains(want, n) {
+			diff = append(diff, "-"+n)
+		}
+	}
+	if len(diff) == 0 {
+		return ""
+	}
+	sort.Strings(diff)
+	return "(" + strings.Join(diff, ", ") + ")"
+}
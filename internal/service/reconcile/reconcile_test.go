@@ -0,0 +1,494 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcpclient"
+	"github.com/mcpjungle/mcpjungle/internal/service/toolgroup"
+	"github.com/mcpjungle/mcpjungle/internal/service/user"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+func newTestService(t *testing.T) *ReconcileService {
+	t.Helper()
+	s, _ := newTestServiceWithDB(t)
+	return s
+}
+
+// newTestServiceWithDB also returns the database of the service, so that tests can set up the registry directly.
+func newTestServiceWithDB(t *testing.T) (*ReconcileService, *gorm.DB) {
+	t.Helper()
+	db := dbtest.New(t)
+	mcpService, err := mcp.NewMCPService(db, server.NewMCPServer("test", "0.1.0"))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	toolGroupService, err := toolgroup.NewToolGroupService(db, mcpService)
+	if err != nil {
+		t.Fatalf("failed to create tool group service: %v", err)
+	}
+	s := NewReconcileService(mcpService, toolGroupService, mcpclient.NewMCPClientService(db), user.NewUserService(db))
+	return s, db
+}
+
+// seedServer stores a server with the given tools in the database without connecting to it.
+// Tools whose name is in disabled are stored disabled.
+func seedServer(t *testing.T, db *gorm.DB, server types.ManifestServer, tools []string, disabled ...string) {
+	t.Helper()
+	in := types.RegisterServerInput(server)
+	s, err := model.NewMcpServer(&in)
+	if err != nil {
+		t.Fatalf("failed to create server model: %v", err)
+	}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to store server %s: %v", in.Name, err)
+	}
+	for _, name := range tools {
+		tool := &model.Tool{ServerID: s.ID, Name: name, InputSchema: []byte(`{"type":"object"}`)}
+		if err := db.Create(tool).Error; err != nil {
+			t.Fatalf("failed to store tool %s: %v", name, err)
+		}
+		if slices.Contains(disabled, name) {
+			if err := db.Model(tool).Update("enabled", false).Error; err != nil {
+				t.Fatalf("failed to disable tool %s: %v", name, err)
+			}
+		}
+	}
+}
+
+// planned returns the changes of a plan in the order they are applied.
+func planned(p *plan) []types.ReconcileChange {
+	changes := make([]types.ReconcileChange, 0, len(p.changes)+len(p.deletions))
+	for _, c := range append(p.changes, p.deletions...) {
+		changes = append(changes, c.ReconcileChange)
+	}
+	return changes
+}
+
+func TestDiffServer(t *testing.T) {
+	base := types.RegisterServerInput{
+		Name: "github", Transport: "streamable_http", URL: "https://example.com/mcp", Description: "code",
+	}
+	retries, moreRetries := 1, 2
+	tests := []struct {
+		name          string
+		change        func(in *types.RegisterServerInput)
+		wantChanged   []string
+		wantReconnect bool
+	}{
+		{"no change", func(in *types.RegisterServerInput) {}, nil, false},
+		{"description", func(in *types.RegisterServerInput) { in.Description = "repos" }, []string{"description"}, false},
+		{"settings", func(in *types.RegisterServerInput) {
+			in.CallTimeout = "10s"
+			in.MaxRetries = &moreRetries
+		}, []string{"call_timeout", "max_retries"}, false},
+		{"url", func(in *types.RegisterServerInput) { in.URL = "https://example.org/mcp" }, []string{"url"}, true},
+		{"bearer token and description", func(in *types.RegisterServerInput) {
+			in.BearerToken = "secret"
+			in.Description = ""
+		}, []string{"bearer_token", "description"}, true},
+		{"transport", func(in *types.RegisterServerInput) {
+			in.Transport = "stdio"
+			in.URL = ""
+			in.Command = "uvx"
+		}, []string{"transport", "url", "command"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, want := base, base
+			cur.MaxRetries, want.MaxRetries = &retries, &retries
+			tt.change(&want)
+			curModel, err := model.NewMcpServer(&cur)
+			if err != nil {
+				t.Fatalf("failed to create server model: %v", err)
+			}
+			wantModel, err := model.NewMcpServer(&want)
+			if err != nil {
+				t.Fatalf("failed to create server model: %v", err)
+			}
+
+			changed, reconnect, err := diffServer(curModel, wantModel)
+			if err != nil {
+				t.Fatalf("diffServer() error = %v", err)
+			}
+			if !slices.Equal(changed, tt.wantChanged) || reconnect != tt.wantReconnect {
+				t.Errorf("diffServer() = %v, %v, want %v, %v", changed, reconnect, tt.wantChanged, tt.wantReconnect)
+			}
+		})
+	}
+}
+
+func TestPlanServers(t *testing.T) {
+	github := types.ManifestServer{Name: "github", Transport: "streamable_http", URL: "https://example.com/mcp"}
+	slack := types.ManifestServer{Name: "slack", Transport: "stdio", Command: "slack-mcp"}
+	with := func(in types.ManifestServer, change func(in *types.ManifestServer)) types.ManifestServer {
+		change(&in)
+		return in
+	}
+
+	tests := []struct {
+		name             string
+		servers          []types.ManifestServer
+		prune            bool
+		want             []types.ReconcileChange
+		wantReregistered []string
+	}{
+		{
+			name:    "no changes",
+			servers: []types.ManifestServer{github, slack},
+		},
+		{
+			name:    "undeclared servers are kept without pruning",
+			servers: []types.ManifestServer{github},
+		},
+		{
+			name:    "undeclared servers are deleted with pruning",
+			servers: []types.ManifestServer{github},
+			prune:   true,
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileDelete, Kind: types.ReconcileKindServer, Name: "slack"},
+			},
+		},
+		{
+			name: "new server is created",
+			servers: []types.ManifestServer{
+				github, slack, {Name: "time", Transport: "stdio", Command: "uvx"},
+			},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileCreate, Kind: types.ReconcileKindServer, Name: "time"},
+			},
+			wantReregistered: []string{"time"},
+		},
+		{
+			name: "changed settings update the server in place",
+			servers: []types.ManifestServer{
+				with(github, func(in *types.ManifestServer) { in.Description = "code" }), slack,
+			},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileUpdate, Kind: types.ReconcileKindServer, Name: "github", Details: "description"},
+			},
+		},
+		{
+			name: "changed connection registers the server again",
+			servers: []types.ManifestServer{
+				github, with(slack, func(in *types.ManifestServer) { in.Args = []string{"--verbose"} }),
+			},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileUpdate, Kind: types.ReconcileKindServer, Name: "slack", Details: "args"},
+			},
+			wantReregistered: []string{"slack"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newTestServiceWithDB(t)
+			seedServer(t, db, github, nil)
+			seedServer(t, db, slack, nil)
+
+			p := &plan{}
+			reregistered, err := s.planServers(p, &types.Manifest{Servers: tt.servers}, Options{Prune: tt.prune})
+			if err != nil {
+				t.Fatalf("planServers() error = %v", err)
+			}
+			if got := planned(p); !slices.Equal(got, tt.want) {
+				t.Errorf("planned changes = %+v, want %+v", got, tt.want)
+			}
+			if got := slices.Sorted(maps.Keys(reregistered)); !slices.Equal(got, tt.wantReregistered) {
+				t.Errorf("reregistered servers = %v, want %v", got, tt.wantReregistered)
+			}
+		})
+	}
+}
+
+func TestPlanTools(t *testing.T) {
+	github := types.ManifestServer{Name: "github", Transport: "streamable_http", URL: "https://example.com/mcp"}
+	slack := types.ManifestServer{Name: "slack", Transport: "stdio", Command: "slack-mcp"}
+
+	tests := []struct {
+		name          string
+		servers       []types.ManifestServer
+		disabledTools []string
+		reregistered  []string
+		want          []types.ReconcileChange
+	}{
+		{
+			name:          "no changes",
+			servers:       []types.ManifestServer{github},
+			disabledTools: []string{"github__delete_repo"},
+		},
+		{
+			name:    "tool missing from the disabled tools is enabled",
+			servers: []types.ManifestServer{github},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileEnable, Kind: types.ReconcileKindTool, Name: "github__delete_repo"},
+			},
+		},
+		{
+			name:          "disabled tool is disabled",
+			servers:       []types.ManifestServer{github},
+			disabledTools: []string{"github__create_pr", "github__delete_repo"},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileDisable, Kind: types.ReconcileKindTool, Name: "github__create_pr"},
+			},
+		},
+		{
+			name:          "unknown disabled tool is disabled so that it fails when applied",
+			servers:       []types.ManifestServer{github},
+			disabledTools: []string{"github__delete_repo", "github__merge_pr"},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileDisable, Kind: types.ReconcileKindTool, Name: "github__merge_pr"},
+			},
+		},
+		{
+			name:          "tools of a reregistered server are disabled after its registration",
+			servers:       []types.ManifestServer{github},
+			disabledTools: []string{"github__merge_pr"},
+			reregistered:  []string{"github"},
+			want: []types.ReconcileChange{
+				{Action: types.ReconcileDisable, Kind: types.ReconcileKindTool, Name: "github__merge_pr"},
+			},
+		},
+		{
+			name:    "tools of undeclared servers are untouched",
+			servers: []types.ManifestServer{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, db := newTestServiceWithDB(t)
+			seedServer(t, db, github, []string{"create_pr", "delete_repo"}, "delete_repo")
+			seedServer(t, db, slack, []string{"post_message"}, "post_message")
+
+			reregistered := make(map[string]bool)
+			for _, name := range tt.reregistered {
+				reregistered[name] = true
+			}
+			p := &plan{}
+			m := &types.Manifest{Servers: tt.servers, DisabledTools: tt.disabledTools}
+			if err := s.planTools(p, m, reregistered); err != nil {
+				t.Fatalf("planTools() error = %v", err)
+			}
+			if got := planned(p); !slices.Equal(got, tt.want) {
+				t.Errorf("planned changes = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileClientsAndUsers(t *testing.T) {
+	s := newTestService(t)
+	ctx := context.Background()
+	opts := Options{ManageAccess: true}
+
+	if _, err := s.userService.CreateAdminUser(); err != nil {
+		t.Fatalf("failed to create admin user: %v", err)
+	}
+	if _, err := s.userService.CreateUser("bob"); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	m := &types.Manifest{
+		Clients: []types.McpClient{{Name: "cursor", AllowList: []string{"github"}}},
+		Users:   []types.User{{Username: "alice"}},
+	}
+
+	// a dry run only reports the changes
+	result, err := s.Reconcile(ctx, m, Options{DryRun: true, ManageAccess: true})
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if len(result.Changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", result.Changes)
+	}
+	if clients, _ := s.mcpClientService.ListClients(); len(clients) != 0 {
+		t.Fatalf("dry run must not create clients, got %d", len(clients))
+	}
+
+	result, err = s.Reconcile(ctx, m, opts)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	for _, c := range result.Changes {
+		if c.Action != types.ReconcileCreate || c.Error != "" || c.AccessToken == "" {
+			t.Errorf("expected a successful creation with an access token, got %+v", c)
+		}
+	}
+
+	// applying the same manifest again changes nothing
+	result, err = s.Reconcile(ctx, m, opts)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", result.Changes)
+	}
+
+	// changing the allow list updates the client and pruning deletes bob, but never the admin
+	m.Clients[0].AllowList = []string{"github", "slack"}
+	opts.Prune = true
+	result, err = s.Reconcile(ctx, m, opts)
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	want := []types.ReconcileChange{
+		{Action: types.ReconcileUpdate, Kind: types.ReconcileKindClient, Name: "cursor", Details: "allow_list (+slack)"},
+		{Action: types.ReconcileDelete, Kind: types.ReconcileKindUser, Name: "bob"},
+	}
+	if len(result.Changes) != len(want) {
+		t.Fatalf("expected changes %+v, got %+v", want, result.Changes)
+	}
+	for i := range want {
+		if result.Changes[i] != want[i] {
+			t.Errorf("expected change %+v, got %+v", want[i], result.Changes[i])
+		}
+	}
+
+	users, err := s.userService.ListUsers()
+	if err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+	if len(users) != 2 {
+		t.Errorf("expected admin and alice to remain, got %+v", users)
+	}
+}
+
+func TestReconcileToolGroupErrorsAreReported(t *testing.T) {
+	s := newTestService(t)
+
+	m := &types.Manifest{
+		ToolGroups: []types.ToolGroup{{Name: "coding", IncludedTools: []string{"github__create_pr"}}},
+	}
+	result, err := s.Reconcile(context.Background(), m, Options{})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if result.Failed() != 1 {
+		t.Fatalf("expected the group creation to fail because its tool doesn't exist, got %+v", result.Changes)
+	}
+}
+
+func TestReconcileRejectsInvalidManifests(t *testing.T) {
+	s := newTestService(t)
+
+	cases := map[string]*types.Manifest{
+		"duplicate server": {Servers: []types.ManifestServer{
+			{Name: "a", Transport: "stdio", Command: "a"},
+			{Name: "a", Transport: "stdio", Command: "b"},
+		}},
+		"invalid transport": {Servers: []types.ManifestServer{{Name: "a", Transport: "sse"}}},
+		"undeclared tool":   {DisabledTools: []string{"github__create_pr"}},
+		"empty group":       {ToolGroups: []types.ToolGroup{{Name: "g"}}},
+		"clients in dev":    {Clients: []types.McpClient{{Name: "cursor"}}},
+	}
+	for name, m := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Reconcile(context.Background(), m, Options{})
+			if !errors.Is(err, ErrInvalidManifest) {
+				t.Errorf("expected ErrInvalidManifest, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	s, db := newTestServiceWithDB(t)
+	ctx := context.Background()
+	opts := Options{ManageAccess: true}
+
+	retries := 2
+	github := types.ManifestServer{
+		Name: "github", Transport: "streamable_http", URL: "https://example.com/mcp",
+		BearerToken: "gh-token", MaxRetries: &retries,
+	}
+	slack := types.ManifestServer{
+		Name: "slack", Transport: "stdio", Command: "slack-mcp", Args: []string{"--team", "eng"},
+		Env: map[string]string{"SLACK_TOKEN": "xoxb", "SLACK_TEAM": "eng"},
+	}
+	seedServer(t, db, github, []string{"create_pr", "delete_repo"}, "delete_repo")
+	seedServer(t, db, slack, []string{"post_message"})
+
+	if _, err := s.userService.CreateAdminUser(); err != nil {
+		t.Fatalf("failed to create admin user: %v", err)
+	}
+	m := &types.Manifest{
+		Clients: []types.McpClient{
+			{Name: "cursor", AllowList: []string{"github"}},
+			{Name: "claude", Description: "desktop app"},
+		},
+		Users: []types.User{{Username: "alice"}},
+	}
+	if _, err := s.Reconcile(ctx, m, opts); err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+
+	exported, err := s.Export(ExportOptions{ManageAccess: true})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if len(exported.Clients) != 2 || exported.Clients[0].Name != "claude" || len(exported.Users) != 1 {
+		t.Fatalf("unexpected exported manifest: %+v", exported)
+	}
+	if !reflect.DeepEqual(exported.Servers, []types.ManifestServer{github, slack}) {
+		t.Errorf("exported servers = %+v, want %+v", exported.Servers, []types.ManifestServer{github, slack})
+	}
+	if !slices.Equal(exported.DisabledTools, []string{"github__delete_repo"}) {
+		t.Errorf("exported disabled tools = %v, want [github__delete_repo]", exported.DisabledTools)
+	}
+
+	// applying the exported manifest, even with pruning, must not change anything
+	result, err := s.Reconcile(ctx, exported, Options{ManageAccess: true, Prune: true})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", result.Changes)
+	}
+
+	// the same goes for a manifest exported without secrets, which keeps the current ones
+	withoutSecrets, err := s.Export(ExportOptions{OmitSecrets: true})
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	if withoutSecrets.Servers[0].BearerToken != types.KeepCurrentSecret ||
+		withoutSecrets.Servers[1].Env["SLACK_TOKEN"] != types.KeepCurrentSecret {
+		t.Fatalf("expected the secrets to be omitted, got %+v", withoutSecrets.Servers)
+	}
+	result, err = s.Reconcile(ctx, withoutSecrets, Options{Prune: true})
+	if err != nil {
+		t.Fatalf("reconcile failed: %v", err)
+	}
+	if len(result.Changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", result.Changes)
+	}
+	for _, want := range []types.ManifestServer{github, slack} {
+		cur, err := s.mcpService.GetMcpServer(want.Name)
+		if err != nil {
+			t.Fatalf("failed to get server %s: %v", want.Name, err)
+		}
+		in, err := cur.ToInput()
+		if err != nil {
+			t.Fatalf("failed to read server %s: %v", want.Name, err)
+		}
+		if in.BearerToken != want.BearerToken || !maps.Equal(in.Env, want.Env) {
+			t.Errorf("server %s has secrets %q, %v, want them kept as %q, %v",
+				want.Name, in.BearerToken, in.Env, want.BearerToken, want.Env)
+		}
+	}
+
+	t.Run("secrets can't be kept for servers that aren't registered", func(t *testing.T) {
+		empty := newTestService(t)
+		if _, err := empty.Reconcile(ctx, withoutSecrets, Options{}); !errors.Is(err, ErrInvalidManifest) {
+			t.Errorf("expected ErrInvalidManifest, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,339 @@
+// Package stats aggregates usage statistics of tools, so that operators can find out which tools are actually used.
+package stats
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dayFormat is the format of the Day column of the stats table
+const dayFormat = "2006-01-02"
+
+// flushInterval is how often the aggregated statistics are written to the database
+const flushInterval = 10 * time.Second
+
+// ErrInvalidGrouping is returned when statistics are requested with an unknown grouping.
+var ErrInvalidGrouping = errors.New("invalid grouping: must be one of tool, server, client or day")
+
+// latencyBucketsMs are the upper bounds of the latency histogram buckets in milliseconds.
+// Latencies beyond the last bound are counted in an additional overflow bucket.
+// Changing the bounds invalidates the histograms already stored in the database.
+var latencyBucketsMs = []float64{5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000}
+
+// Query selects the tool call statistics to return and how to group them.
+// Empty Server, Tool and Client fields match all calls.
+type Query struct {
+	By    types.StatsGrouping
+	Since time.Time
+
+	Server string
+	Tool   string
+	Client string
+}
+
+// statKey identifies a row of the stats table.
+type statKey struct {
+	day    string
+	server string
+	tool   string
+	client string
+}
+
+// aggregate holds the statistics of a set of calls.
+type aggregate struct {
+	calls     int64
+	errors    int64
+	histogram []int64
+}
+
+func newAggregate() *aggregate {
+	return &aggregate{histogram: make([]int64, len(latencyBucketsMs)+1)}
+}
+
+func (a *aggregate) observe(d time.Duration, failed bool) {
+	a.calls++
+	if failed {
+		a.errors++
+	}
+	ms := float64(d) / float64(time.Millisecond)
+	i := sort.SearchFloat64s(latencyBucketsMs, ms)
+	a.histogram[i]++
+}
+
+func (a *aggregate) merge(o *aggregate) {
+	a.calls += o.calls
+	a.errors += o.errors
+	for i := range a.histogram {
+		if i < len(o.histogram) {
+			a.histogram[i] += o.histogram[i]
+		}
+	}
+}
+
+// percentile estimates the p-th percentile (0 < p < 1) of the latencies in milliseconds,
+// interpolating linearly within the bucket it falls in.
+func (a *aggregate) percentile(p float64) float64 {
+	var total int64
+	for _, n := range a.histogram {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := p * float64(total)
+	var seen int64
+	for i, n := range a.histogram {
+		if n == 0 || float64(seen+n) < rank {
+			seen += n
+			continue
+		}
+		if i == len(latencyBucketsMs) {
+			// the overflow bucket has no upper bound, so the best estimate is its lower bound
+			return latencyBucketsMs[i-1]
+		}
+		lower := 0.0
+		if i > 0 {
+			lower = latencyBucketsMs[i-1]
+		}
+		return lower + (latencyBucketsMs[i]-lower)*(rank-float64(seen))/float64(n)
+	}
+	return latencyBucketsMs[len(latencyBucketsMs)-1]
+}
+
+// StatsService aggregates tool calls per tool, server, client and day.
+// Calls are aggregated in memory and periodically added to the stats table in the database.
+type StatsService struct {
+	db *gorm.DB
+
+	pending map[statKey]*aggregate
+	mu      sync.Mutex
+	// flushMu ensures that a single flush writes to the database at a time
+	flushMu sync.Mutex
+}
+
+func NewStatsService(db *gorm.DB) *StatsService {
+	return &StatsService{
+		db:      db,
+		pending: make(map[statKey]*aggregate),
+	}
+}
+
+// RecordToolCall adds a tool call to the statistics.
+// Its signature matches mcp.ToolCallCallback.
+func (s *StatsService) RecordToolCall(call *mcp.ToolCall) {
+	key := statKey{
+		day:    call.Start.UTC().Format(dayFormat),
+		server: call.Server,
+		tool:   call.Tool,
+		client: call.Client,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.pending[key]
+	if !ok {
+		a = newAggregate()
+		s.pending[key] = a
+	}
+	a.observe(call.Duration, call.Outcome != metrics.OutcomeSuccess)
+}
+
+// Start periodically writes the aggregated statistics to the database until ctx is cancelled.
+func (s *StatsService) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				// write whatever was aggregated since the last flush before shutting down
+				if err := s.flush(); err != nil {
+					slog.Warn("failed to save tool call statistics", logging.KeyError, err)
+				}
+				return
+			case <-ticker.C:
+				if err := s.flush(); err != nil {
+					slog.Warn("failed to save tool call statistics", logging.KeyError, err)
+				}
+			}
+		}
+	}()
+}
+
+// flush adds the statistics aggregated in memory to the stats table.
+// If writing a row fails, its statistics are kept in memory and retried on the next flush.
+func (s *StatsService) flush() error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[statKey]*aggregate)
+	s.mu.Unlock()
+
+	var errs []error
+	for key, a := range pending {
+		if err := s.save(key, a); err != nil {
+			errs = append(errs, err)
+			s.mu.Lock()
+			if cur, ok := s.pending[key]; ok {
+				a.merge(cur)
+			}
+			s.pending[key] = a
+			s.mu.Unlock()
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// save adds the statistics of a single tool, client and day to its row in the stats table.
+// The row is locked while it is updated, because other servers sharing the database update it as well.
+func (s *StatsService) save(key statKey, a *aggregate) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		// create the row if this is the first flush of the day, which may race with another server
+		row := model.ToolCallStat{Day: key.day, Server: key.server, Tool: key.tool, Client: key.client}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+			return fmt.Errorf("failed to create tool call statistics: %w", err)
+		}
+
+		row = model.ToolCallStat{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(
+			"day = ? AND server = ? AND tool = ? AND client = ?", key.day, key.server, key.tool, key.client,
+		).First(&row).Error
+		if err != nil {
+			return fmt.Errorf("failed to get tool call statistics: %w", err)
+		}
+
+		merged, err := aggregateOf(&row)
+		if err != nil {
+			return err
+		}
+		merged.merge(a)
+
+		histogram, err := json.Marshal(merged.histogram)
+		if err != nil {
+			return fmt.Errorf("failed to serialize latency histogram: %w", err)
+		}
+		row.Calls = merged.calls
+		row.Errors = merged.errors
+		row.LatencyHistogram = datatypes.JSON(histogram)
+		if err := tx.Save(&row).Error; err != nil {
+			return fmt.Errorf("failed to save tool call statistics: %w", err)
+		}
+		return nil
+	})
+}
+
+// aggregateOf converts a row of the stats table into an aggregate.
+func aggregateOf(row *model.ToolCallStat) (*aggregate, error) {
+	a := newAggregate()
+	a.calls = row.Calls
+	a.errors = row.Errors
+	if len(row.LatencyHistogram) > 0 {
+		var histogram []int64
+		if err := json.Unmarshal(row.LatencyHistogram, &histogram); err != nil {
+			return nil, fmt.Errorf("invalid latency histogram of tool %s on %s: %w", row.Tool, row.Day, err)
+		}
+		a.merge(&aggregate{histogram: histogram})
+	}
+	return a, nil
+}
+
+// GetStats returns the statistics of the tool calls matching the query, grouped as requested.
+// Groups are ordered by the number of calls, or chronologically when grouping by day.
+func (s *StatsService) GetStats(q Query) ([]types.ToolCallStats, error) {
+	// include the calls made since the last flush
+	if err := s.flush(); err != nil {
+		return nil, err
+	}
+
+	groupKey, err := groupKeyFunc(q.By)
+	if err != nil {
+		return nil, err
+	}
+
+	db := s.db.Where("day >= ?", q.Since.UTC().Format(dayFormat))
+	if q.Server != "" {
+		db = db.Where("server = ?", q.Server)
+	}
+	if q.Tool != "" {
+		db = db.Where("tool = ?", q.Tool)
+	}
+	if q.Client != "" {
+		db = db.Where("client = ?", q.Client)
+	}
+	var rows []model.ToolCallStat
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to get tool call statistics: %w", err)
+	}
+
+	groups := make(map[string]*aggregate)
+	for i := range rows {
+		a, err := aggregateOf(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		k := groupKey(&rows[i])
+		if g, ok := groups[k]; ok {
+			g.merge(a)
+		} else {
+			groups[k] = a
+		}
+	}
+
+	stats := make([]types.ToolCallStats, 0, len(groups))
+	for k, g := range groups {
+		st := types.ToolCallStats{
+			Key:    k,
+			Calls:  g.calls,
+			Errors: g.errors,
+			P50Ms:  g.percentile(0.5),
+			P95Ms:  g.percentile(0.95),
+		}
+		if g.calls > 0 {
+			st.ErrorRate = float64(g.errors) / float64(g.calls)
+		}
+		stats = append(stats, st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if q.By == types.StatsByDay {
+			return stats[i].Key < stats[j].Key
+		}
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	return stats, nil
+}
+
+// groupKeyFunc returns a function that extracts the group key from a row of the stats table.
+func groupKeyFunc(by types.StatsGrouping) (func(row *model.ToolCallStat) string, error) {
+	switch by {
+	case types.StatsByTool:
+		return func(row *model.ToolCallStat) string { return row.Tool }, nil
+	case types.StatsByServer:
+		return func(row *model.ToolCallStat) string { return row.Server }, nil
+	case types.StatsByClient:
+		return func(row *model.ToolCallStat) string { return row.Client }, nil
+	case types.StatsByDay:
+		return func(row *model.ToolCallStat) string { return row.Day }, nil
+	default:
+		return nil, ErrInvalidGrouping
+	}
+}
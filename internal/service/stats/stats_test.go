@@ -0,0 +1,149 @@
+package stats
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+)
+
+func newTestService(t *testing.T) *StatsService {
+	t.Helper()
+	db := dbtest.New(t)
+	return NewStatsService(db)
+}
+
+func recordCalls(s *StatsService, day time.Time, tool, client, outcome string, d time.Duration, n int) {
+	for i := 0; i < n; i++ {
+		s.RecordToolCall(&mcp.ToolCall{
+			Server:   "docs",
+			Tool:     tool,
+			Client:   client,
+			Outcome:  outcome,
+			Start:    day,
+			Duration: d,
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	a := newAggregate()
+	for i := 0; i < 90; i++ {
+		a.observe(20*time.Millisecond, false)
+	}
+	for i := 0; i < 10; i++ {
+		a.observe(2*time.Second, false)
+	}
+
+	// 90 calls fall in the (10ms, 25ms] bucket, so the median is interpolated within it
+	if p50 := a.percentile(0.5); p50 <= 10 || p50 > 25 {
+		t.Errorf("percentile(0.5) = %v, want within (10, 25]", p50)
+	}
+	if p95 := a.percentile(0.95); p95 <= 1000 || p95 > 2500 {
+		t.Errorf("percentile(0.95) = %v, want within (1000, 2500]", p95)
+	}
+	if p := newAggregate().percentile(0.5); p != 0 {
+		t.Errorf("percentile() of an empty aggregate = %v, want 0", p)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	s := newTestService(t)
+	today := time.Now().UTC()
+	lastMonth := today.AddDate(0, -1, 0)
+
+	recordCalls(s, today, "docs__lookup", "agent", metrics.OutcomeSuccess, 20*time.Millisecond, 6)
+	recordCalls(s, today, "docs__lookup", "agent", metrics.OutcomeToolError, 20*time.Millisecond, 2)
+	recordCalls(s, lastMonth, "docs__lookup", "agent", metrics.OutcomeSuccess, 20*time.Millisecond, 5)
+	if err := s.flush(); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+	// calls that haven't been flushed yet are included as well, and added to the existing rows
+	recordCalls(s, today, "docs__lookup", "ide", metrics.OutcomeError, 20*time.Millisecond, 1)
+	recordCalls(s, today, "docs__search", "agent", metrics.OutcomeSuccess, 20*time.Millisecond, 1)
+	recordCalls(s, today, "docs__lookup", "agent", metrics.OutcomeSuccess, 20*time.Millisecond, 2)
+
+	stats, err := s.GetStats(Query{By: types.StatsByTool, Since: today.AddDate(0, 0, -7)})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("GetStats() returned %d groups, want 2: %+v", len(stats), stats)
+	}
+	lookup := stats[0]
+	if lookup.Key != "docs__lookup" || lookup.Calls != 11 || lookup.Errors != 3 {
+		t.Errorf("GetStats()[0] = %+v, want 11 calls with 3 errors to docs__lookup", lookup)
+	}
+	if lookup.P50Ms <= 10 || lookup.P50Ms > 25 {
+		t.Errorf("GetStats()[0].P50Ms = %v, want within (10, 25]", lookup.P50Ms)
+	}
+
+	stats, err = s.GetStats(Query{By: types.StatsByClient, Since: lastMonth, Tool: "docs__lookup"})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	if len(stats) != 2 || stats[0].Key != "agent" || stats[0].Calls != 15 || stats[1].Key != "ide" {
+		t.Errorf("GetStats() by client = %+v, want agent with 15 calls followed by ide", stats)
+	}
+
+	if _, err := s.GetStats(Query{By: "week", Since: today}); err != ErrInvalidGrouping {
+		t.Errorf("GetStats() error = %v, want ErrInvalidGrouping", err)
+	}
+}
+
+func TestConcurrentFlushesOnSharedDatabase(t *testing.T) {
+	db := dbtest.New(t)
+	// two servers sharing a database flush the statistics of the same tool at the same time.
+	// SQLite serializes the transactions anyway, so updates can only get lost on PostgreSQL and MySQL.
+	servers := []*StatsService{NewStatsService(db), NewStatsService(db)}
+	today := time.Now().UTC()
+	const rounds, callsPerRound = 10, 3
+
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range rounds {
+				recordCalls(s, today, "docs__lookup", "agent", metrics.OutcomeSuccess, 20*time.Millisecond, callsPerRound)
+				// a failed flush keeps its calls for the next one, so only lost updates change the totals
+				_ = s.flush()
+			}
+		}()
+	}
+	wg.Wait()
+	for _, s := range servers {
+		if err := s.flush(); err != nil {
+			t.Fatalf("flush() error = %v", err)
+		}
+	}
+
+	stats, err := servers[0].GetStats(Query{By: types.StatsByTool, Since: today.AddDate(0, 0, -1)})
+	if err != nil {
+		t.Fatalf("GetStats() error = %v", err)
+	}
+	want := int64(len(servers) * rounds * callsPerRound)
+	if len(stats) != 1 || stats[0].Calls != want {
+		t.Fatalf("GetStats() = %+v, want %d calls", stats, want)
+	}
+	var row model.ToolCallStat
+	if err := db.First(&row).Error; err != nil {
+		t.Fatalf("failed to get statistics row: %v", err)
+	}
+	a, err := aggregateOf(&row)
+	if err != nil {
+		t.Fatalf("aggregateOf() error = %v", err)
+	}
+	var histogramCalls int64
+	for _, n := range a.histogram {
+		histogramCalls += n
+	}
+	if histogramCalls != want {
+		t.Errorf("latency histogram counts %d calls, want %d", histogramCalls, want)
+	}
+}
@@ -4,12 +4,17 @@ package toolgroup
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"regexp"
 	"sync"
 
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/events"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
 	"github.com/mcpjungle/mcpjungle/internal/model"
 	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
 	"gorm.io/gorm"
 )
 
@@ -31,6 +36,9 @@ type ToolGroupService struct {
 	mcpServers map[string]*server.MCPServer
 	// mu protects access to the mcpServers map
 	mu sync.RWMutex
+
+	// events receives events about created & deleted tool groups. It is nil if events are not published.
+	events events.Publisher
 }
 
 func NewToolGroupService(db *gorm.DB, mcpService *mcp.MCPService) (*ToolGroupService, error) {
@@ -45,7 +53,7 @@ func NewToolGroupService(db *gorm.DB, mcpService *mcp.MCPService) (*ToolGroupSer
 	mcpService.SetToolDeletionCallback(s.handleToolDeletion)
 	mcpService.SetToolAdditionCallback(s.handleToolAddition)
 
-	if err := s.initToolGroupMCPServers(); err != nil {
+	if err := s.Reload(); err != nil {
 		return nil, fmt.Errorf("failed to initialize tool group MCP servers: %w", err)
 	}
 	return s, nil
@@ -64,38 +72,74 @@ func (s *ToolGroupService) CreateToolGroup(group *model.ToolGroup) error {
 		)
 	}
 
+	mcpServer, toolNames, err := s.newGroupMCPServer(group)
+	if err != nil {
+		return err
+	}
+
+	// first, add the tool group to the database
+	// this also checks for uniqueness of the group's name
+	if err := s.db.Create(group).Error; err != nil {
+		return fmt.Errorf("failed to create tool group: %w", err)
+	}
+
+	// finally, add the proxy MCP to the tool group MCPs manager so that it is ready to serve
+	s.addToolGroupMCPServer(group.Name, mcpServer)
+	s.recordChange()
+
+	s.publishEvent(types.EventToolGroupCreated, map[string]any{"group": group.Name, "tools": toolNames})
+	return nil
+}
+
+// UpdateToolGroup replaces the description and tools of an existing tool group.
+// The group's MCP proxy server is replaced, so the change takes effect immediately.
+func (s *ToolGroupService) UpdateToolGroup(group *model.ToolGroup) error {
+	cur, err := s.GetToolGroup(group.Name)
+	if err != nil {
+		return err
+	}
+	mcpServer, toolNames, err := s.newGroupMCPServer(group)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Model(cur).Select("Description", "IncludedTools").Updates(&model.ToolGroup{
+		Description:   group.Description,
+		IncludedTools: group.IncludedTools,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to update tool group: %w", err)
+	}
+	s.addToolGroupMCPServer(group.Name, mcpServer)
+	s.recordChange()
+
+	s.publishEvent(types.EventToolGroupUpdated, map[string]any{"group": group.Name, "tools": toolNames})
+	return nil
+}
+
+// newGroupMCPServer creates the proxy MCP server that exposes only the tools of a group.
+// It returns an error if the group has no tools or if any of its tools does not exist.
+func (s *ToolGroupService) newGroupMCPServer(group *model.ToolGroup) (*server.MCPServer, []string, error) {
 	toolNames, err := group.GetTools()
 	if err != nil {
-		return fmt.Errorf("failed to parse toolNames: %w", err)
+		return nil, nil, fmt.Errorf("failed to parse toolNames: %w", err)
 	}
 	if len(toolNames) == 0 {
-		return errors.New("tool group must contain at least one tool")
+		return nil, nil, errors.New("tool group must contain at least one tool")
 	}
 
-	// create the proxy MCP server that exposes only specified tools
 	mcpServer := s.newMCPServer(group.Name)
 
 	// populate the MCP server with the specified tools
 	// this also has a side effect of validating that the tools exist in mcpjungle.
-	// if a tool does not exist, return an error without creating the group.
 	for _, name := range toolNames {
 		tool, exists := s.mcpService.GetToolInstance(name)
 		if !exists {
-			return fmt.Errorf("tool %s does not exist or is disabled", name)
+			return nil, nil, fmt.Errorf("tool %s does not exist or is disabled", name)
 		}
 		mcpServer.AddTool(tool, s.mcpService.MCPProxyToolCallHandler)
 	}
-
-	// first, add the tool group to the database
-	// this also checks for uniqueness of the group's name
-	if err := s.db.Create(group).Error; err != nil {
-		return fmt.Errorf("failed to create tool group: %w", err)
-	}
-
-	// finally, add the proxy MCP to the tool group MCPs manager so that it is ready to serve
-	s.addToolGroupMCPServer(group.Name, mcpServer)
-
-	return nil
+	return mcpServer, toolNames, nil
 }
 
 // GetToolGroup retrieves a tool group by name from the database.
@@ -122,13 +166,30 @@ func (s *ToolGroupService) ListToolGroups() ([]model.ToolGroup, error) {
 func (s *ToolGroupService) DeleteToolGroup(name string) error {
 	s.deleteToolGroupMCPServer(name)
 
-	err := s.db.Unscoped().Where("name = ?", name).Delete(&model.ToolGroup{}).Error
-	if err != nil {
-		return fmt.Errorf("failed to delete toolgroup: %w", err)
+	result := s.db.Unscoped().Where("name = ?", name).Delete(&model.ToolGroup{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete toolgroup: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		s.recordChange()
+		s.publishEvent(types.EventToolGroupDeleted, map[string]any{"group": name})
 	}
 	return nil
 }
 
+// SetEventPublisher registers the publisher that events about tool groups are published to.
+func (s *ToolGroupService) SetEventPublisher(p events.Publisher) {
+	s.events = p
+}
+
+// publishEvent publishes an event if an event publisher is registered.
+func (s *ToolGroupService) publishEvent(t types.EventType, data map[string]any) {
+	if s.events == nil {
+		return
+	}
+	s.events.Publish(t, data)
+}
+
 // GetToolGroupMCPServer retrieves the MCP proxy server for a given tool group name.
 func (s *ToolGroupService) GetToolGroupMCPServer(name string) (*server.MCPServer, bool) {
 	s.mu.RLock()
@@ -162,12 +223,16 @@ func (s *ToolGroupService) deleteToolGroupMCPServer(name string) {
 	delete(s.mcpServers, name)
 }
 
-// initToolGroupMCPServers initializes the MCP proxy servers for all existing tool groups in the database.
-func (s *ToolGroupService) initToolGroupMCPServers() error {
+// Reload recreates the MCP proxy servers of all tool groups from the database.
+// It is called when the service is created and whenever the database was changed without going through
+// this service, eg- when a backup is restored or another mcpjungle server sharing the database changed a group.
+// Proxy servers of groups that no longer exist are dropped.
+func (s *ToolGroupService) Reload() error {
 	groups, err := s.ListToolGroups()
 	if err != nil {
 		return fmt.Errorf("failed to list tool groups from DB: %w", err)
 	}
+	mcpServers := make(map[string]*server.MCPServer, len(groups))
 	for _, group := range groups {
 		toolNames, err := group.GetTools()
 		if err != nil {
@@ -186,11 +251,23 @@ func (s *ToolGroupService) initToolGroupMCPServers() error {
 			}
 			mcpServer.AddTool(tool, s.mcpService.MCPProxyToolCallHandler)
 		}
-		s.addToolGroupMCPServer(group.Name, mcpServer)
+		mcpServers[group.Name] = mcpServer
 	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mcpServers = mcpServers
 	return nil
 }
 
+// recordChange notifies the other mcpjungle servers that share the database that the tool groups have changed.
+// Failing to do so doesn't fail the change, which has already been made.
+func (s *ToolGroupService) recordChange() {
+	if err := changes.Record(s.db, model.RegistryScopeToolGroups); err != nil {
+		slog.Error("other servers may not see the change to the tool groups until they restart", logging.KeyError, err)
+	}
+}
+
 // handleToolDeletion is a callback that is called when one or more tools is deleted or disabled.
 // It removes the tools from all tool group MCP proxy servers.
 func (s *ToolGroupService) handleToolDeletion(tools ...string) {
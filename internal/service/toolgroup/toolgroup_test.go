@@ -0,0 +1,262 @@
+package toolgroup
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/changes"
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/internal/service/mcp"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// replica is an mcpjungle server that shares its database with others.
+type replica struct {
+	mcpService       *mcp.MCPService
+	toolGroupService *ToolGroupService
+	watcher          *changes.Watcher
+}
+
+func newReplica(t *testing.T, db *gorm.DB) *replica {
+	t.Helper()
+	watcher, err := changes.NewWatcher(db)
+	if err != nil {
+		t.Fatalf("failed to create watcher: %v", err)
+	}
+	mcpService, err := mcp.NewMCPService(db, server.NewMCPServer("test", "0.1.0"))
+	if err != nil {
+		t.Fatalf("failed to create MCP service: %v", err)
+	}
+	toolGroupService, err := NewToolGroupService(db, mcpService)
+	if err != nil {
+		t.Fatalf("failed to create tool group service: %v", err)
+	}
+	watcher.OnChange(model.RegistryScopeTools, mcpService.Reload)
+	watcher.OnChange(model.RegistryScopeToolGroups, toolGroupService.Reload)
+	return &replica{mcpService: mcpService, toolGroupService: toolGroupService, watcher: watcher}
+}
+
+func (r *replica) poll(t *testing.T) {
+	t.Helper()
+	if err := r.watcher.Poll(); err != nil {
+		t.Fatalf("failed to reload changes: %v", err)
+	}
+}
+
+// groupTools returns the tools listed by the MCP proxy server of a group, or false if the group doesn't exist.
+func (r *replica) groupTools(name string) (string, bool) {
+	s, ok := r.toolGroupService.GetToolGroupMCPServer(name)
+	if !ok {
+		return "", false
+	}
+	resp, _ := json.Marshal(s.HandleMessage(
+		context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`),
+	))
+	return string(resp), true
+}
+
+// openCircuit calls a tool of an unreachable server until the server's circuit breaker opens.
+func (r *replica) openCircuit(t *testing.T, tool string) {
+	t.Helper()
+	server := mcp.ServerOfTool(tool)
+	for i := 0; i < 10; i++ {
+		if r.mcpService.GetServerStatus(server).CircuitBreaker == types.CircuitOpen {
+			return
+		}
+		if _, err := r.mcpService.InvokeTool(context.Background(), tool, map[string]any{}); err == nil {
+			t.Fatalf("call to %s succeeded, want it to fail", tool)
+		}
+	}
+	t.Fatalf("circuit of %s is %s, want it open", server, r.mcpService.GetServerStatus(server).CircuitBreaker)
+}
+
+// createGithubServer registers an unreachable github server with 2 tools directly in the database,
+// since registering it through the service requires connecting to it.
+func createGithubServer(t *testing.T, db *gorm.DB) *model.McpServer {
+	t.Helper()
+	s, err := model.NewStreamableHTTPServer("github", "", "http://localhost:9999/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	for _, name := range []string{"create_issue", "delete_repo"} {
+		tool := &model.Tool{Name: name, ServerID: s.ID, InputSchema: datatypes.JSON(`{"type":"object"}`)}
+		if err := db.Create(tool).Error; err != nil {
+			t.Fatalf("failed to create tool: %v", err)
+		}
+	}
+	return s
+}
+
+func TestChangesPropagateBetweenReplicas(t *testing.T) {
+	db := dbtest.New(t)
+	s := createGithubServer(t, db)
+
+	a := newReplica(t, db)
+	b := newReplica(t, db)
+
+	// disabling a tool on one replica removes it from the other one once it has polled for changes
+	if _, err := a.mcpService.DisableTools("github__delete_repo"); err != nil {
+		t.Fatalf("failed to disable tool: %v", err)
+	}
+	if _, ok := b.mcpService.GetToolInstance("github__delete_repo"); !ok {
+		t.Fatal("expected the tool to be served until the replica polls for changes")
+	}
+	b.poll(t)
+	if _, ok := b.mcpService.GetToolInstance("github__delete_repo"); ok {
+		t.Error("expected the disabled tool to be removed from the other replica")
+	}
+
+	// a group created on one replica is served by the other one
+	group := &model.ToolGroup{Name: "triage", IncludedTools: datatypes.JSON(`["github__create_issue"]`)}
+	if err := a.toolGroupService.CreateToolGroup(group); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	b.poll(t)
+	if tools, ok := b.groupTools("triage"); !ok || !strings.Contains(tools, "github__create_issue") {
+		t.Fatalf("expected the group to be served by the other replica, got %q, %t", tools, ok)
+	}
+
+	// disabling & enabling a tool also changes the groups that include it
+	if _, err := a.mcpService.DisableTools("github__create_issue"); err != nil {
+		t.Fatalf("failed to disable tool: %v", err)
+	}
+	b.poll(t)
+	if tools, _ := b.groupTools("triage"); strings.Contains(tools, "github__create_issue") {
+		t.Errorf("expected the disabled tool to be removed from the group, got %s", tools)
+	}
+	if _, err := a.mcpService.EnableTools("github"); err != nil {
+		t.Fatalf("failed to enable tools: %v", err)
+	}
+	b.poll(t)
+	if tools, _ := b.groupTools("triage"); !strings.Contains(tools, "github__create_issue") {
+		t.Errorf("expected the enabled tool to be added back to the group, got %s", tools)
+	}
+	if _, ok := b.mcpService.GetToolInstance("github__delete_repo"); !ok {
+		t.Error("expected the enabled tool to be served by the other replica")
+	}
+
+	// the runtime state of a server is reset when another replica changes its connection
+	b.openCircuit(t, "github__create_issue")
+	moved := datatypes.JSON(`{"url":"http://localhost:9998/mcp"}`)
+	if err := db.Model(s).Update("config", moved).Error; err != nil {
+		t.Fatalf("failed to update server: %v", err)
+	}
+	if err := changes.Record(db, model.RegistryScopeTools); err != nil {
+		t.Fatalf("failed to record change: %v", err)
+	}
+	b.poll(t)
+	if state := b.mcpService.GetServerStatus("github").CircuitBreaker; state != types.CircuitClosed {
+		t.Errorf("circuit is %s after the server was moved by the other replica, want it closed", state)
+	}
+
+	// changes flow in both directions
+	a.openCircuit(t, "github__create_issue")
+	if err := b.toolGroupService.DeleteToolGroup("triage"); err != nil {
+		t.Fatalf("failed to delete group: %v", err)
+	}
+	if err := b.mcpService.DeregisterMcpServer("github"); err != nil {
+		t.Fatalf("failed to deregister server: %v", err)
+	}
+	a.poll(t)
+	if _, ok := a.groupTools("triage"); ok {
+		t.Error("expected the deleted group to be removed from the other replica")
+	}
+	for _, name := range []string{"github__create_issue", "github__delete_repo"} {
+		if _, ok := a.mcpService.GetToolInstance(name); ok {
+			t.Errorf("expected %s of the deregistered server to be removed from the other replica", name)
+		}
+	}
+	if state := a.mcpService.GetServerStatus("github").CircuitBreaker; state != types.CircuitClosed {
+		t.Errorf("circuit is %s after the server was deregistered by the other replica, want it closed", state)
+	}
+
+	// a server that is deregistered and registered again by another replica starts from scratch
+	createGithubServer(t, db)
+	if err := changes.Record(db, model.RegistryScopeTools); err != nil {
+		t.Fatalf("failed to record change: %v", err)
+	}
+	a.poll(t)
+	b.poll(t)
+	a.openCircuit(t, "github__create_issue")
+	if err := b.mcpService.DeregisterMcpServer("github"); err != nil {
+		t.Fatalf("failed to deregister server: %v", err)
+	}
+	createGithubServer(t, db)
+	if err := changes.Record(db, model.RegistryScopeTools); err != nil {
+		t.Fatalf("failed to record change: %v", err)
+	}
+	a.poll(t)
+	if state := a.mcpService.GetServerStatus("github").CircuitBreaker; state != types.CircuitClosed {
+		t.Errorf("circuit is %s after the server was registered again by the other replica, want it closed", state)
+	}
+	if _, ok := a.mcpService.GetToolInstance("github__create_issue"); !ok {
+		t.Error("expected the tools of the registered server to be served by the other replica")
+	}
+}
+
+// eventRecorder is an events.Publisher that records the events published to it.
+type eventRecorder struct {
+	events []types.Event
+}
+
+func (r *eventRecorder) Publish(t types.EventType, data map[string]any) {
+	r.events = append(r.events, types.Event{Type: t, Data: data})
+}
+
+func TestToolGroupEvents(t *testing.T) {
+	db := dbtest.New(t)
+	s, err := model.NewStreamableHTTPServer("github", "", "http://localhost:9999/mcp", "")
+	if err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	if err := db.Create(s).Error; err != nil {
+		t.Fatalf("failed to create server: %v", err)
+	}
+	for _, name := range []string{"create_issue", "delete_repo"} {
+		tool := &model.Tool{Name: name, ServerID: s.ID, InputSchema: datatypes.JSON(`{"type":"object"}`)}
+		if err := db.Create(tool).Error; err != nil {
+			t.Fatalf("failed to create tool: %v", err)
+		}
+	}
+	r := newReplica(t, db)
+	events := &eventRecorder{}
+	r.toolGroupService.SetEventPublisher(events)
+
+	group := &model.ToolGroup{Name: "triage", IncludedTools: datatypes.JSON(`["github__create_issue"]`)}
+	if err := r.toolGroupService.CreateToolGroup(group); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	update := &model.ToolGroup{Name: "triage", IncludedTools: datatypes.JSON(`["github__missing"]`)}
+	if err := r.toolGroupService.UpdateToolGroup(update); err == nil {
+		t.Fatal("UpdateToolGroup() error = nil for a group with a missing tool, want error")
+	}
+	update.IncludedTools = datatypes.JSON(`["github__create_issue","github__delete_repo"]`)
+	if err := r.toolGroupService.UpdateToolGroup(update); err != nil {
+		t.Fatalf("UpdateToolGroup() error = %v", err)
+	}
+	if err := r.toolGroupService.DeleteToolGroup("triage"); err != nil {
+		t.Fatalf("failed to delete group: %v", err)
+	}
+
+	want := []types.EventType{types.EventToolGroupCreated, types.EventToolGroupUpdated, types.EventToolGroupDeleted}
+	if len(events.events) != len(want) {
+		t.Fatalf("published %d events, want %v", len(events.events), want)
+	}
+	for i, e := range events.events {
+		if e.Type != want[i] || e.Data["group"] != "triage" {
+			t.Errorf("event %d = %+v, want %s of group triage", i, e, want[i])
+		}
+	}
+	if tools, _ := events.events[1].Data["tools"].([]string); len(tools) != 2 {
+		t.Errorf("tool_group.updated has tools %v, want the 2 tools of the updated group", events.events[1].Data["tools"])
+	}
+}
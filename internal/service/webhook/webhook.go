@@ -0,0 +1,332 @@
+// Package webhook delivers the events published by mcpjungle to HTTP endpoints configured by admins.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/gorm"
+)
+
+// Headers sent along with every webhook payload.
+const (
+	HeaderEvent    = "X-MCPJungle-Event"
+	HeaderDelivery = "X-MCPJungle-Delivery"
+	// HeaderSignature carries the hex-encoded HMAC-SHA256 of the payload, keyed with the webhook's secret,
+	// prefixed with "sha256=".
+	HeaderSignature = "X-MCPJungle-Signature-256"
+)
+
+const (
+	// maxAttempts is the number of times delivering an event to a webhook is attempted before giving up
+	maxAttempts = 5
+	// initialBackoff is the delay before the first retry, doubled for every subsequent retry
+	initialBackoff = 2 * time.Second
+	// requestTimeout is the maximum time a webhook may take to respond
+	requestTimeout = 10 * time.Second
+
+	// defaultMaxDeliveries is the number of most recent deliveries retained in the delivery log of every webhook
+	defaultMaxDeliveries = 1000
+	// pendingBufferSize is the number of events that can wait to be dispatched to webhooks.
+	// Events are dropped beyond this, so that publishing an event never blocks.
+	pendingBufferSize = 1000
+)
+
+var (
+	// ErrWebhookNotFound is returned when a webhook does not exist.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrWebhookExists is returned when creating a webhook with the name of an existing one.
+	ErrWebhookExists = errors.New("webhook already exists")
+)
+
+// WebhookService manages webhooks and delivers events to them.
+type WebhookService struct {
+	db         *gorm.DB
+	httpClient *http.Client
+
+	// backoff is the delay before the first retry of a failed delivery
+	backoff time.Duration
+	// maxDeliveries is the number of most recent deliveries retained in the delivery log of every webhook
+	maxDeliveries int
+
+	pending chan *types.Event
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{
+		db:            db,
+		httpClient:    &http.Client{Timeout: requestTimeout},
+		backoff:       initialBackoff,
+		maxDeliveries: defaultMaxDeliveries,
+		pending:       make(chan *types.Event, pendingBufferSize),
+	}
+}
+
+// CreateWebhook creates a new webhook.
+// If the webhook has no secret, a random one is generated.
+func (s *WebhookService) CreateWebhook(w *model.Webhook) error {
+	if err := w.Validate(); err != nil {
+		return err
+	}
+	if w.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		w.Secret = secret
+	}
+	if _, err := s.GetWebhook(w.Name); err == nil {
+		return fmt.Errorf("%w: %s", ErrWebhookExists, w.Name)
+	} else if !errors.Is(err, ErrWebhookNotFound) {
+		return err
+	}
+	if err := s.db.Create(w).Error; err != nil {
+		// a webhook with the same name may have been created concurrently, in which case the unique index rejects this one
+		if _, getErr := s.GetWebhook(w.Name); getErr == nil {
+			return fmt.Errorf("%w: %s", ErrWebhookExists, w.Name)
+		}
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// ListWebhooks returns all webhooks.
+func (s *WebhookService) ListWebhooks() ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	if err := s.db.Order("name").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+// GetWebhook returns a webhook by name.
+func (s *WebhookService) GetWebhook(name string) (*model.Webhook, error) {
+	var w model.Webhook
+	if err := s.db.Where("name = ?", name).First(&w).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &w, nil
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log.
+// Deliveries that are still being retried are abandoned.
+func (s *WebhookService) DeleteWebhook(name string) error {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return err
+	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Delete(w).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook: %w", err)
+		}
+		if err := tx.Where("webhook_id = ?", w.ID).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListDeliveries returns the most recent deliveries of events to a webhook, newest first.
+func (s *WebhookService) ListDeliveries(name string, limit int) ([]model.WebhookDelivery, error) {
+	w, err := s.GetWebhook(name)
+	if err != nil {
+		return nil, err
+	}
+	q := s.db.Where("webhook_id = ?", w.ID).Order("id DESC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	var deliveries []model.WebhookDelivery
+	if err := q.Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// HandleEvent queues an event to be delivered to all webhooks subscribed to it.
+// It never blocks, if too many events are waiting to be dispatched, the event is dropped.
+func (s *WebhookService) HandleEvent(e *types.Event) {
+	select {
+	case s.pending <- e:
+	default:
+		slog.Warn("too many events waiting to be sent to webhooks, dropping event", "event_type", e.Type)
+	}
+}
+
+// Start dispatches the queued events to webhooks in the background until ctx is cancelled.
+func (s *WebhookService) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e := <-s.pending:
+				s.dispatch(ctx, e)
+			}
+		}
+	}()
+}
+
+// dispatch starts delivering an event to every webhook subscribed to its type.
+func (s *WebhookService) dispatch(ctx context.Context, e *types.Event) {
+	webhooks, err := s.ListWebhooks()
+	if err != nil {
+		slog.Error("failed to list webhooks", logging.KeyError, err)
+		return
+	}
+	var payload []byte
+	for i := range webhooks {
+		w := &webhooks[i]
+		if !w.Subscribed(e.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(e); err != nil {
+				slog.Error("failed to serialize event", "event_type", e.Type, logging.KeyError, err)
+				return
+			}
+		}
+		d := &model.WebhookDelivery{
+			WebhookID: w.ID,
+			EventID:   e.ID,
+			EventType: e.Type,
+			Status:    types.WebhookDeliveryPending,
+		}
+		if err := s.createDelivery(d); err != nil {
+			slog.Error("failed to record webhook delivery", "webhook", w.Name, logging.KeyError, err)
+			continue
+		}
+		go s.deliver(ctx, w, d, payload)
+	}
+}
+
+// createDelivery adds a delivery to the log of its webhook and removes the deliveries that no longer fit in it.
+// Every webhook keeps its own most recent deliveries, so a busy webhook can't push out the log of a quiet one.
+func (s *WebhookService) createDelivery(d *model.WebhookDelivery) error {
+	if err := s.db.Create(d).Error; err != nil {
+		return err
+	}
+	// IDs increase monotonically, so the ID of the newest delivery that no longer fits
+	// marks everything that can be deleted
+	var ids []uint
+	err := s.db.Model(&model.WebhookDelivery{}).
+		Where("webhook_id = ?", d.WebhookID).
+		Order("id DESC").
+		Offset(s.maxDeliveries).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.Where("webhook_id = ? AND id <= ?", d.WebhookID, ids[0]).Delete(&model.WebhookDelivery{}).Error
+}
+
+// deliver sends the payload of an event to a webhook, retrying with exponential backoff until it is accepted,
+// the webhook rejects it permanently or all attempts are exhausted.
+// Every attempt is recorded in the delivery log.
+func (s *WebhookService) deliver(ctx context.Context, w *model.Webhook, d *model.WebhookDelivery, payload []byte) {
+	logger := slog.With("webhook", w.Name, "event_type", d.EventType, "delivery", d.ID)
+	delay := s.backoff
+	for {
+		d.Attempts++
+		status, retryable, err := s.send(ctx, w, d, payload)
+		d.StatusCode = status
+		d.Error = ""
+		switch {
+		case err == nil:
+			d.Status = types.WebhookDeliveryDelivered
+		case !retryable || d.Attempts >= maxAttempts:
+			d.Status = types.WebhookDeliveryFailed
+			d.Error = err.Error()
+			logger.Warn("failed to deliver event to webhook", "attempts", d.Attempts, logging.KeyError, err)
+		default:
+			d.Error = err.Error()
+		}
+		// the delivery is only updated, so that it isn't recreated if the webhook was deleted in the meantime
+		err = s.db.Model(d).Select("Status", "Attempts", "StatusCode", "Error").Updates(d).Error
+		if err != nil {
+			logger.Error("failed to update webhook delivery", logging.KeyError, err)
+		}
+		if d.Status != types.WebhookDeliveryPending {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// send makes a single attempt to deliver the payload to the webhook.
+// It returns the HTTP status code of the response (0 if there was none) and,
+// if the attempt failed, whether it is worth retrying.
+func (s *WebhookService) send(
+	ctx context.Context, w *model.Webhook, d *model.WebhookDelivery, payload []byte,
+) (int, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mcpjungle-webhook")
+	req.Header.Set(HeaderEvent, string(d.EventType))
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(d.ID), 10))
+	req.Header.Set(HeaderSignature, Sign(w.Secret, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, true, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, false, nil
+	}
+	// server errors and throttling are usually transient, other client errors won't go away by retrying
+	retryable := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == http.StatusRequestTimeout
+	return resp.StatusCode, retryable, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+}
+
+// Sign returns the signature of a payload sent with the given secret, as sent in the HeaderSignature header.
+// Receivers should compute the same signature over the raw request body and compare them in constant time.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateSecret returns a random secret for signing webhook payloads.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
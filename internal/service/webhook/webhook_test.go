@@ -0,0 +1,234 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"github.com/mcpjungle/mcpjungle/pkg/types"
+	"gorm.io/datatypes"
+)
+
+func newTestService(t *testing.T) *WebhookService {
+	t.Helper()
+	db := dbtest.New(t)
+	s := NewWebhookService(db)
+	s.backoff = time.Millisecond
+	return s
+}
+
+// waitForDelivery waits until the only delivery of the given webhook is no longer pending and returns it.
+func waitForDelivery(t *testing.T, s *WebhookService, name string) *model.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		deliveries, err := s.ListDeliveries(name, 0)
+		if err != nil {
+			t.Fatalf("ListDeliveries() error = %v", err)
+		}
+		if len(deliveries) == 1 && deliveries[0].Status != types.WebhookDeliveryPending {
+			return &deliveries[0]
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for the webhook delivery to complete")
+	return nil
+}
+
+func TestDeliverSignedPayload(t *testing.T) {
+	s := newTestService(t)
+
+	var received atomic.Value
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(HeaderSignature) != Sign("s3cret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		received.Store(body)
+	}))
+	defer receiver.Close()
+
+	subscribed := &model.Webhook{
+		Name:       "ops",
+		URL:        receiver.URL,
+		Secret:     "s3cret",
+		EventTypes: datatypes.JSON(`["server.unhealthy"]`),
+	}
+	other := &model.Webhook{
+		Name:       "audit",
+		URL:        receiver.URL,
+		EventTypes: datatypes.JSON(`["tool_group.created"]`),
+	}
+	for _, w := range []*model.Webhook{subscribed, other} {
+		if err := s.CreateWebhook(w); err != nil {
+			t.Fatalf("CreateWebhook() error = %v", err)
+		}
+	}
+	if other.Secret == "" {
+		t.Error("CreateWebhook() did not generate a secret")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.Start(ctx)
+	s.HandleEvent(&types.Event{ID: "1", Type: types.EventServerUnhealthy, Data: map[string]any{"server": "docs"}})
+
+	d := waitForDelivery(t, s, "ops")
+	if d.Status != types.WebhookDeliveryDelivered || d.Attempts != 1 {
+		t.Fatalf("delivery = %+v, want delivered at first attempt", d)
+	}
+	var e types.Event
+	if err := json.Unmarshal(received.Load().([]byte), &e); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if e.Type != types.EventServerUnhealthy || e.Data["server"] != "docs" {
+		t.Errorf("payload = %+v, want the server.unhealthy event", e)
+	}
+
+	if deliveries, _ := s.ListDeliveries("audit", 0); len(deliveries) != 0 {
+		t.Errorf("event was delivered to a webhook that isn't subscribed to it: %+v", deliveries)
+	}
+}
+
+func TestDeliveryRetries(t *testing.T) {
+	testCases := []struct {
+		name         string
+		statuses     []int
+		wantStatus   types.WebhookDeliveryStatus
+		wantAttempts int
+	}{
+		{
+			name:         "retries server errors",
+			statuses:     []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			wantStatus:   types.WebhookDeliveryDelivered,
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{http.StatusInternalServerError},
+			wantStatus:   types.WebhookDeliveryFailed,
+			wantAttempts: maxAttempts,
+		},
+		{
+			name:         "does not retry client errors",
+			statuses:     []int{http.StatusNotFound},
+			wantStatus:   types.WebhookDeliveryFailed,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestService(t)
+			var calls atomic.Int32
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(calls.Add(1))
+				w.WriteHeader(tc.statuses[min(n, len(tc.statuses))-1])
+			}))
+			defer receiver.Close()
+
+			if err := s.CreateWebhook(&model.Webhook{Name: "ops", URL: receiver.URL}); err != nil {
+				t.Fatalf("CreateWebhook() error = %v", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			s.Start(ctx)
+			s.HandleEvent(&types.Event{ID: "1", Type: types.EventToolAdded})
+
+			d := waitForDelivery(t, s, "ops")
+			if d.Status != tc.wantStatus || d.Attempts != tc.wantAttempts {
+				t.Errorf("delivery = %+v, want status %s after %d attempts", d, tc.wantStatus, tc.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestWebhookValidation(t *testing.T) {
+	s := newTestService(t)
+	invalid := []*model.Webhook{
+		{Name: "", URL: "https://example.com"},
+		{Name: "ops hook", URL: "https://example.com"},
+		{Name: "ops", URL: "example.com/hook"},
+		{Name: "ops", URL: "https://example.com", EventTypes: datatypes.JSON(`["server.exploded"]`)},
+	}
+	for _, w := range invalid {
+		if err := s.CreateWebhook(w); err == nil {
+			t.Errorf("CreateWebhook(%+v) succeeded, want a validation error", w)
+		}
+	}
+}
+
+func TestCreateDuplicateWebhook(t *testing.T) {
+	s := newTestService(t)
+	if err := s.CreateWebhook(&model.Webhook{Name: "ops", URL: "https://example.com"}); err != nil {
+		t.Fatalf("CreateWebhook() error = %v", err)
+	}
+	err := s.CreateWebhook(&model.Webhook{Name: "ops", URL: "https://example.org"})
+	if !errors.Is(err, ErrWebhookExists) {
+		t.Fatalf("CreateWebhook() error = %v, want ErrWebhookExists", err)
+	}
+	w, err := s.GetWebhook("ops")
+	if err != nil {
+		t.Fatalf("GetWebhook() error = %v", err)
+	}
+	if w.URL != "https://example.com" {
+		t.Errorf("webhook URL = %s, want the existing webhook to be unchanged", w.URL)
+	}
+}
+
+func TestDeliveryLogIsPrunedPerWebhook(t *testing.T) {
+	s := newTestService(t)
+	s.maxDeliveries = 3
+	busy := &model.Webhook{Name: "busy", URL: "https://example.com"}
+	quiet := &model.Webhook{Name: "quiet", URL: "https://example.com"}
+	for _, w := range []*model.Webhook{busy, quiet} {
+		if err := s.CreateWebhook(w); err != nil {
+			t.Fatalf("CreateWebhook() error = %v", err)
+		}
+	}
+
+	record := func(w *model.Webhook, eventID string) {
+		d := &model.WebhookDelivery{
+			WebhookID: w.ID, EventID: eventID, EventType: types.EventToolAdded, Status: types.WebhookDeliveryDelivered,
+		}
+		if err := s.createDelivery(d); err != nil {
+			t.Fatalf("createDelivery() error = %v", err)
+		}
+	}
+	record(quiet, "q1")
+	for i := range 10 {
+		record(busy, fmt.Sprintf("b%d", i))
+	}
+
+	deliveries, err := s.ListDeliveries("busy", 0)
+	if err != nil {
+		t.Fatalf("ListDeliveries() error = %v", err)
+	}
+	var got []string
+	for _, d := range deliveries {
+		got = append(got, d.EventID)
+	}
+	if want := []string{"b9", "b8", "b7"}; !slices.Equal(got, want) {
+		t.Errorf("deliveries of busy webhook = %v, want %v", got, want)
+	}
+
+	// the deliveries of other webhooks are kept
+	deliveries, err = s.ListDeliveries("quiet", 0)
+	if err != nil {
+		t.Fatalf("ListDeliveries() error = %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].EventID != "q1" {
+		t.Errorf("deliveries of quiet webhook = %+v, want the only one to be kept", deliveries)
+	}
+}
@@ -0,0 +1,94 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+	"gorm.io/gorm"
+)
+
+// DBStore keeps sessions in the database, so that they are shared by all servers using it.
+type DBStore struct {
+	db *gorm.DB
+}
+
+func NewDBStore(db *gorm.DB) *DBStore {
+	return &DBStore{db: db}
+}
+
+func (d *DBStore) Create(s *model.McpSession) error {
+	return d.db.Create(s).Error
+}
+
+func (d *DBStore) Get(id string) (*model.McpSession, error) {
+	var s model.McpSession
+	if err := d.db.Where("id = ?", id).First(&s).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (d *DBStore) Touch(id string, t time.Time) error {
+	result := d.db.Model(&model.McpSession{}).Where("id = ?", id).Update("last_used_at", t)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (d *DBStore) Delete(id string) error {
+	result := d.db.Where("id = ?", id).Delete(&model.McpSession{})
+	if result.Error != nil {
+		return result.Error
+	}
+	// only the server whose delete succeeds reports the session as removed
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (d *DBStore) DeleteIdle(before time.Time) ([]model.McpSession, error) {
+	var idle []model.McpSession
+	if err := d.db.Where("last_used_at < ?", before).Find(&idle).Error; err != nil {
+		return nil, fmt.Errorf("failed to get idle sessions: %w", err)
+	}
+	// every server expires idle sessions, so a session is only returned by the server that deleted it
+	deleted := idle[:0]
+	for _, s := range idle {
+		result := d.db.Where("id = ? AND last_used_at < ?", s.ID, before).Delete(&model.McpSession{})
+		if result.Error != nil {
+			return deleted, fmt.Errorf("failed to delete idle session: %w", result.Error)
+		}
+		if result.RowsAffected > 0 {
+			deleted = append(deleted, s)
+		}
+	}
+	return deleted, nil
+}
+
+// existingBatchSize is the maximum number of session IDs looked up in a single query
+const existingBatchSize = 500
+
+func (d *DBStore) Existing(ids []string) ([]string, error) {
+	var existing []string
+	for start := 0; start < len(ids); start += existingBatchSize {
+		var batch []string
+		err := d.db.Model(&model.McpSession{}).
+			Where("id IN ?", ids[start:min(start+existingBatchSize, len(ids))]).
+			Pluck("id", &batch).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sessions: %w", err)
+		}
+		existing = append(existing, batch...)
+	}
+	return existing, nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// MemoryStore keeps sessions in memory. They are lost when the server restarts.
+type MemoryStore struct {
+	sessions map[string]model.McpSession
+	mu       sync.Mutex
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{sessions: make(map[string]model.McpSession)}
+}
+
+func (m *MemoryStore) Create(s *model.McpSession) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[s.ID] = *s
+	return nil
+}
+
+func (m *MemoryStore) Get(id string) (*model.McpSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &s, nil
+}
+
+func (m *MemoryStore) Touch(id string, t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	s.LastUsedAt = t
+	m.sessions[id] = s
+	return nil
+}
+
+func (m *MemoryStore) Delete(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.sessions, id)
+	return nil
+}
+
+func (m *MemoryStore) DeleteIdle(before time.Time) ([]model.McpSession, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var idle []model.McpSession
+	for id, s := range m.sessions {
+		if s.LastUsedAt.Before(before) {
+			idle = append(idle, s)
+			delete(m.sessions, id)
+		}
+	}
+	return idle, nil
+}
+
+func (m *MemoryStore) Existing(ids []string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var existing []string
+	for _, id := range ids {
+		if _, ok := m.sessions[id]; ok {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,258 @@
+// Package session manages the MCP sessions that clients open on mcpjungle's proxy endpoints.
+//
+// Sessions are kept in a Store. The in-memory store is enough for a single server,
+// while the database store lets several servers sharing a database behind a load balancer
+// serve requests made in a session opened on any of them.
+// Alternatively, the proxy can be run without sessions at all.
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/mcpjungle/mcpjungle/internal/logging"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/mcpjungle/mcpjungle/internal/model"
+)
+
+// ErrNotFound is returned by a Store if the requested session does not exist.
+var ErrNotFound = errors.New("session not found")
+
+// touchInterval is the minimum time between two updates of the last use of a session.
+const touchInterval = time.Minute
+
+// expiryInterval is the time between two checks for idle sessions.
+const expiryInterval = time.Minute
+
+// StoreType identifies where sessions are stored.
+type StoreType string
+
+const (
+	// StoreMemory keeps sessions in the memory of the server, so they are only valid on the server that created them
+	StoreMemory StoreType = "memory"
+	// StoreDatabase keeps sessions in the database, so they are valid on all servers sharing it
+	StoreDatabase StoreType = "database"
+	// StoreStateless disables sessions, so that every request is handled on its own
+	StoreStateless StoreType = "stateless"
+)
+
+// Store persists MCP sessions.
+type Store interface {
+	// Create stores a new session.
+	Create(s *model.McpSession) error
+	// Get returns the session with the given ID or ErrNotFound.
+	Get(id string) (*model.McpSession, error)
+	// Touch sets the last use of a session.
+	Touch(id string, t time.Time) error
+	// Delete removes a session. It returns ErrNotFound if the session didn't exist.
+	Delete(id string) error
+	// DeleteIdle removes all sessions last used before the given time and returns them.
+	DeleteIdle(before time.Time) ([]model.McpSession, error)
+	// Existing returns the IDs of the given sessions that still exist.
+	Existing(ids []string) ([]string, error)
+}
+
+// Manager creates and validates the sessions of all proxy endpoints.
+type Manager struct {
+	// store is nil if sessions are disabled
+	store Store
+	// idleTimeout is the time after which an unused session expires, sessions never expire if it is zero
+	idleTimeout time.Duration
+
+	// opened holds the endpoints of the active sessions opened on this server, keyed by session ID.
+	// The active sessions metric is derived from it, so that every server only counts the sessions it opened,
+	// even though sessions in the database store may be terminated or expire on another server.
+	opened map[string]string
+	// active is the number of sessions in opened per endpoint
+	active map[string]int
+	mu     sync.Mutex
+}
+
+// NewManager creates a Manager that keeps sessions in the given store.
+// If the store is nil, the proxy endpoints are stateless: no session IDs are issued and none are required.
+func NewManager(store Store, idleTimeout time.Duration) *Manager {
+	return &Manager{
+		store:       store,
+		idleTimeout: idleTimeout,
+		opened:      make(map[string]string),
+		active:      make(map[string]int),
+	}
+}
+
+// ForEndpoint returns a session ID manager for the streamable HTTP server serving the given endpoint.
+// A session is only valid on the endpoint it was opened on.
+func (m *Manager) ForEndpoint(endpoint string) server.SessionIdManager {
+	if m.store == nil {
+		return &server.StatelessSessionIdManager{}
+	}
+	return &storedSessionIdManager{manager: m, endpoint: endpoint}
+}
+
+// Start expires idle sessions in the background and keeps track of the sessions opened on this server
+// that were closed by another one. It stops when ctx is done.
+func (m *Manager) Start(ctx context.Context) {
+	if m.store == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(expiryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if m.idleTimeout > 0 {
+				if err := m.expireIdle(time.Now()); err != nil {
+					slog.Error("failed to expire idle MCP sessions", logging.KeyError, err)
+				}
+			}
+			if err := m.forgetClosed(); err != nil {
+				slog.Error("failed to check for closed MCP sessions", logging.KeyError, err)
+			}
+		}
+	}()
+}
+
+// expireIdle removes the sessions that haven't been used within the idle timeout.
+func (m *Manager) expireIdle(now time.Time) error {
+	expired, err := m.store.DeleteIdle(now.Add(-m.idleTimeout))
+	if err != nil {
+		return err
+	}
+	for _, s := range expired {
+		m.trackClosed(s.ID)
+	}
+	if len(expired) > 0 {
+		slog.Debug("expired idle MCP sessions", "count", len(expired))
+	}
+	return nil
+}
+
+// forgetClosed stops counting the sessions opened on this server that no longer exist in the store,
+// eg- because another server sharing the database terminated or expired them.
+func (m *Manager) forgetClosed() error {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.opened))
+	for id := range m.opened {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	existing, err := m.store.Existing(ids)
+	if err != nil {
+		return err
+	}
+	exists := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		exists[id] = true
+	}
+	for _, id := range ids {
+		if !exists[id] {
+			m.trackClosed(id)
+		}
+	}
+	return nil
+}
+
+// trackOpened records that a session was opened on this server.
+func (m *Manager) trackOpened(id, endpoint string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.opened[id] = endpoint
+	m.active[endpoint]++
+	metrics.SetActiveSessions(endpoint, m.active[endpoint])
+}
+
+// trackClosed records that a session was terminated or has expired.
+// Sessions that were not opened on this server, or were already closed, are ignored.
+func (m *Manager) trackClosed(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	endpoint, ok := m.opened[id]
+	if !ok {
+		return
+	}
+	delete(m.opened, id)
+	m.active[endpoint]--
+	metrics.SetActiveSessions(endpoint, m.active[endpoint])
+}
+
+// storedSessionIdManager issues session IDs in the format of mcp-go's default session ID manager
+// and keeps track of the sessions in the manager's store.
+type storedSessionIdManager struct {
+	server.InsecureStatefulSessionIdManager
+
+	manager  *Manager
+	endpoint string
+}
+
+func (m *storedSessionIdManager) Generate() string {
+	id := m.InsecureStatefulSessionIdManager.Generate()
+	now := time.Now()
+	err := m.manager.store.Create(&model.McpSession{ID: id, Endpoint: m.endpoint, CreatedAt: now, LastUsedAt: now})
+	if err != nil {
+		// the client is told to start a new session on its next request
+		slog.Error("failed to store MCP session", "endpoint", m.endpoint, logging.KeyError, err)
+		return id
+	}
+	m.manager.trackOpened(id, m.endpoint)
+	return id
+}
+
+// Validate reports a session that doesn't exist as terminated, so that the client is
+// told to start a new session rather than that its request is invalid.
+func (m *storedSessionIdManager) Validate(sessionID string) (isTerminated bool, err error) {
+	if _, err := m.InsecureStatefulSessionIdManager.Validate(sessionID); err != nil {
+		return false, err
+	}
+	s, err := m.manager.store.Get(sessionID)
+	if errors.Is(err, ErrNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		slog.Error("failed to get MCP session", "endpoint", m.endpoint, logging.KeyError, err)
+		return false, fmt.Errorf("failed to get session: %w", err)
+	}
+	if s.Endpoint != m.endpoint {
+		return true, nil
+	}
+
+	if now := time.Now(); now.Sub(s.LastUsedAt) >= touchInterval {
+		if err := m.manager.store.Touch(sessionID, now); err != nil {
+			// the session remains valid, it may only expire earlier than it should
+			slog.Warn("failed to update last use of MCP session", "endpoint", m.endpoint, logging.KeyError, err)
+		}
+	}
+	return false, nil
+}
+
+func (m *storedSessionIdManager) Terminate(sessionID string) (isNotAllowed bool, err error) {
+	s, err := m.manager.store.Get(sessionID)
+	if errors.Is(err, ErrNotFound) || (err == nil && s.Endpoint != m.endpoint) {
+		// the session was already terminated or has expired
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	if err := m.manager.store.Delete(sessionID); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			// the session was terminated concurrently, eg- by another server
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to delete session: %w", err)
+	}
+	m.manager.trackClosed(sessionID)
+	return false, nil
+}
@@ -0,0 +1,196 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mcpjungle/mcpjungle/internal/db/dbtest"
+	"github.com/mcpjungle/mcpjungle/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// activeSessions returns the value of the active sessions metric of the given endpoint.
+func activeSessions(t *testing.T, reg *prometheus.Registry, endpoint string) float64 {
+	t.Helper()
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("failed to gather metrics: %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != "mcpjungle_mcp_active_sessions" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			if m.GetLabel()[0].GetValue() == endpoint {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestSessions(t *testing.T) {
+	db := dbtest.New(t)
+	stores := map[string]func() Store{
+		"memory":   func() Store { return NewMemoryStore() },
+		"database": func() Store { return NewDBStore(db) },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			m := NewManager(newStore(), time.Hour)
+			proxy := m.ForEndpoint("/mcp")
+			group := m.ForEndpoint("/v0/groups/triage/mcp")
+
+			id := proxy.Generate()
+			if terminated, err := proxy.Validate(id); err != nil || terminated {
+				t.Fatalf("expected a new session to be valid, got %t, %v", terminated, err)
+			}
+			if terminated, _ := group.Validate(id); !terminated {
+				t.Error("expected a session to only be valid on the endpoint it was opened on")
+			}
+			if _, err := proxy.Validate("not-a-session"); err == nil {
+				t.Error("expected a malformed session ID to be rejected")
+			}
+			// a session that the store doesn't know about, eg- because it has expired, must be started again
+			if terminated, _ := proxy.Validate(NewManager(newStore(), 0).ForEndpoint("/x").Generate()); !terminated {
+				t.Error("expected an unknown session to be reported as terminated")
+			}
+
+			if _, err := group.Terminate(id); err != nil {
+				t.Fatalf("failed to terminate session: %v", err)
+			}
+			if terminated, _ := proxy.Validate(id); terminated {
+				t.Error("expected a session not to be terminated through another endpoint")
+			}
+			if _, err := proxy.Terminate(id); err != nil {
+				t.Fatalf("failed to terminate session: %v", err)
+			}
+			if terminated, _ := proxy.Validate(id); !terminated {
+				t.Error("expected a terminated session to be invalid")
+			}
+			// terminating a session again is not an error
+			if _, err := proxy.Terminate(id); err != nil {
+				t.Errorf("expected terminating a session twice to succeed, got %v", err)
+			}
+
+			// sessions that haven't been used within the idle timeout expire
+			idle, active := proxy.Generate(), proxy.Generate()
+			if err := m.store.Touch(idle, time.Now().Add(-2*time.Hour)); err != nil {
+				t.Fatalf("failed to touch session: %v", err)
+			}
+			if err := m.expireIdle(time.Now()); err != nil {
+				t.Fatalf("failed to expire idle sessions: %v", err)
+			}
+			if terminated, _ := proxy.Validate(idle); !terminated {
+				t.Error("expected the idle session to expire")
+			}
+			if terminated, _ := proxy.Validate(active); terminated {
+				t.Error("expected the active session not to expire")
+			}
+		})
+	}
+}
+
+func TestSessionsSharedThroughDatabase(t *testing.T) {
+	db := dbtest.New(t)
+	a := NewManager(NewDBStore(db), time.Hour).ForEndpoint("/mcp")
+	b := NewManager(NewDBStore(db), time.Hour).ForEndpoint("/mcp")
+
+	id := a.Generate()
+	if terminated, err := b.Validate(id); err != nil || terminated {
+		t.Fatalf("expected a session opened on one server to be valid on another, got %t, %v", terminated, err)
+	}
+	if _, err := b.Terminate(id); err != nil {
+		t.Fatalf("failed to terminate session: %v", err)
+	}
+	if terminated, _ := a.Validate(id); !terminated {
+		t.Error("expected a session terminated on one server to be invalid on another")
+	}
+}
+
+func TestStatelessSessions(t *testing.T) {
+	m := NewManager(nil, time.Hour).ForEndpoint("/mcp")
+	if id := m.Generate(); id != "" {
+		t.Errorf("expected no session ID to be issued, got %q", id)
+	}
+	for _, id := range []string{"", "mcp-session-issued-by-another-server"} {
+		if terminated, err := m.Validate(id); err != nil || terminated {
+			t.Errorf("expected session ID %q to be accepted, got %t, %v", id, terminated, err)
+		}
+	}
+}
+
+func TestActiveSessionsMetric(t *testing.T) {
+	reg := metrics.NewTestRegistry()
+	m := NewManager(NewMemoryStore(), time.Hour)
+	proxy := m.ForEndpoint("/mcp")
+
+	first, second := proxy.Generate(), proxy.Generate()
+	if got := activeSessions(t, reg, "/mcp"); got != 2 {
+		t.Fatalf("active sessions = %v, want 2", got)
+	}
+	if _, err := proxy.Terminate(first); err != nil {
+		t.Fatalf("failed to terminate session: %v", err)
+	}
+	// terminating a session twice only counts once
+	if _, err := proxy.Terminate(first); err != nil {
+		t.Fatalf("failed to terminate session: %v", err)
+	}
+	if got := activeSessions(t, reg, "/mcp"); got != 1 {
+		t.Fatalf("active sessions = %v after termination, want 1", got)
+	}
+	if err := m.store.Touch(second, time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("failed to touch session: %v", err)
+	}
+	if err := m.expireIdle(time.Now()); err != nil {
+		t.Fatalf("failed to expire idle sessions: %v", err)
+	}
+	if got := activeSessions(t, reg, "/mcp"); got != 0 {
+		t.Fatalf("active sessions = %v after expiry, want 0", got)
+	}
+}
+
+func TestActiveSessionsMetricWithSharedDatabase(t *testing.T) {
+	reg := metrics.NewTestRegistry()
+	db := dbtest.New(t)
+	// both servers report the same metric since they run in the same process,
+	// so the metric shows the count of the server that changed it last
+	a := NewManager(NewDBStore(db), time.Hour)
+	b := NewManager(NewDBStore(db), time.Hour)
+	proxyA, proxyB := a.ForEndpoint("/mcp"), b.ForEndpoint("/mcp")
+
+	ids := []string{proxyA.Generate(), proxyA.Generate(), proxyA.Generate()}
+	proxyB.Generate()
+	proxyB.Generate()
+	if got := activeSessions(t, reg, "/mcp"); got != 2 {
+		t.Fatalf("active sessions of server b = %v, want 2", got)
+	}
+
+	// sessions opened on server a and closed on server b only count on server a
+	if _, err := proxyB.Terminate(ids[0]); err != nil {
+		t.Fatalf("failed to terminate session: %v", err)
+	}
+	if err := b.store.Touch(ids[1], time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatalf("failed to touch session: %v", err)
+	}
+	if err := b.expireIdle(time.Now()); err != nil {
+		t.Fatalf("failed to expire idle sessions: %v", err)
+	}
+	if got := activeSessions(t, reg, "/mcp"); got != 2 {
+		t.Fatalf("active sessions of server b = %v after closing sessions of server a, want 2", got)
+	}
+
+	// server a notices that they were closed
+	if err := a.forgetClosed(); err != nil {
+		t.Fatalf("failed to check for closed sessions: %v", err)
+	}
+	if got := activeSessions(t, reg, "/mcp"); got != 1 {
+		t.Fatalf("active sessions of server a = %v, want 1", got)
+	}
+	if _, err := proxyA.Terminate(ids[2]); err != nil {
+		t.Fatalf("failed to terminate session: %v", err)
+	}
+	if got := activeSessions(t, reg, "/mcp"); got != 0 {
+		t.Fatalf("active sessions of server a = %v, want 0", got)
+	}
+}
@@ -0,0 +1,100 @@
+// Package tracing provides OpenTelemetry tracing functionality for the MCPJungle server.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the default name mcpjungle reports itself as in traces.
+// It can be overridden with the standard OTEL_SERVICE_NAME environment variable.
+const ServiceName = "mcpjungle"
+
+const instrumentationName = "github.com/mcpjungle/mcpjungle"
+
+// Supported values for the trace exporter.
+const (
+	// ExporterNone disables exporting of traces.
+	// Trace context is still propagated to upstream MCP servers.
+	ExporterNone = "none"
+	// ExporterOTLP exports traces to an OpenTelemetry collector over OTLP/HTTP.
+	// The collector endpoint is configured using the standard OTEL_EXPORTER_OTLP_* environment variables.
+	ExporterOTLP = "otlp"
+	// ExporterConsole writes traces to stdout. It is useful for debugging.
+	ExporterConsole = "console"
+)
+
+// ShutdownFunc flushes any pending spans and releases resources held by the tracer provider.
+type ShutdownFunc func(context.Context) error
+
+// Init sets up the global OpenTelemetry tracer provider and propagator.
+// The W3C trace context propagator is always installed so that trace context received from MCP clients
+// is forwarded to upstream MCP servers, even if exporting of traces is disabled.
+func Init(ctx context.Context, exporter string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	)
+
+	var (
+		spanExporter sdktrace.SpanExporter
+		err          error
+	)
+	switch exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case ExporterConsole:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf(
+			"unsupported trace exporter '%s' (acceptable values: '%s', '%s', '%s')",
+			exporter, ExporterNone, ExporterOTLP, ExporterConsole,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporter, err)
+	}
+
+	// resource.Default() picks up OTEL_SERVICE_NAME & OTEL_RESOURCE_ATTRIBUTES,
+	// so only set our service name if the user hasn't supplied one.
+	attrs := resource.Default()
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		attrs, err = resource.Merge(attrs, resource.NewSchemaless(semconv.ServiceName(ServiceName)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create trace resource: %w", err)
+		}
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(attrs),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Tracer returns the tracer used to create all mcpjungle spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// InjectTraceContext returns the trace context carried by ctx in its W3C text representation,
+// ie, the "traceparent" (and optionally "tracestate" & "baggage") entries.
+// The returned map can be used as HTTP headers or as MCP request _meta fields.
+func InjectTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	return carrier
+}
@@ -0,0 +1,29 @@
+package types
+
+import "time"
+
+// BackupHeader describes where a backup of the registry comes from.
+// It is stored at the top level of every backup.
+type BackupHeader struct {
+	// SchemaVersion is the version of the database schema of the server the backup was taken from.
+	// A backup can only be restored by a server whose database has the same schema version.
+	SchemaVersion int `json:"schema_version"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	// Mode is the mode of the server the backup was taken from.
+	// A backup can only be restored by a server running in the same mode.
+	Mode string `json:"mode"`
+}
+
+// RestoreResult contains the number of objects of each kind that were restored from a backup.
+type RestoreResult struct {
+	McpServers        int `json:"mcp_servers"`
+	Tools             int `json:"tools"`
+	ToolGroups        int `json:"tool_groups"`
+	McpClients        int `json:"mcp_clients"`
+	Users             int `json:"users"`
+	RateLimits        int `json:"rate_limits"`
+	ToolCachePolicies int `json:"tool_cache_policies"`
+	Webhooks          int `json:"webhooks"`
+}
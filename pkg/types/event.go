@@ -0,0 +1,78 @@
+package types
+
+import "time"
+
+// EventType identifies the kind of change that happened in mcpjungle.
+type EventType string
+
+const (
+	// EventServerRegistered is published when an MCP server is registered.
+	EventServerRegistered EventType = "server.registered"
+	// EventServerDeregistered is published when an MCP server is deregistered.
+	EventServerDeregistered EventType = "server.deregistered"
+	// EventServerUpdated is published when the configuration of an MCP server is updated.
+	EventServerUpdated EventType = "server.updated"
+	// EventServerUnhealthy is published when a health check of a healthy MCP server fails.
+	EventServerUnhealthy EventType = "server.unhealthy"
+	// EventServerRecovered is published when a health check of an unhealthy MCP server succeeds again.
+	EventServerRecovered EventType = "server.recovered"
+
+	// EventToolEnabled is published when one or more tools are enabled.
+	EventToolEnabled EventType = "tool.enabled"
+	// EventToolDisabled is published when one or more tools are disabled.
+	EventToolDisabled EventType = "tool.disabled"
+	// EventToolAdded is published when a tool becomes available in the MCP proxy,
+	// because its server was registered or it was (re-)enabled.
+	EventToolAdded EventType = "tool.added"
+	// EventToolRemoved is published when one or more tools disappear from the MCP proxy,
+	// because their server was deregistered or became unhealthy, or they were disabled.
+	EventToolRemoved EventType = "tool.removed"
+
+	// EventToolGroupCreated is published when a tool group is created.
+	EventToolGroupCreated EventType = "tool_group.created"
+	// EventToolGroupDeleted is published when a tool group is deleted.
+	EventToolGroupDeleted EventType = "tool_group.deleted"
+	// EventToolGroupUpdated is published when the description or tools of a tool group are updated.
+	EventToolGroupUpdated EventType = "tool_group.updated"
+
+	// EventQuotaExceeded is published when a tool call is rejected because a daily or monthly quota is exhausted.
+	// It is published once per quota and period, not for every rejected call.
+	EventQuotaExceeded EventType = "quota.exceeded"
+)
+
+// EventTypes lists all types of events published by mcpjungle.
+var EventTypes = []EventType{
+	EventServerRegistered,
+	EventServerDeregistered,
+	EventServerUpdated,
+	EventServerUnhealthy,
+	EventServerRecovered,
+	EventToolEnabled,
+	EventToolDisabled,
+	EventToolAdded,
+	EventToolRemoved,
+	EventToolGroupCreated,
+	EventToolGroupDeleted,
+	EventToolGroupUpdated,
+	EventQuotaExceeded,
+}
+
+// IsValidEventType returns true if t is a type of event published by mcpjungle.
+func IsValidEventType(t EventType) bool {
+	for _, et := range EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+// Event describes a change that happened in mcpjungle.
+type Event struct {
+	// ID uniquely identifies the event.
+	ID   string    `json:"id"`
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// Data holds the details of the event, eg- the name of the server for server events.
+	Data map[string]any `json:"data"`
+}
@@ -0,0 +1,112 @@
+package types
+
+// Manifest declares the desired state of the registry, so that it can be managed as code.
+// Applying a manifest creates the objects it declares, updates the ones that differ from it and,
+// if requested, deletes the objects it doesn't declare.
+type Manifest struct {
+	// Servers are the MCP servers to register, in the same format as the configuration file of `register`
+	Servers []ManifestServer `json:"servers,omitempty"`
+
+	// DisabledTools are the canonical names of the tools to disable.
+	// All other tools of the servers in the manifest are enabled.
+	// Tools of servers that are not in the manifest are left untouched.
+	DisabledTools []string `json:"disabled_tools,omitempty"`
+
+	ToolGroups []ToolGroup `json:"tool_groups,omitempty"`
+
+	// Clients are the MCP clients that may access the MCP proxy (production mode only)
+	Clients []McpClient `json:"clients,omitempty"`
+
+	// Users are the human users of mcpjungle (production mode only).
+	// Admin users can't be declared in a manifest and are never deleted by it.
+	Users []User `json:"users,omitempty"`
+}
+
+// KeepCurrentSecret replaces the secrets of MCP servers in a manifest exported without secrets.
+// When the manifest is applied, a bearer token or environment variable with this value keeps its current value,
+// so it can only be used for servers that are already registered.
+const KeepCurrentSecret = "<keep-current>"
+
+// ManifestServer declares an MCP server in a manifest.
+// It has the same fields as RegisterServerInput, so that they can be converted into each other,
+// but the fields that are not set are left out of the manifest.
+type ManifestServer struct {
+	Name        string `json:"name"`
+	Transport   string `json:"transport"`
+	Description string `json:"description,omitempty"`
+
+	URL string `json:"url,omitempty"`
+	// BearerToken may be KeepCurrentSecret
+	BearerToken string `json:"bearer_token,omitempty"`
+
+	Command string   `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+	// The values of Env may be KeepCurrentSecret
+	Env map[string]string `json:"env,omitempty"`
+
+	CallTimeout    string `json:"call_timeout,omitempty"`
+	MaxRetries     *int   `json:"max_retries,omitempty"`
+	RetryBackoff   string `json:"retry_backoff,omitempty"`
+	RetryOnTimeout *bool  `json:"retry_on_timeout,omitempty"`
+	MaxConcurrency *int   `json:"max_concurrency,omitempty"`
+	MaxQueue       *int   `json:"max_queue,omitempty"`
+	QueueTimeout   string `json:"queue_timeout,omitempty"`
+}
+
+// ReconcileAction describes what is done to an object to make it match a manifest.
+type ReconcileAction string
+
+const (
+	ReconcileCreate  ReconcileAction = "create"
+	ReconcileUpdate  ReconcileAction = "update"
+	ReconcileDelete  ReconcileAction = "delete"
+	ReconcileEnable  ReconcileAction = "enable"
+	ReconcileDisable ReconcileAction = "disable"
+)
+
+// ReconcileObjectKind is the kind of object changed to make the registry match a manifest.
+type ReconcileObjectKind string
+
+const (
+	ReconcileKindServer    ReconcileObjectKind = "server"
+	ReconcileKindTool      ReconcileObjectKind = "tool"
+	ReconcileKindToolGroup ReconcileObjectKind = "tool_group"
+	ReconcileKindClient    ReconcileObjectKind = "client"
+	ReconcileKindUser      ReconcileObjectKind = "user"
+)
+
+// ReconcileChange is a single change made (or, in a dry run, to be made) to the registry.
+type ReconcileChange struct {
+	Action ReconcileAction     `json:"action"`
+	Kind   ReconcileObjectKind `json:"kind"`
+	Name   string              `json:"name"`
+
+	// Details describes what is changed in an updated object, eg- "url, call_timeout"
+	Details string `json:"details,omitempty"`
+
+	// AccessToken is the access token of a created MCP client or user.
+	// It is only returned once, when the object is created.
+	AccessToken string `json:"access_token,omitempty"`
+
+	// Error is the reason why the change could not be applied, if it failed
+	Error string `json:"error,omitempty"`
+}
+
+// ReconcileResult is the outcome of applying a manifest.
+type ReconcileResult struct {
+	DryRun bool `json:"dry_run"`
+
+	// Changes are listed in the order they are applied
+	Changes []ReconcileChange `json:"changes"`
+}
+
+// Failed returns the number of changes that could not be applied.
+func (r *ReconcileResult) Failed() int {
+	n := 0
+	for _, c := range r.Changes {
+		if c.Error != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // McpServerTransport represents the transport protocol used by an MCP server.
 // All transport types supported by mcpjungle are defined in this file with this type.
@@ -22,10 +25,87 @@ type McpServer struct {
 	Command string            `json:"command"`
 	Args    []string          `json:"args"`
 	Env     map[string]string `json:"env"`
+
+	// The call settings below are only set if they override the global defaults.
+	CallTimeout    string `json:"call_timeout,omitempty"`
+	MaxRetries     *int   `json:"max_retries,omitempty"`
+	RetryBackoff   string `json:"retry_backoff,omitempty"`
+	RetryOnTimeout *bool  `json:"retry_on_timeout,omitempty"`
+	MaxConcurrency *int   `json:"max_concurrency,omitempty"`
+	MaxQueue       *int   `json:"max_queue,omitempty"`
+	QueueTimeout   string `json:"queue_timeout,omitempty"`
+
+	// Status describes the runtime health of the MCP server.
+	Status *McpServerStatus `json:"status,omitempty"`
+}
+
+// McpServerHealth describes the health of an MCP server as determined by the health checker.
+type McpServerHealth string
+
+const (
+	// ServerHealthUnknown means that the server has not been checked yet or health checks are disabled.
+	ServerHealthUnknown McpServerHealth = "unknown"
+	// ServerHealthHealthy means that the last health check of the server succeeded.
+	ServerHealthHealthy McpServerHealth = "healthy"
+	// ServerHealthUnhealthy means that the last health check of the server failed.
+	ServerHealthUnhealthy McpServerHealth = "unhealthy"
+)
+
+// CircuitBreakerState is the state of the circuit breaker that protects mcpjungle from a failing MCP server.
+type CircuitBreakerState string
+
+const (
+	// CircuitClosed means that tool calls are forwarded to the MCP server as usual.
+	CircuitClosed CircuitBreakerState = "closed"
+	// CircuitOpen means that too many recent tool calls to the MCP server have failed,
+	// so calls are rejected without contacting the server.
+	CircuitOpen CircuitBreakerState = "open"
+	// CircuitHalfOpen means that a single tool call is let through to check whether the MCP server has recovered.
+	CircuitHalfOpen CircuitBreakerState = "half_open"
+)
+
+// McpServerStatus contains the runtime status of an MCP server registered in mcpjungle.
+type McpServerStatus struct {
+	Health McpServerHealth `json:"health"`
+
+	// LastSuccess is the time of the last successful health check
+	LastSuccess *time.Time `json:"last_success,omitempty"`
+	// LastFailure is the time of the last failed health check
+	LastFailure *time.Time `json:"last_failure,omitempty"`
+	// LastError is the error that caused the last health check to fail
+	LastError string `json:"last_error,omitempty"`
+
+	// LatencyMs is the time taken by the last health check in milliseconds.
+	// It includes the time needed to establish a session with the server.
+	LatencyMs int64 `json:"latency_ms"`
+
+	// ConsecutiveFailures is the number of health checks that have failed in a row
+	ConsecutiveFailures int `json:"consecutive_failures"`
+
+	// ToolsDisabled is true if the server's tools have been automatically removed from the MCP proxy
+	// because the server failed too many consecutive health checks.
+	// They are restored as soon as the server recovers.
+	ToolsDisabled bool `json:"tools_disabled"`
+
+	// CircuitBreaker is the state of the server's circuit breaker.
+	// It is empty if circuit breakers are disabled.
+	CircuitBreaker CircuitBreakerState `json:"circuit_breaker,omitempty"`
+
+	// ActiveCalls and QueuedCalls are the number of tool calls in progress on the server and
+	// waiting for a free slot. They are only tracked if the server's concurrency is limited.
+	ActiveCalls int `json:"active_calls"`
+	QueuedCalls int `json:"queued_calls"`
+}
+
+// ServerLogEntry is a single line written to stderr by a stdio MCP server.
+type ServerLogEntry struct {
+	Time time.Time `json:"time"`
+	Line string    `json:"line"`
 }
 
 // RegisterServerInput is the input structure for registering a new MCP server with mcpjungle.
 // It is also the basis for the JSON configuration file used to register a new MCP server.
+// ManifestServer must have the same fields.
 type RegisterServerInput struct {
 	// Name is the unique name of an MCP server registered in mcpjungle
 	Name string `json:"name"`
@@ -56,6 +136,41 @@ type RegisterServerInput struct {
 	// Env is the set of environment variables to pass to the mcp server when the transport is "stdio".
 	// Both the key and value must be of type string.
 	Env map[string]string `json:"env"`
+
+	// CallTimeout is the maximum time allowed for a single attempt of a tool call on this server (eg- "30s").
+	// It overrides the global default configured on the mcpjungle server.
+	CallTimeout string `json:"call_timeout,omitempty"`
+
+	// MaxRetries is the number of times a tool call is retried if it fails due to a transport error,
+	// eg- the server could not be reached.
+	// A call is never retried if the server responds with an error or the tool itself returns an error result.
+	// It overrides the global default configured on the mcpjungle server.
+	MaxRetries *int `json:"max_retries,omitempty"`
+
+	// RetryBackoff is the delay before the first retry of a tool call (eg- "500ms").
+	// The delay doubles with every subsequent retry.
+	// It overrides the global default configured on the mcpjungle server.
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+
+	// RetryOnTimeout allows retrying tool calls on this server that timed out.
+	// Only enable it if the server's tools are safe to call more than once,
+	// because a tool may have done its job before the call timed out.
+	RetryOnTimeout *bool `json:"retry_on_timeout,omitempty"`
+
+	// MaxConcurrency is the maximum number of tool calls that can be in progress on this server at the same time.
+	// Further calls wait in a queue until a call finishes. 0 means unlimited.
+	// This is useful for stdio servers, because every tool call starts a new process of the server.
+	// It overrides the global default configured on the mcpjungle server.
+	MaxConcurrency *int `json:"max_concurrency,omitempty"`
+
+	// MaxQueue is the maximum number of tool calls that can wait for this server at the same time.
+	// Calls beyond that are rejected immediately.
+	// It overrides the global default configured on the mcpjungle server.
+	MaxQueue *int `json:"max_queue,omitempty"`
+
+	// QueueTimeout is the maximum time a tool call waits in the queue before it is rejected (eg- "30s").
+	// It overrides the global default configured on the mcpjungle server.
+	QueueTimeout string `json:"queue_timeout,omitempty"`
 }
 
 // ValidateTransport validates the input string and returns the corresponding model.McpServerTransport.
@@ -0,0 +1,37 @@
+package types
+
+// RateLimitScope is the kind of entity a rate limit applies to.
+type RateLimitScope string
+
+const (
+	// RateLimitScopeClient limits all tool calls made by an MCP client.
+	RateLimitScopeClient RateLimitScope = "client"
+	// RateLimitScopeServer limits all tool calls made to an MCP server, across all clients.
+	RateLimitScopeServer RateLimitScope = "server"
+	// RateLimitScopeTool limits all calls made to a single tool, across all clients.
+	RateLimitScopeTool RateLimitScope = "tool"
+)
+
+// RateLimit limits the number of tool calls made through the MCP proxy for a client, server or tool.
+// A zero value for any limit means that limit is not enforced.
+type RateLimit struct {
+	Scope RateLimitScope `json:"scope"`
+	// Target is the name of the MCP client, MCP server or tool (canonical name) the limit applies to.
+	Target string `json:"target"`
+
+	// RequestsPerMinute is the rate at which the token bucket is refilled.
+	RequestsPerMinute int `json:"requests_per_minute,omitempty"`
+	// Burst is the maximum number of calls that can be made at once.
+	// Defaults to RequestsPerMinute.
+	Burst int `json:"burst,omitempty"`
+
+	// DailyQuota is the maximum number of calls allowed per day (UTC).
+	DailyQuota int64 `json:"daily_quota,omitempty"`
+	// MonthlyQuota is the maximum number of calls allowed per calendar month (UTC).
+	MonthlyQuota int64 `json:"monthly_quota,omitempty"`
+
+	// DailyUsage and MonthlyUsage are the number of calls made in the current day and month.
+	// They are only populated in responses.
+	DailyUsage   int64 `json:"daily_usage,omitempty"`
+	MonthlyUsage int64 `json:"monthly_usage,omitempty"`
+}
@@ -0,0 +1,62 @@
+package types
+
+// ServerSettings are the settings of the mcpjungle server that can be changed while it is running.
+// Durations are in the format accepted by time.ParseDuration (eg- "30s", "1m").
+type ServerSettings struct {
+	// CallTimeout is the default maximum time allowed for a single attempt of a tool call
+	CallTimeout string `json:"call_timeout,omitempty"`
+
+	// MaxRetries is the default number of times a tool call is retried after a transport error
+	MaxRetries *int `json:"max_retries,omitempty"`
+
+	// RetryBackoff is the default delay before the first retry of a tool call
+	RetryBackoff string `json:"retry_backoff,omitempty"`
+
+	// MaxConcurrency is the default maximum number of tool calls in progress on an MCP server, 0 means unlimited
+	MaxConcurrency *int `json:"max_concurrency,omitempty"`
+
+	// MaxQueue is the default maximum number of tool calls waiting for a busy MCP server
+	MaxQueue *int `json:"max_queue,omitempty"`
+
+	// QueueTimeout is the default maximum time a tool call waits for a busy MCP server
+	QueueTimeout string `json:"queue_timeout,omitempty"`
+
+	// LogLevel is the minimum level of the server logs ("debug", "info", "warn" or "error")
+	LogLevel string `json:"log_level,omitempty"`
+}
+
+// UpdateServerSettingsInput is the input for changing the settings of a running mcpjungle server.
+// Settings that are set are changed, all others keep their current values.
+type UpdateServerSettingsInput struct {
+	ServerSettings
+
+	// Unset lists the settings to revert to the values given to the `start` command,
+	// named like their JSON fields, eg- "call_timeout"
+	Unset []string `json:"unset,omitempty"`
+}
+
+// ServerConfig is the configuration of a running mcpjungle server.
+type ServerConfig struct {
+	Mode string `json:"mode"`
+
+	// Settings are the settings currently in effect.
+	// MCP servers may override the default call settings with their own.
+	Settings ServerSettings `json:"settings"`
+}
+
+// SwitchToProdInput is the input for switching a server from development to production mode.
+type SwitchToProdInput struct {
+	// ConfirmationToken is the token printed to the logs of the server when it started in development mode
+	ConfirmationToken string `json:"confirmation_token"`
+
+	// McpClient is the name of an MCP client to create with access to all registered MCP servers (optional)
+	McpClient string `json:"mcp_client,omitempty"`
+}
+
+// SwitchToProdResponse contains the access tokens created when switching a server to production mode.
+type SwitchToProdResponse struct {
+	AdminAccessToken string `json:"admin_access_token"`
+
+	// McpClientAccessToken is only set if an MCP client was requested
+	McpClientAccessToken string `json:"mcp_client_access_token,omitempty"`
+}
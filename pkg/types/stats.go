@@ -0,0 +1,36 @@
+package types
+
+import "time"
+
+// StatsGrouping is the dimension by which tool call statistics are grouped.
+type StatsGrouping string
+
+const (
+	StatsByTool   StatsGrouping = "tool"
+	StatsByServer StatsGrouping = "server"
+	StatsByClient StatsGrouping = "client"
+	StatsByDay    StatsGrouping = "day"
+)
+
+// ToolCallStats summarizes the tool calls in a single group.
+type ToolCallStats struct {
+	// Key identifies the group, eg- the tool name when grouping by tool or the date (YYYY-MM-DD) when grouping by day.
+	Key string `json:"key"`
+
+	Calls  int64 `json:"calls"`
+	Errors int64 `json:"errors"`
+	// ErrorRate is the ratio of calls that did not succeed, between 0 and 1.
+	ErrorRate float64 `json:"error_rate"`
+
+	// P50Ms and P95Ms are estimates of the median and 95th percentile latencies of the calls in milliseconds.
+	P50Ms float64 `json:"p50_ms"`
+	P95Ms float64 `json:"p95_ms"`
+}
+
+// StatsResponse is the response of the tool call statistics API.
+type StatsResponse struct {
+	By StatsGrouping `json:"by"`
+	// Since is the start of the first day included in the statistics (UTC).
+	Since time.Time       `json:"since"`
+	Stats []ToolCallStats `json:"stats"`
+}
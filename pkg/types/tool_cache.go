@@ -0,0 +1,23 @@
+package types
+
+// ToolCachePolicy enables caching of the results of a tool.
+type ToolCachePolicy struct {
+	// Tool is the canonical name of the tool whose results are cached.
+	Tool string `json:"tool"`
+	// TTL is the duration for which a cached result is served (eg- "5m").
+	TTL string `json:"ttl"`
+	// MaxEntries is the maximum number of results cached for the tool.
+	MaxEntries int `json:"max_entries"`
+
+	// Entries, Hits and Misses describe the current state of the cache.
+	// They are only populated in responses.
+	Entries int    `json:"entries"`
+	Hits    uint64 `json:"hits"`
+	Misses  uint64 `json:"misses"`
+}
+
+// ClearToolCacheResponse is the response of the API that clears the cached results of a tool.
+type ClearToolCacheResponse struct {
+	// Cleared is the number of cached results that were removed.
+	Cleared int `json:"cleared"`
+}
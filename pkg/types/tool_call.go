@@ -0,0 +1,28 @@
+package types
+
+import "time"
+
+// ToolCallRecord is the record of a past call to a tool.
+type ToolCallRecord struct {
+	// ID identifies the call, it can be used to replay the call
+	ID   uint      `json:"id"`
+	Time time.Time `json:"time"`
+
+	// Tool is the canonical name of the tool that was called.
+	Tool string `json:"tool"`
+	// Client is the name of the MCP client that made the call.
+	Client string `json:"client"`
+
+	Arguments map[string]any `json:"arguments,omitempty"`
+	// Result is the result returned by the tool. It is nil if the call failed.
+	Result *ToolInvokeResult `json:"result,omitempty"`
+	// Error is the reason the call failed, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Outcome is the outcome of the call (eg- "success", "tool_error", "error")
+	Outcome    string `json:"outcome"`
+	DurationMs int64  `json:"duration_ms"`
+
+	// Truncated indicates that the arguments and/or the result were too large to be recorded.
+	Truncated bool `json:"truncated,omitempty"`
+}
@@ -0,0 +1,42 @@
+package types
+
+import "time"
+
+// Webhook is an HTTP endpoint that mcpjungle sends events to.
+type Webhook struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+	// Events are the types of events sent to the webhook. All events are sent if it is empty.
+	Events []EventType `json:"events,omitempty"`
+	// Secret is the key used to sign the payloads sent to the webhook.
+	// If it is not supplied when creating a webhook, one is generated.
+	// It is only returned once, when the webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookDeliveryStatus is the state of the delivery of an event to a webhook.
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending means that the event has not been delivered yet, but will be (re)tried.
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered means that the webhook accepted the event.
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed means that the event could not be delivered and won't be retried.
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery describes the delivery of a single event to a webhook.
+type WebhookDelivery struct {
+	ID        uint      `json:"id"`
+	Time      time.Time `json:"time"`
+	EventID   string    `json:"event_id"`
+	EventType EventType `json:"event_type"`
+
+	Status   WebhookDeliveryStatus `json:"status"`
+	Attempts int                   `json:"attempts"`
+	// StatusCode is the HTTP status code returned by the webhook for the last attempt.
+	StatusCode int `json:"status_code,omitempty"`
+	// Error describes why the last attempt failed, if it did.
+	Error string `json:"error,omitempty"`
+}